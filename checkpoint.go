@@ -0,0 +1,69 @@
+package genetics
+
+// Checkpoint objects contain the state of an evolution that can be resumed:
+// its generation, population, random source and adapted mutation rate.
+type Checkpoint struct {
+	Generation   int           `json:"generation" yaml:"generation"`
+	Population   Population    `json:"population" yaml:"population"`
+	Random       *RandomSource `json:"random,omitempty" yaml:"random,omitempty"`
+	MutationRate float64       `json:"mutation_rate,omitempty" yaml:"mutation_rate,omitempty"`
+}
+
+// MARK: Public methods
+
+// MarshalProto encodes the checkpoint as a `Checkpoint` protobuf message.
+func (c Checkpoint) MarshalProto() ([]byte, error) {
+	buffer := appendProtoVarint(nil, 1, uint64(c.Generation))
+
+	population, err := c.Population.appendProto(nil)
+	if err != nil {
+		return nil, err
+	}
+	buffer = appendProtoMessage(buffer, 2, population)
+
+	if c.Random != nil {
+		c.Random.mutex.Lock()
+		state := c.Random.state
+		c.Random.mutex.Unlock()
+		buffer = appendProtoMessage(buffer, 3, appendProtoVarint(nil, 1, state))
+	}
+
+	return appendProtoDouble(buffer, 4, c.MutationRate), nil
+}
+
+// UnmarshalProto decodes the checkpoint from a `Checkpoint` protobuf message.
+func (c *Checkpoint) UnmarshalProto(data []byte) error {
+	*c = Checkpoint{}
+	d := &protoDecoder{data: data}
+	for d.err == nil && len(d.data) > 0 {
+		field, wire := d.tag()
+		switch field {
+		case 1:
+			c.Generation = int(d.varint(wire))
+		case 2:
+			message := d.bytes(wire)
+			if d.err == nil {
+				if err := c.Population.UnmarshalProto(message); err != nil {
+					return err
+				}
+			}
+		case 3:
+			c.Random = &RandomSource{}
+			m := d.message(wire)
+			for m.err == nil && len(m.data) > 0 {
+				field, wire := m.tag()
+				if field == 1 {
+					c.Random.state = m.varint(wire)
+				} else {
+					m.skip(wire)
+				}
+			}
+			d.finish(m)
+		case 4:
+			c.MutationRate = d.double(wire)
+		default:
+			d.skip(wire)
+		}
+	}
+	return d.err
+}
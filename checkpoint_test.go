@@ -0,0 +1,66 @@
+package genetics
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// resumeConfiguration returns the configuration of the evolutions that are
+// interrupted and resumed.
+func resumeConfiguration() *EvolverConfiguration {
+	configuration := DefaultEvolverConfiguration()
+	configuration.AdaptiveMutationRate = true
+	return configuration
+}
+
+func TestResumedEvolutionMatchesUninterruptedEvolution(t *testing.T) {
+	defer SetRandomSource(nil)
+
+	SetRandomSource(NewRandomSource(1))
+	uninterrupted := NewOptimizer(NewEvolver(resumeConfiguration(), dryRunFitness), dryRunPopulation(), 20)
+	if _, err := uninterrupted.Optimize(); err != nil {
+		t.Fatal(err)
+	}
+	expected := uninterrupted.Checkpoint()
+
+	// Interrupt an identical evolution halfway through, and encode its
+	// checkpoint as it would be written to disk.
+	SetRandomSource(NewRandomSource(1))
+	interrupted := NewOptimizer(NewEvolver(resumeConfiguration(), dryRunFitness), dryRunPopulation(), 10)
+	if _, err := interrupted.Optimize(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(interrupted.Checkpoint())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		t.Fatal(err)
+	}
+
+	// Resume the evolution from the checkpoint in a new evolver.
+	SetRandomSource(checkpoint.Random)
+	resumed := NewOptimizer(NewEvolver(resumeConfiguration(), dryRunFitness), checkpoint.Population, 10)
+	resumed.SetMutationRate(checkpoint.MutationRate)
+	if _, err := resumed.Optimize(); err != nil {
+		t.Fatal(err)
+	}
+	actual := resumed.Checkpoint()
+
+	if actual.MutationRate != expected.MutationRate {
+		t.Errorf("the resumed mutation rate is %f, expected %f", actual.MutationRate, expected.MutationRate)
+	}
+	if actual.Random.state != expected.Random.state {
+		t.Errorf("the resumed random source's state is %x, expected %x", actual.Random.state, expected.Random.state)
+	}
+	for i, c := range actual.Population {
+		e := expected.Population[i]
+		if c.Fitness != e.Fitness || !floats.Equal(c.Genes, e.Genes) {
+			t.Errorf("chromosome %d of the resumed population is %v with fitness %f, expected %v with fitness %f", i, c.Genes, c.Fitness, e.Genes, e.Fitness)
+		}
+	}
+}
@@ -1,9 +1,46 @@
 package genetics
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// FNV-1a parameters used to hash genes.
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// lastChromosomeID is the most recently assigned chromosome identifier.
+var lastChromosomeID uint64
+
+// chromosomeIDs objects assign unique chromosome identifiers. A nil allocator
+// assigns the package's identifiers, which are unique within the process.
+type chromosomeIDs struct {
+	last uint64
+}
+
+// chromosomePool contains the chromosomes of previous generations that can be
+// reused to breed new chromosomes.
+var chromosomePool = sync.Pool{
+	New: func() interface{} {
+		return &Chromosome{}
+	},
+}
 
 // Chromosome object contain an array of genes and a fitness value.
 type Chromosome struct {
+	// The chromosome's unique identifier. Identifiers are assigned when
+	// chromosomes are generated or bred, and are kept by clones. Chromosomes
+	// created without an identifier are assigned one when evolution begins.
+	ID uint64 `json:",omitempty" yaml:",omitempty"`
+
 	// The chromosome's genes.
 	Genes []float64
 
@@ -15,8 +52,319 @@ type Chromosome struct {
 	// selection from the population.
 	Fitness float64
 
+	// The named components of the chromosome's fitness, such as the profit,
+	// drawdown and trade count of a trading strategy. Components describe
+	// fitness but don't affect it, so selection uses `Fitness` unless a
+	// comparator orders chromosomes by their components. See
+	// `NewComponentFitnessFunction`.
+	Components map[string]float64 `json:",omitempty" yaml:",omitempty"`
+
+	// How long the fitness function took to evaluate the chromosome, including
+	// retries of invalid fitness values. Useful for finding genomes that are
+	// pathologically slow to evaluate.
+	EvaluationDuration time.Duration `json:",omitempty" yaml:",omitempty"`
+
+	// The number of generations since the chromosome's oldest ancestor was
+	// generated. Bred chromosomes inherit the age of their oldest parent.
+	Age int `json:",omitempty" yaml:",omitempty"`
+
+	// The value of each objective of a multi-objective fitness function. Every
+	// objective is maximized. See `NewMultiObjectiveFitnessFunction`.
+	Objectives []float64 `json:",omitempty" yaml:",omitempty"`
+
+	// The total violation of the constraints of a constrained multi-objective
+	// problem. Chromosomes with zero violation are feasible.
+	Violation float64 `json:",omitempty" yaml:",omitempty"`
+
+	// The error of the chromosome on each fitness case, where lower errors are
+	// better. See `NewCaseFitnessFunction`.
+	CaseErrors []float64 `json:",omitempty" yaml:",omitempty"`
+
+	// A descriptor of the chromosome's behavior and its novelty, measured by an
+	// evolver's novelty search. See `NoveltySearch`.
+	Behavior []float64 `json:",omitempty" yaml:",omitempty"`
+	Novelty  float64   `json:",omitempty" yaml:",omitempty"`
+
+	// Optional user data attached to the chromosome, such as its decoded
+	// phenotype or provenance. Metadata is copied when the chromosome is cloned
+	// or seeded, and is serialized with the chromosome, but isn't inherited by
+	// children.
+	Metadata map[string]interface{} `json:",omitempty" yaml:",omitempty"`
+
+	// The schema that names the chromosome's genes. See `GeneSchema`.
+	schema *GeneSchema
+
 	// The weight of the chromosome. Internal use only.
 	weight float64
+
+	// Whether or not the chromosome's fitness has been calculated. Internal use
+	// only.
+	evaluated bool
+
+	// Whether or not the chromosome's fitness was predicted by a surrogate model.
+	// Internal use only.
+	estimated bool
+
+	// Whether or not the evaluation of the chromosome's fitness panicked.
+	// Internal use only.
+	panicked bool
+
+	// How the chromosome was bred. Internal use only.
+	bred breedingRecord
+
+	// The fitness of the chromosome before it was penalized by a penalized
+	// fitness function, and the function's penalizer. Internal use only.
+	unpenalized float64
+	penalizer   penalizer
+}
+
+// MARK: Public methods
+
+// Clone returns a deep copy of the chromosome.
+func (c Chromosome) Clone() *Chromosome {
+	clone := c
+	clone.Genes = make([]float64, len(c.Genes))
+	copy(clone.Genes, c.Genes)
+	clone.Components = copyComponents(c.Components)
+	clone.Objectives = copyValues(c.Objectives)
+	clone.CaseErrors = copyValues(c.CaseErrors)
+	clone.Behavior = copyValues(c.Behavior)
+	clone.Metadata = copyMetadata(c.Metadata)
+	clone.bred = breedingRecord{}
+	return &clone
+}
+
+// Distance returns the euclidean distance between the genes of the chromosome
+// and another chromosome.
+func (c Chromosome) Distance(other *Chromosome) float64 {
+	n := len(c.Genes)
+	if len(other.Genes) < n {
+		n = len(other.Genes)
+	}
+	return floats.Distance(c.Genes[:n], other.Genes[:n], 2.0)
+}
+
+// Schema returns the schema that names the chromosome's genes, or nil if the
+// chromosome doesn't have one.
+func (c Chromosome) Schema() *GeneSchema {
+	return c.schema
+}
+
+// SetSchema sets the schema that names the chromosome's genes. Evolvers set the
+// schema of the chromosomes that they evaluate to their configuration's
+// `Schema`.
+func (c *Chromosome) SetSchema(schema *GeneSchema) {
+	c.schema = schema
+}
+
+// Get returns the value of the named gene. It panics if the chromosome doesn't
+// have a schema or the gene, so use `Lookup` when the name may be invalid.
+func (c Chromosome) Get(name string) float64 {
+	if c.schema == nil {
+		panic(fmt.Sprintf("genetics: unable to get gene %q of a chromosome without a schema", name))
+	}
+
+	value, ok := c.schema.Lookup(&c, name)
+	if !ok {
+		panic(fmt.Sprintf("genetics: unknown gene %q", name))
+	}
+	return value
+}
+
+// Lookup returns the value of the named gene, and whether or not the chromosome
+// has a schema and the gene.
+func (c Chromosome) Lookup(name string) (float64, bool) {
+	if c.schema == nil {
+		return 0.0, false
+	}
+	return c.schema.Lookup(&c, name)
+}
+
+// DecodeInto sets the fields of the struct pointed to by `v` to the values of
+// the chromosome's named genes. See `GeneSchema.DecodeInto`.
+func (c Chromosome) DecodeInto(v interface{}) error {
+	if c.schema == nil {
+		return errors.New("genetics: unable to decode a chromosome without a schema")
+	}
+	return c.schema.DecodeInto(&c, v)
+}
+
+// Estimated returns whether or not the chromosome's fitness was predicted by
+// an evolver's surrogate model, or assigned because the evolver's evaluation
+// budget was spent, rather than evaluated by its fitness function.
+func (c Chromosome) Estimated() bool {
+	return c.estimated
+}
+
+// Panicked returns whether or not the fitness function panicked while
+// evaluating the chromosome. See `PanicPolicyContinue`.
+func (c Chromosome) Panicked() bool {
+	return c.panicked
+}
+
+// Hash returns a 64-bit FNV-1a hash of the chromosome's genes. The hash is
+// computed from a canonical little-endian encoding of the genes in which
+// negative zero is encoded as zero and every NaN is encoded alike, so it depends
+// only on the genes' values and is the same on every platform and in every
+// process. Chromosomes with equal genes have equal hashes.
+func (c Chromosome) Hash() uint64 {
+	hash := uint64(fnvOffset64)
+	for _, g := range c.Genes {
+		bits := canonicalBits(g)
+		for i := uint(0); i < 64; i += 8 {
+			hash ^= (bits >> i) & 0xff
+			hash *= fnvPrime64
+		}
+	}
+	return hash
+}
+
+// MARK: Private methods
+
+// fitterThan returns whether or not the chromosome should be ordered after the
+// other chromosome when sorting by fitness. NaN fitness is lower than every
+// other fitness, and chromosomes with equal fitness are ordered by the hashes
+// of their genes so that the order doesn't depend on their previous positions.
+func (c Chromosome) fitterThan(other *Chromosome) bool {
+	a, b := c.Fitness, other.Fitness
+	switch {
+	case math.IsNaN(a) || math.IsNaN(b):
+		if math.IsNaN(a) != math.IsNaN(b) {
+			return math.IsNaN(b)
+		}
+	case a != b:
+		return a > b
+	}
+	return c.Hash() > other.Hash()
+}
+
+// next returns a new identifier.
+func (a *chromosomeIDs) next() uint64 {
+	if a == nil {
+		return nextChromosomeID()
+	}
+	return atomic.AddUint64(&a.last, 1)
+}
+
+// reserve ensures that identifiers assigned in the future are greater than the
+// given identifier.
+func (a *chromosomeIDs) reserve(id uint64) {
+	if a == nil {
+		reserveChromosomeID(id)
+	} else if id > atomic.LoadUint64(&a.last) {
+		atomic.StoreUint64(&a.last, id)
+	}
+}
+
+// MARK: Private functions
+
+// newChromosome returns an unevaluated chromosome with `length` genes. If
+// `reuse` is true, then the chromosome may be one previously released with
+// `releaseChromosome` and its genes aren't zeroed. Its identifier is assigned
+// by the allocator.
+func newChromosome(length int, reuse bool, ids *chromosomeIDs) *Chromosome {
+	if !reuse {
+		return &Chromosome{
+			ID:    ids.next(),
+			Genes: make([]float64, length),
+		}
+	}
+
+	c := chromosomePool.Get().(*Chromosome)
+	if cap(c.Genes) < length {
+		c.Genes = make([]float64, length)
+	}
+	c.Genes = c.Genes[:length]
+	c.Fitness = 0.0
+	c.EvaluationDuration = 0
+	c.Age = 0
+	c.weight = 0.0
+	c.evaluated = false
+	c.estimated = false
+	c.panicked = false
+	c.Components = nil
+	c.Objectives = c.Objectives[:0]
+	c.Violation = 0.0
+	c.CaseErrors = c.CaseErrors[:0]
+	c.Behavior = c.Behavior[:0]
+	c.Novelty = 0.0
+	c.Metadata = nil
+	c.schema = nil
+	c.penalizer = nil
+	c.ID = ids.next()
+	return c
+}
+
+// canonicalBits returns the canonical binary encoding of a gene.
+func canonicalBits(g float64) uint64 {
+	switch {
+	case g == 0.0:
+		return 0
+	case math.IsNaN(g):
+		return math.Float64bits(math.NaN())
+	default:
+		return math.Float64bits(g)
+	}
+}
+
+// nextChromosomeID returns a new unique chromosome identifier.
+func nextChromosomeID() uint64 {
+	return atomic.AddUint64(&lastChromosomeID, 1)
+}
+
+// reserveChromosomeID ensures that identifiers assigned in the future are
+// greater than the given identifier, such as one restored from a checkpoint.
+func reserveChromosomeID(id uint64) {
+	for {
+		last := atomic.LoadUint64(&lastChromosomeID)
+		if id <= last || atomic.CompareAndSwapUint64(&lastChromosomeID, last, id) {
+			return
+		}
+	}
+}
+
+// copyMetadata returns a shallow copy of the metadata, or nil if it's empty.
+func copyMetadata(metadata map[string]interface{}) map[string]interface{} {
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	copied := make(map[string]interface{}, len(metadata))
+	for k, v := range metadata {
+		copied[k] = v
+	}
+	return copied
+}
+
+// copyComponents returns a copy of the fitness components, or nil if there are
+// none.
+func copyComponents(components map[string]float64) map[string]float64 {
+	if len(components) == 0 {
+		return nil
+	}
+
+	copied := make(map[string]float64, len(components))
+	for k, v := range components {
+		copied[k] = v
+	}
+	return copied
+}
+
+// copyValues returns a copy of the values, or nil if there are none.
+func copyValues(values []float64) []float64 {
+	if len(values) == 0 {
+		return nil
+	}
+
+	copied := make([]float64, len(values))
+	copy(copied, values)
+	return copied
+}
+
+// releaseChromosome makes the chromosome available to be reused by
+// `newChromosome`.
+func releaseChromosome(c *Chromosome) {
+	chromosomePool.Put(c)
 }
 
 // MARK: String methods
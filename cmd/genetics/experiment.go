@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"plugin"
+
+	genetics "github.com/colinc86/go-genetics"
+	"gopkg.in/yaml.v2"
+)
+
+// Experiment objects define an evolution to run. Since YAML is a superset of
+// JSON, experiment files may be written in either format.
+type Experiment struct {
+	PopulationSize   uint                           `yaml:"population_size"`
+	ChromosomeLength uint                           `yaml:"chromosome_length"`
+	Configuration    *genetics.EvolverConfiguration `yaml:"configuration"`
+
+	Termination        Termination `yaml:"termination"`
+	Fitness            Fitness     `yaml:"fitness"`
+	Output             string      `yaml:"output"`
+	CheckpointInterval int         `yaml:"checkpoint_interval"`
+	Seed               int64       `yaml:"seed"`
+}
+
+// Termination objects define when an evolution stops.
+type Termination struct {
+	// The maximum number of generations to evolve.
+	Generations int `yaml:"generations"`
+
+	// An optional fitness that stops the evolution once reached.
+	TargetFitness *float64 `yaml:"target_fitness"`
+}
+
+// Fitness objects define the fitness function of an experiment as either a
+// built-in benchmark or a symbol exported by a Go plugin.
+type Fitness struct {
+	Benchmark string `yaml:"benchmark"`
+	Plugin    string `yaml:"plugin"`
+	Symbol    string `yaml:"symbol"`
+}
+
+// MARK: Constructors
+
+// loadExperiment reads an experiment from the file at the given path and
+// applies default values.
+//
+// If the experiment's fitness function is provided by a plugin, then the plugin
+// is opened before the experiment's configuration is parsed so that operators
+// it registers in its init functions can be referenced by name.
+func loadExperiment(path string) (*Experiment, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fitness := struct {
+		Fitness Fitness `yaml:"fitness"`
+	}{}
+	if err = yaml.Unmarshal(data, &fitness); err != nil {
+		return nil, err
+	}
+	if fitness.Fitness.Plugin != "" {
+		if _, err = plugin.Open(fitness.Fitness.Plugin); err != nil {
+			return nil, err
+		}
+	}
+
+	experiment := &Experiment{
+		PopulationSize: 100,
+		Configuration:  genetics.DefaultEvolverConfiguration(),
+		Termination:    Termination{Generations: 100},
+		Fitness:        Fitness{Symbol: "Fitness"},
+		Output:         "results",
+	}
+
+	if err = yaml.UnmarshalStrict(data, experiment); err != nil {
+		return nil, err
+	}
+
+	return experiment, experiment.validate()
+}
+
+// MARK: Private methods
+
+// validate returns an error describing the first invalid value of the
+// experiment.
+func (x Experiment) validate() error {
+	if x.PopulationSize == 0 {
+		return fmt.Errorf("population_size must be greater than zero")
+	}
+	if x.ChromosomeLength == 0 {
+		return fmt.Errorf("chromosome_length must be greater than zero")
+	}
+	if x.Configuration.EliteCount(int(x.PopulationSize)) > int(x.PopulationSize) {
+		return fmt.Errorf("elitism must be less than or equal to population_size")
+	}
+	if x.Fitness.Benchmark == "" && x.Fitness.Plugin == "" {
+		return fmt.Errorf("fitness requires a benchmark or plugin")
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"plugin"
+
+	genetics "github.com/colinc86/go-genetics"
+)
+
+// benchmarks maps the names of built-in benchmark functions to their
+// implementations. Benchmarks are minimization problems, so fitness is the
+// negated value of the benchmark.
+var benchmarks = map[string]func(x []float64) float64{
+	"sphere":     sphere,
+	"rastrigin":  rastrigin,
+	"rosenbrock": rosenbrock,
+	"ackley":     ackley,
+}
+
+// MARK: Private functions
+
+// fitnessFunction returns the fitness function defined by the experiment.
+func fitnessFunction(f Fitness) (genetics.FitnessFunction, error) {
+	if f.Plugin != "" {
+		return pluginFitnessFunction(f.Plugin, f.Symbol)
+	}
+
+	benchmark, ok := benchmarks[f.Benchmark]
+	if !ok {
+		return nil, fmt.Errorf("unknown benchmark %q", f.Benchmark)
+	}
+
+	return func(chromosome *genetics.Chromosome, state *genetics.EvolutionState) float64 {
+		return -benchmark(chromosome.Genes)
+	}, nil
+}
+
+// pluginFitnessFunction loads a fitness function exported by the Go plugin at
+// the given path.
+func pluginFitnessFunction(path string, symbol string) (genetics.FitnessFunction, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := p.Lookup(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	switch f := s.(type) {
+	case func(*genetics.Chromosome, *genetics.EvolutionState) float64:
+		return f, nil
+	case *genetics.FitnessFunction:
+		return *f, nil
+	default:
+		return nil, fmt.Errorf("plugin symbol %q is not a fitness function", symbol)
+	}
+}
+
+// sphere implements the sphere function.
+func sphere(x []float64) float64 {
+	sum := 0.0
+	for _, v := range x {
+		sum += v * v
+	}
+	return sum
+}
+
+// rastrigin implements the Rastrigin function.
+func rastrigin(x []float64) float64 {
+	sum := 10.0 * float64(len(x))
+	for _, v := range x {
+		sum += v*v - 10.0*math.Cos(2.0*math.Pi*v)
+	}
+	return sum
+}
+
+// rosenbrock implements the Rosenbrock function.
+func rosenbrock(x []float64) float64 {
+	sum := 0.0
+	for i := 0; i < len(x)-1; i++ {
+		sum += 100.0*math.Pow(x[i+1]-x[i]*x[i], 2.0) + math.Pow(1.0-x[i], 2.0)
+	}
+	return sum
+}
+
+// ackley implements the Ackley function.
+func ackley(x []float64) float64 {
+	if len(x) == 0 {
+		return 0.0
+	}
+
+	squares := 0.0
+	cosines := 0.0
+	for _, v := range x {
+		squares += v * v
+		cosines += math.Cos(2.0 * math.Pi * v)
+	}
+
+	n := float64(len(x))
+	return -20.0*math.Exp(-0.2*math.Sqrt(squares/n)) - math.Exp(cosines/n) + 20.0 + math.E
+}
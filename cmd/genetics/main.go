@@ -0,0 +1,183 @@
+// Command genetics runs an evolution defined by an experiment file and writes
+// its results to an output directory.
+//
+// Usage:
+//
+//	genetics [-resume checkpoint.json] [-dashboard] experiment.yaml
+//
+// The output directory contains the statistics of each generation in
+// `stats.csv`, the best chromosome in `best.json` and the most recent
+// population in `checkpoint.json`. Checkpoints also contain the state of the
+// random number generator and the mutation rate, so a resumed evolution
+// continues exactly as it would have without interruption. Interrupting the
+// command finishes the current generation and writes a final checkpoint. The
+// -dashboard flag shows the progress of the evolution in the terminal.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	genetics "github.com/colinc86/go-genetics"
+	"github.com/colinc86/go-genetics/dashboard"
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+	resume := flag.String("resume", "", "path to a checkpoint to resume evolution from")
+	showDashboard := flag.Bool("dashboard", false, "show a live dashboard of the evolution's progress")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-resume checkpoint.json] [-dashboard] experiment.yaml\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(flag.Arg(0), *resume, *showDashboard); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// run runs the experiment at the given path, optionally resuming from a
+// checkpoint and showing a dashboard of its progress.
+func run(experimentPath string, checkpointPath string, showDashboard bool) error {
+	experiment, err := loadExperiment(experimentPath)
+	if err != nil {
+		return fmt.Errorf("unable to load experiment: %s", err)
+	}
+
+	evolver, err := newEvolver(experiment)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(experiment.Output, 0755); err != nil {
+		return err
+	}
+
+	statsFile, err := os.Create(filepath.Join(experiment.Output, "stats.csv"))
+	if err != nil {
+		return err
+	}
+	defer statsFile.Close()
+	evolver.StatsExporter = genetics.NewStatsExporter(statsFile, genetics.StatsFormatCSV)
+	stop, cancel := genetics.StopOnSignal(os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	evolver.Stop = stop
+
+	checkpoint := genetics.Checkpoint{}
+	if checkpointPath != "" {
+		if checkpoint, err = readCheckpoint(checkpointPath); err != nil {
+			return fmt.Errorf("unable to read checkpoint: %s", err)
+		}
+	}
+
+	if checkpoint.Random == nil {
+		seed := experiment.Seed
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+		checkpoint.Random = genetics.NewRandomSource(seed)
+	}
+	genetics.SetRandomSource(checkpoint.Random)
+
+	if checkpoint.Population == nil {
+		checkpoint.Population = genetics.GeneratePopulation(experiment.PopulationSize, experiment.ChromosomeLength, func(i, j int) float64 {
+			bounds, ok := experiment.Configuration.BoundsForGene(j)
+			if !ok {
+				bounds = genetics.GeneBounds{Min: -1.0, Max: 1.0}
+			}
+			return bounds.Random()
+		})
+	}
+
+	start := checkpoint.Generation
+	generation := start
+	mutationRate := checkpoint.MutationRate
+
+	var board *dashboard.Dashboard
+	if showDashboard {
+		board = dashboard.New(os.Stderr, experiment.Termination.Generations-start)
+	}
+
+	population, best, evolveErr := evolver.Evolve(checkpoint.Population, func(state *genetics.EvolutionState) bool {
+		generation = start + state.Generation
+		if board != nil {
+			board.Update(state.Stats)
+		}
+		if state.Generation == 0 && checkpoint.MutationRate > 0.0 {
+			state.MutationRate = checkpoint.MutationRate
+		}
+		mutationRate = state.MutationRate
+
+		if experiment.CheckpointInterval > 0 && state.Generation > 0 && generation%experiment.CheckpointInterval == 0 {
+			if err := writeJSON(experiment.Output, "checkpoint.json", genetics.Checkpoint{Generation: generation, Population: state.Population, Random: checkpoint.Random, MutationRate: mutationRate}); err != nil {
+				log.Errorf("Unable to write checkpoint: %s", err)
+			}
+		}
+
+		if target := experiment.Termination.TargetFitness; target != nil && len(state.Population) > 0 {
+			if state.Population[len(state.Population)-1].Fitness >= *target {
+				return false
+			}
+		}
+		return generation < experiment.Termination.Generations
+	})
+
+	if err = writeJSON(experiment.Output, "checkpoint.json", genetics.Checkpoint{Generation: generation, Population: population, Random: checkpoint.Random, MutationRate: mutationRate}); err != nil {
+		return err
+	}
+
+	if evolveErr != nil {
+		return evolveErr
+	}
+
+	if best == nil {
+		return fmt.Errorf("the population is empty")
+	}
+
+	log.Infof("Best fitness %g after %d generations.", best.Fitness, generation)
+	return writeJSON(experiment.Output, "best.json", best)
+}
+
+// newEvolver creates an evolver from the experiment.
+func newEvolver(experiment *Experiment) (*genetics.Evolver, error) {
+	fitness, err := fitnessFunction(experiment.Fitness)
+	if err != nil {
+		return nil, err
+	}
+
+	return genetics.NewEvolver(experiment.Configuration, fitness), nil
+}
+
+// readCheckpoint reads the checkpoint at the given path.
+func readCheckpoint(path string) (genetics.Checkpoint, error) {
+	checkpoint := genetics.Checkpoint{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return checkpoint, err
+	}
+
+	err = json.Unmarshal(data, &checkpoint)
+	return checkpoint, err
+}
+
+// writeJSON writes the value as indented JSON to the named file in the output
+// directory.
+func writeJSON(output string, name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(output, name), data, 0644)
+}
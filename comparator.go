@@ -0,0 +1,156 @@
+package genetics
+
+import "math"
+
+// Comparator types order chromosomes by how fit they are. Set an evolver
+// configuration's `Comparator` to change how its population is sorted, which
+// chromosomes survive as elites and which win tournaments.
+type Comparator interface {
+	// Compare returns a negative number if chromosome `a` is less fit than
+	// chromosome `b`, a positive number if it's fitter, and zero if they're
+	// equally fit.
+	Compare(a, b *Chromosome) int
+}
+
+// ComparatorFunc types are functions that compare chromosomes.
+type ComparatorFunc func(a, b *Chromosome) int
+
+// epsilonComparator types compare chromosomes' fitness, treating fitnesses
+// that differ by at most epsilon as equal.
+type epsilonComparator struct {
+	epsilon float64
+}
+
+// lexicographicComparator types compare chromosomes' objectives in order.
+type lexicographicComparator struct {
+	epsilons []float64
+}
+
+// constraintComparator types prefer feasible chromosomes to infeasible ones.
+type constraintComparator struct {
+	base Comparator
+}
+
+// MARK: Public methods
+
+// Compare calls the function.
+func (f ComparatorFunc) Compare(a, b *Chromosome) int {
+	return f(a, b)
+}
+
+// Compare compares the chromosomes' fitness.
+func (c epsilonComparator) Compare(a, b *Chromosome) int {
+	return compareValues(a.Fitness, b.Fitness, c.epsilon)
+}
+
+// Compare compares the chromosomes' objectives in order, then their fitness.
+func (c lexicographicComparator) Compare(a, b *Chromosome) int {
+	n := len(a.Objectives)
+	if len(b.Objectives) > n {
+		n = len(b.Objectives)
+	}
+
+	for i := 0; i < n; i++ {
+		epsilon := 0.0
+		if i < len(c.epsilons) {
+			epsilon = c.epsilons[i]
+		}
+
+		if result := compareValues(objective(a, i), objective(b, i), epsilon); result != 0 {
+			return result
+		}
+	}
+	return compareValues(a.Fitness, b.Fitness, 0.0)
+}
+
+// Compare compares the chromosomes' feasibility, then compares them with the
+// base comparator.
+func (c constraintComparator) Compare(a, b *Chromosome) int {
+	switch {
+	case a.Violation <= 0.0 && b.Violation <= 0.0:
+		return c.base.Compare(a, b)
+	case a.Violation <= 0.0:
+		return 1
+	case b.Violation <= 0.0:
+		return -1
+	default:
+		return compareValues(b.Violation, a.Violation, 0.0)
+	}
+}
+
+// MARK: Public functions
+
+// FitnessComparator compares chromosomes' fitness. NaN fitness is lower than
+// every other fitness. It's the comparator used when a configuration doesn't
+// have one.
+var FitnessComparator Comparator = ComparatorFunc(func(a, b *Chromosome) int {
+	return compareValues(a.Fitness, b.Fitness, 0.0)
+})
+
+// NewEpsilonComparator returns a comparator that compares chromosomes'
+// fitness, treating fitnesses that differ by at most epsilon as equal, so that
+// insignificant differences, such as those due to noise, don't decide
+// tournaments or elitism.
+func NewEpsilonComparator(epsilon float64) Comparator {
+	return epsilonComparator{epsilon: epsilon}
+}
+
+// NewLexicographicComparator returns a comparator that compares chromosomes'
+// `Objectives` in order of priority, so that a later objective only decides
+// between chromosomes whose earlier objectives are equal. Objectives that
+// differ by at most the objective's epsilon are equal, and missing epsilons
+// are zero. Chromosomes whose objectives are all equal are compared by
+// fitness. Missing objectives are zero and NaN objectives are lower than every
+// other value.
+func NewLexicographicComparator(epsilons ...float64) Comparator {
+	return lexicographicComparator{epsilons: epsilons}
+}
+
+// NewConstraintComparator returns a comparator that prefers feasible
+// chromosomes, whose `Violation` is zero, to infeasible ones, and prefers lower
+// violations between infeasible chromosomes. Feasible chromosomes are compared
+// with the base comparator, or by fitness if it's nil.
+func NewConstraintComparator(base Comparator) Comparator {
+	if base == nil {
+		base = FitnessComparator
+	}
+	return constraintComparator{base: base}
+}
+
+// MARK: Private functions
+
+// compareValues compares two values that differ significantly if they differ
+// by more than epsilon. NaN is lower than every other value.
+func compareValues(a, b float64, epsilon float64) int {
+	switch {
+	case math.IsNaN(a) || math.IsNaN(b):
+		if math.IsNaN(a) == math.IsNaN(b) {
+			return 0
+		}
+		if math.IsNaN(a) {
+			return -1
+		}
+		return 1
+	case a == b || math.Abs(a-b) <= epsilon:
+		return 0
+	case a > b:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// fitter returns whether or not chromosome `a` should be ordered after
+// chromosome `b` when sorting with the comparator, or by fitness if the
+// comparator is nil. Chromosomes that compare equally are ordered by the hashes
+// of their genes so that the order doesn't depend on their previous positions.
+func fitter(a, b *Chromosome, comparator Comparator) bool {
+	if comparator == nil {
+		return a.fitterThan(b)
+	}
+
+	if result := comparator.Compare(a, b); result != 0 {
+		return result > 0
+	}
+	return a.Hash() > b.Hash()
+}
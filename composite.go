@@ -0,0 +1,296 @@
+package genetics
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ChromosomeSegment objects describe a named sub-chromosome of the chromosomes
+// of a composite schema, such as the weights of a model or the parameters of
+// one of its components.
+type ChromosomeSegment struct {
+	// The segment's unique name.
+	Name string
+
+	// The number of genes of the segment.
+	Length int
+
+	// Optional bounds of the segment's genes. Either contains the bounds of each
+	// gene, or a single bounds that applies to every gene. If empty, then the
+	// segment's genes are in [-1, 1].
+	Bounds []GeneBounds
+
+	// An optional crossover method that recombines the segment's genes. If nil,
+	// then the schema's fallback method is used.
+	CrossoverMethod *CrossoverMethod
+
+	// An optional mutation method that mutates the segment's genes. If nil, then
+	// the schema's fallback method is used.
+	MutationMethod *MutationMethod
+}
+
+// CompositeSchema objects describe chromosomes whose genomes are assembled
+// from named segments in order. Each segment has its own bounds and operators,
+// and crossover may recombine chromosomes at the level of whole segments.
+// Configure evolvers with the schema's `Configure` method.
+type CompositeSchema struct {
+	// The segments of the schema's chromosomes in the order that their genes
+	// appear.
+	Segments []ChromosomeSegment
+
+	// The probability that each segment of a child is recombined by its
+	// crossover method rather than inherited whole from one of the parents.
+	SegmentCrossoverRate float64
+}
+
+// MARK: Constructors
+
+// NewCompositeSchema creates and returns a new composite schema with the given
+// segments in which segments are recombined by their crossover methods half of
+// the time.
+func NewCompositeSchema(segments ...ChromosomeSegment) *CompositeSchema {
+	return &CompositeSchema{
+		Segments:             segments,
+		SegmentCrossoverRate: 0.5,
+	}
+}
+
+// MARK: Public methods
+
+// Validate returns an error if the schema's segments are invalid.
+func (s CompositeSchema) Validate() error {
+	if len(s.Segments) == 0 {
+		return errors.New("a composite schema must have at least one segment")
+	}
+	if s.SegmentCrossoverRate < 0.0 || s.SegmentCrossoverRate > 1.0 {
+		return fmt.Errorf("the segment crossover rate %f must be in [0, 1]", s.SegmentCrossoverRate)
+	}
+
+	names := make(map[string]bool)
+	for i, segment := range s.Segments {
+		if segment.Name == "" {
+			return fmt.Errorf("segment %d must have a name", i)
+		}
+		if names[segment.Name] {
+			return fmt.Errorf("the name %q of segment %d isn't unique", segment.Name, i)
+		}
+		names[segment.Name] = true
+
+		if segment.Length < 1 {
+			return fmt.Errorf("the length of segment %q must be positive", segment.Name)
+		}
+		if len(segment.Bounds) > 1 && len(segment.Bounds) != segment.Length {
+			return fmt.Errorf("segment %q must have a single bounds or bounds for each of its %d genes", segment.Name, segment.Length)
+		}
+		if m := segment.CrossoverMethod; m != nil && m.Function == nil && m.MultiParentFunction == nil && m.IntoFunction == nil {
+			return fmt.Errorf("the crossover method of segment %q must have a function", segment.Name)
+		}
+		if m := segment.MutationMethod; m != nil && m.Function == nil {
+			return fmt.Errorf("the mutation method of segment %q must have a function", segment.Name)
+		}
+	}
+	return nil
+}
+
+// GeneCount returns the number of genes of the schema's chromosomes.
+func (s CompositeSchema) GeneCount() int {
+	count := 0
+	for _, segment := range s.Segments {
+		count += segment.Length
+	}
+	return count
+}
+
+// Offset returns the index of the first gene of the named segment, and whether
+// or not the schema has the segment.
+func (s CompositeSchema) Offset(name string) (int, bool) {
+	offset := 0
+	for _, segment := range s.Segments {
+		if segment.Name == name {
+			return offset, true
+		}
+		offset += segment.Length
+	}
+	return 0, false
+}
+
+// Segment returns the genes of the named segment of the chromosome, or nil if
+// the schema doesn't have the segment. The returned slice shares the
+// chromosome's genes, so changes to it change the chromosome.
+func (s CompositeSchema) Segment(chromosome *Chromosome, name string) []float64 {
+	offset := 0
+	for _, segment := range s.Segments {
+		if segment.Name == name {
+			if offset+segment.Length > len(chromosome.Genes) {
+				return nil
+			}
+			return chromosome.Genes[offset : offset+segment.Length]
+		}
+		offset += segment.Length
+	}
+	return nil
+}
+
+// Decode returns a copy of the genes of each of the chromosome's segments by
+// name.
+func (s CompositeSchema) Decode(chromosome *Chromosome) map[string][]float64 {
+	segments := make(map[string][]float64, len(s.Segments))
+	for _, segment := range s.Segments {
+		if genes := s.Segment(chromosome, segment.Name); genes != nil {
+			segments[segment.Name] = copyValues(genes)
+		}
+	}
+	return segments
+}
+
+// Assemble returns a new chromosome assembled from the genes of each segment
+// by name. Segments whose genes aren't given are generated randomly within
+// their bounds.
+func (s CompositeSchema) Assemble(segments map[string][]float64) (*Chromosome, error) {
+	chromosome := s.randomChromosome()
+	for name, genes := range segments {
+		segment := s.Segment(chromosome, name)
+		if segment == nil {
+			return nil, fmt.Errorf("unknown segment %q", name)
+		}
+		if len(genes) != len(segment) {
+			return nil, fmt.Errorf("segment %q must have %d genes, but %d were given", name, len(segment), len(genes))
+		}
+		copy(segment, genes)
+	}
+	return chromosome, nil
+}
+
+// Bounds returns the bounds of each gene of the schema's chromosomes.
+func (s CompositeSchema) Bounds() []GeneBounds {
+	bounds := make([]GeneBounds, 0, s.GeneCount())
+	for _, segment := range s.Segments {
+		for i := 0; i < segment.Length; i++ {
+			bounds = append(bounds, segment.boundsForGene(i))
+		}
+	}
+	return bounds
+}
+
+// Population generates a population of chromosomes whose genes are uniformly
+// distributed within their bounds.
+func (s CompositeSchema) Population(populationSize uint) Population {
+	population := make(Population, populationSize)
+	for i := range population {
+		population[i] = s.randomChromosome()
+	}
+	return population
+}
+
+// CrossoverMethod returns a crossover method that breeds each segment of a
+// child separately. Each segment is recombined by its crossover method, or by
+// `fallback` if it doesn't have one, with a probability of the schema's
+// segment crossover rate, and is otherwise inherited whole from a randomly
+// chosen parent. If `fallback` is nil, then segments without a crossover
+// method are always inherited whole.
+func (s *CompositeSchema) CrossoverMethod(fallback *CrossoverMethod) *CrossoverMethod {
+	return NewCustomCrossoverMethod(func(cA *Chromosome, cB *Chromosome, options CrossoverOptions) *Chromosome {
+		rng := options.Source()
+		child := &Chromosome{Genes: copyValues(cA.Genes)}
+
+		offset := 0
+		for _, segment := range s.Segments {
+			end := offset + segment.Length
+			if end > len(child.Genes) || end > len(cB.Genes) {
+				break
+			}
+
+			method := segment.CrossoverMethod
+			if method == nil {
+				method = fallback
+			}
+
+			if method != nil && rng.Float64() < s.SegmentCrossoverRate {
+				parents := []*Chromosome{
+					{Genes: cA.Genes[offset:end]},
+					{Genes: cB.Genes[offset:end]},
+				}
+				method.CrossoverInto(child.Genes[offset:end], parents)
+			} else if rng.Float64() < 0.5 {
+				copy(child.Genes[offset:end], cB.Genes[offset:end])
+			}
+			offset = end
+		}
+		return child
+	}, CrossoverOptions{})
+}
+
+// MutationMethod returns a mutation method that mutates each gene with the
+// mutation method of its segment, or with `fallback` if the segment doesn't
+// have one. Segments' mutation methods see the segment as a chromosome bounded
+// by the segment's bounds. If `fallback` is nil, then genes of segments
+// without a mutation method aren't mutated.
+func (s *CompositeSchema) MutationMethod(fallback *MutationMethod) *MutationMethod {
+	return NewCustomMutationMethod(func(chromosome *Chromosome, i int, state *EvolutionState) float64 {
+		offset := 0
+		for _, segment := range s.Segments {
+			end := offset + segment.Length
+			if i >= end {
+				offset = end
+				continue
+			}
+
+			method := segment.MutationMethod
+			if method == nil {
+				method = fallback
+			}
+			if method == nil || method.Function == nil || end > len(chromosome.Genes) {
+				return chromosome.Genes[i]
+			}
+
+			configuration := *state.Configuration
+			configuration.Bounds = segment.Bounds
+			if len(configuration.Bounds) == 0 {
+				configuration.Bounds = []GeneBounds{segment.boundsForGene(0)}
+			}
+
+			segmentState := *state
+			segmentState.Configuration = &configuration
+			return method.Function(&Chromosome{Genes: chromosome.Genes[offset:end]}, i-offset, &segmentState)
+		}
+		return chromosome.Genes[i]
+	})
+}
+
+// Configure returns a copy of the configuration bounded by the schema, whose
+// crossover and mutation methods breed the schema's segments with their own
+// methods. The configuration's crossover and mutation methods are used by
+// segments that don't have their own.
+func (s *CompositeSchema) Configure(configuration *EvolverConfiguration) *EvolverConfiguration {
+	composite := *configuration
+	composite.Bounds = s.Bounds()
+	composite.CrossoverMethod = s.CrossoverMethod(configuration.CrossoverMethod)
+	composite.MutationMethod = s.MutationMethod(configuration.MutationMethod)
+	composite.CrossoverMethods = nil
+	composite.MutationMethods = nil
+	return &composite
+}
+
+// MARK: Private methods
+
+// randomChromosome returns a new chromosome whose genes are uniformly
+// distributed within their bounds.
+func (s CompositeSchema) randomChromosome() *Chromosome {
+	chromosome := newChromosome(s.GeneCount(), false, nil)
+	for i, bounds := range s.Bounds() {
+		chromosome.Genes[i] = bounds.Random()
+	}
+	return chromosome
+}
+
+// boundsForGene returns the bounds of the segment's gene at index `i`.
+func (s ChromosomeSegment) boundsForGene(i int) GeneBounds {
+	switch {
+	case len(s.Bounds) == 1:
+		return s.Bounds[0]
+	case i < len(s.Bounds):
+		return s.Bounds[i]
+	default:
+		return GeneBounds{Min: -1.0, Max: 1.0}
+	}
+}
@@ -0,0 +1,174 @@
+package genetics
+
+import "fmt"
+
+// CooperativeEvolver types evolve long chromosomes by cooperative
+// co-evolution. The chromosome is split in to components of consecutive genes
+// and each component is evolved in its own population. A component's
+// chromosomes are evaluated by assembling them with the best chromosome, or
+// representative, of every other component. Because representatives change as
+// the other components evolve, elites are evaluated again every cycle.
+type CooperativeEvolver struct {
+	// The configuration used to evolve each component. Bounds are applied to
+	// the genes of the assembled chromosome.
+	Configuration *EvolverConfiguration
+
+	// The fitness function that evaluates assembled chromosomes.
+	FitnessFunction FitnessFunction
+
+	// The number of genes in each component.
+	ComponentLengths []int
+}
+
+// CooperativeState objects describe the progress of a cooperative evolution.
+type CooperativeState struct {
+	// The number of cycles evolved. Each component evolves one generation per
+	// cycle.
+	Cycle int
+
+	// The assembled chromosome of the components' representatives.
+	Best *Chromosome
+
+	// The state of each component's evolution.
+	Components []*EvolutionState
+}
+
+// MARK: Constructors
+
+// NewCooperativeEvolver creates and returns a new cooperative evolver.
+func NewCooperativeEvolver(configuration *EvolverConfiguration, fitnessFunction FitnessFunction, componentLengths []int) *CooperativeEvolver {
+	return &CooperativeEvolver{
+		Configuration:    configuration,
+		FitnessFunction:  fitnessFunction,
+		ComponentLengths: componentLengths,
+	}
+}
+
+// MARK: Public methods
+
+// GeneratePopulations generates a population for each component. The
+// generating function is given the index of the chromosome and the index of
+// the gene in the assembled chromosome.
+func (c CooperativeEvolver) GeneratePopulations(populationSize uint, generatingFunction func(i, j int) float64) []Population {
+	populations := make([]Population, len(c.ComponentLengths))
+	offset := 0
+	for k, length := range c.ComponentLengths {
+		start := offset
+		populations[k] = GeneratePopulation(populationSize, uint(length), func(i, j int) float64 {
+			return generatingFunction(i, start+j)
+		})
+		offset += length
+	}
+	return populations
+}
+
+// Evolve evolves a population for each component in cycles until
+// `shouldContinue` returns false, and returns the assembled chromosome of the
+// components' representatives.
+func (c CooperativeEvolver) Evolve(populations []Population, shouldContinue func(state *CooperativeState) bool) (*Chromosome, error) {
+	if len(populations) != len(c.ComponentLengths) {
+		return nil, fmt.Errorf("expected %d populations but got %d", len(c.ComponentLengths), len(populations))
+	}
+
+	state := &CooperativeState{}
+	representatives := make([]*Chromosome, len(populations))
+	for k, population := range populations {
+		if len(population) == 0 {
+			return nil, fmt.Errorf("the population of component %d is empty", k)
+		}
+		representatives[k] = population[0]
+	}
+
+	evolvers := make([]*Evolver, len(populations))
+	for k := range populations {
+		evolvers[k] = c.componentEvolver(k, representatives)
+		evolvers[k].validate(populations[k])
+
+		population, componentState, err := evolvers[k].initialize(populations[k])
+		if err != nil {
+			return nil, err
+		}
+
+		populations[k] = population
+
+		state.Components = append(state.Components, componentState)
+		representatives[k] = populations[k][len(populations[k])-1].Clone()
+	}
+
+	state.Best = c.assemble(representatives, state.Components[len(state.Components)-1])
+	for shouldContinue(state) {
+		var err error
+		for k := range populations {
+			for _, chromosome := range populations[k] {
+				chromosome.evaluated = false
+			}
+
+			if populations[k], err = evolvers[k].evolveGeneration(populations[k], state.Components[k]); err != nil {
+				return state.Best, err
+			}
+			representatives[k] = populations[k][len(populations[k])-1].Clone()
+		}
+
+		state.Cycle++
+		state.Best = c.assemble(representatives, state.Components[len(state.Components)-1])
+	}
+
+	return state.Best, nil
+}
+
+// MARK: Private methods
+
+// componentEvolver returns an evolver for the component at index `k` that
+// evaluates its chromosomes by assembling them with the representatives of the
+// other components.
+func (c CooperativeEvolver) componentEvolver(k int, representatives []*Chromosome) *Evolver {
+	configuration := *c.Configuration
+	offset := c.componentOffset(k)
+	if len(configuration.Bounds) > 1 {
+		end := offset + c.ComponentLengths[k]
+		if end > len(configuration.Bounds) {
+			end = len(configuration.Bounds)
+		}
+		if offset < end {
+			configuration.Bounds = configuration.Bounds[offset:end]
+		} else {
+			configuration.Bounds = nil
+		}
+	}
+
+	assembled := &Chromosome{}
+	return NewEvolver(&configuration, func(chromosome *Chromosome, state *EvolutionState) float64 {
+		assembled.Genes = assembled.Genes[:0]
+		for i, r := range representatives {
+			if i == k {
+				assembled.Genes = append(assembled.Genes, chromosome.Genes...)
+			} else {
+				assembled.Genes = append(assembled.Genes, r.Genes...)
+			}
+		}
+		return c.FitnessFunction(assembled, state)
+	})
+}
+
+// componentOffset returns the index of the first gene of the component at
+// index `k` in the assembled chromosome.
+func (c CooperativeEvolver) componentOffset(k int) int {
+	offset := 0
+	for _, length := range c.ComponentLengths[:k] {
+		offset += length
+	}
+	return offset
+}
+
+// assemble returns the evaluated chromosome assembled from the
+// representatives.
+func (c CooperativeEvolver) assemble(representatives []*Chromosome, state *EvolutionState) *Chromosome {
+	assembled := &Chromosome{ID: nextChromosomeID()}
+	for _, r := range representatives {
+		assembled.Genes = append(assembled.Genes, r.Genes...)
+	}
+
+	assembled.Fitness = c.FitnessFunction(assembled, state)
+	assembled.evaluated = true
+	return assembled
+}
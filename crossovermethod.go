@@ -1,8 +1,13 @@
 package genetics
 
 import (
+	"fmt"
+	"math"
 	"math/rand"
-	"sort"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/gonum/floats"
 )
 
 // CrossoverMethodType represents a type of crossover method.
@@ -10,103 +15,539 @@ type CrossoverMethodType uint
 
 // Types of crossover methods.
 const (
-	CrossoverMethodTypePoint   CrossoverMethodType = 0
-	CrossoverMethodTypeUniform CrossoverMethodType = 1
-	CrossoverMethodTypeCustom  CrossoverMethodType = 2
+	CrossoverMethodTypePoint    CrossoverMethodType = 0
+	CrossoverMethodTypeUniform  CrossoverMethodType = 1
+	CrossoverMethodTypeCustom   CrossoverMethodType = 2
+	CrossoverMethodTypeMajority CrossoverMethodType = 3
+	CrossoverMethodTypeAverage  CrossoverMethodType = 4
+	CrossoverMethodTypeBlock    CrossoverMethodType = 5
+	CrossoverMethodTypeShuffle  CrossoverMethodType = 6
+	CrossoverMethodTypeSBX      CrossoverMethodType = 7
 )
 
 // CrossoverMethodFunction takes a pair of chromosomes and performs crossover
 // between them.
-type CrossoverMethodFunction func(cA *Chromosome, cB *Chromosome, count int) *Chromosome
+type CrossoverMethodFunction func(cA *Chromosome, cB *Chromosome, options CrossoverOptions) *Chromosome
+
+// MultiParentCrossoverFunction takes any number of chromosomes and performs
+// crossover between them.
+type MultiParentCrossoverFunction func(parents []*Chromosome, options CrossoverOptions) *Chromosome
+
+// CrossoverIntoFunction takes any number of chromosomes, performs crossover
+// between them and writes the child's genes in to the given slice, so that
+// children can be bred without allocating. Parents have at least as many genes
+// as the child.
+type CrossoverIntoFunction func(child []float64, parents []*Chromosome, options CrossoverOptions)
+
+// CrossoverOptions objects contain the parameters of crossover functions. Each
+// function uses the options that apply to it and ignores the rest.
+type CrossoverOptions struct {
+	// The number of crossover points of point and shuffle crossover.
+	Points int `json:"points,omitempty" yaml:"points,omitempty"`
+
+	// The fraction by which blending crossovers may extend beyond the range of
+	// the parents' genes.
+	Alpha float64 `json:"alpha,omitempty" yaml:"alpha,omitempty"`
+
+	// The distribution index of simulated binary crossovers. Larger indexes
+	// produce children closer to their parents.
+	Eta float64 `json:"eta,omitempty" yaml:"eta,omitempty"`
+
+	// The probability, in (0, 1], that uniform and block crossover inherit each
+	// gene, or block of genes, from the fitter parent. Zero means that the bias
+	// isn't set, and is treated as one half so that genes are equally likely
+	// to be inherited from either parent. Because zero isn't a probability,
+	// genes can't always be inherited from the less fit parent; use a small
+	// bias to favour it instead.
+	Bias float64 `json:"bias,omitempty" yaml:"bias,omitempty"`
+
+	// The number of contiguous genes in each block of block crossover. Values
+	// less than one are treated as one.
+	BlockLength int `json:"block_length,omitempty" yaml:"block_length,omitempty"`
+
+	// An optional source of random numbers. If nil, then the package's source
+	// is used. See `Source`.
+	Random *rand.Rand `json:"-" yaml:"-"`
+}
 
 // CrossoverMethod wraps a method type and function together.
 type CrossoverMethod struct {
-	Type     CrossoverMethodType
-	Function CrossoverMethodFunction
-	Count    int
+	Type                CrossoverMethodType
+	Function            CrossoverMethodFunction
+	MultiParentFunction MultiParentCrossoverFunction
+	Options             CrossoverOptions
+
+	// An optional function equivalent to `Function` or `MultiParentFunction`
+	// that writes children's genes in to a buffer. Evolvers use it in place of
+	// the method's other functions to avoid allocating each child. Methods of
+	// the package's crossover types have one.
+	IntoFunction CrossoverIntoFunction
+
+	// The number of parents selected for each crossover. Only used by methods
+	// with a `MultiParentFunction`, otherwise two parents are always selected.
+	Parents int
+
+	// The relative probability that the method is chosen when it is one of an
+	// evolver configuration's `CrossoverMethods`. If every method's weight is
+	// zero, then the methods are equally likely to be chosen.
+	Weight float64
+}
+
+// MARK: String methods
+
+func (t CrossoverMethodType) String() string {
+	switch t {
+	case CrossoverMethodTypePoint:
+		return "point"
+	case CrossoverMethodTypeUniform:
+		return "uniform"
+	case CrossoverMethodTypeMajority:
+		return "majority"
+	case CrossoverMethodTypeAverage:
+		return "average"
+	case CrossoverMethodTypeBlock:
+		return "block"
+	case CrossoverMethodTypeShuffle:
+		return "shuffle"
+	case CrossoverMethodTypeSBX:
+		return "sbx"
+	default:
+		return "custom"
+	}
 }
 
 // MARK: Constructors
 
 // NewCrossoverMethod creates a new crossover method from the given crossover
-// method type. To use a custom function, use the `NewCustomCrossoverMethod`
-// constructor.
-func NewCrossoverMethod(t CrossoverMethodType, count int) *CrossoverMethod {
+// method type and options. To use a custom function, use the
+// `NewCustomCrossoverMethod` constructor.
+func NewCrossoverMethod(t CrossoverMethodType, options CrossoverOptions) *CrossoverMethod {
 	return &CrossoverMethod{
-		Type:     t,
-		Function: crossoverFunctionForType(t),
-		Count:    count,
+		Type:         t,
+		Function:     crossoverFunctionForType(t),
+		Options:      options,
+		IntoFunction: crossoverIntoFunctionForType(t),
 	}
 }
 
 // NewCustomCrossoverMethod creates a new custom crossover method from the
-// provided crossover method function.
-func NewCustomCrossoverMethod(f CrossoverMethodFunction, count int) *CrossoverMethod {
+// provided crossover method function and options.
+func NewCustomCrossoverMethod(f CrossoverMethodFunction, options CrossoverOptions) *CrossoverMethod {
 	return &CrossoverMethod{
 		Type:     CrossoverMethodTypeCustom,
 		Function: f,
-		Count:    count,
+		Options:  options,
 	}
 }
 
-// MARK: Public functions
+// NewMultiParentCrossoverMethod creates a new crossover method from the given
+// crossover method type that selects `parents` parents for each crossover.
+// Two-parent method types may also be used, in which case `parents` is ignored.
+func NewMultiParentCrossoverMethod(t CrossoverMethodType, parents int, options CrossoverOptions) *CrossoverMethod {
+	return &CrossoverMethod{
+		Type:                t,
+		Function:            crossoverFunctionForType(t),
+		MultiParentFunction: multiParentCrossoverFunctionForType(t),
+		Options:             options,
+		Parents:             parents,
+		IntoFunction:        crossoverIntoFunctionForType(t),
+	}
+}
 
-// PointFunction implements the point crossover function.
-var PointFunction CrossoverMethodFunction = func(cA *Chromosome, cB *Chromosome, count int) *Chromosome {
-	var indexes []int
-	for i := 0; i < len(cA.Genes); i++ {
-		indexes = append(indexes, i+1)
+// NewCustomMultiParentCrossoverMethod creates a new custom crossover method
+// from the provided multi-parent crossover method function.
+func NewCustomMultiParentCrossoverMethod(f MultiParentCrossoverFunction, parents int, options CrossoverOptions) *CrossoverMethod {
+	return &CrossoverMethod{
+		Type:                CrossoverMethodTypeCustom,
+		MultiParentFunction: f,
+		Options:             options,
+		Parents:             parents,
 	}
+}
 
-	if len(indexes) > 1 {
-		for i := 0; i < len(indexes)-1; i++ {
-			j := rand.Intn(len(indexes)-i) + i
-			if i == j {
-				continue
-			}
-			indexes[i], indexes[j] = indexes[j], indexes[i]
+// ParseCrossoverMethod creates a new crossover method from a spec of the form
+// "name" or "name:parameter". Valid specs are "point", "N-point" or
+// "point:N" for N crossover points, "shuffle" or "shuffle:N" for N crossover
+// points, "uniform", "block" or "block:N" for blocks of N genes, "sbx" or
+// "sbx:N" for a distribution index of N, and "majority" or "average"
+// optionally followed by ":parents".
+//
+// The sbx method has a distribution index of 15 by default. The majority method
+// selects three parents by default, and the average method selects two.
+// Crossovers registered with `RegisterCrossover` and
+// `RegisterMultiParentCrossover` may also be given by name.
+func ParseCrossoverMethod(spec string) (*CrossoverMethod, error) {
+	name, parameter, err := parseMethodSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasSuffix(name, "-point") && parameter == 0 {
+		count, err := strconv.Atoi(strings.TrimSuffix(name, "-point"))
+		if err != nil || count < 1 {
+			return nil, fmt.Errorf("unknown crossover method %q", spec)
+		}
+		name, parameter = "point", count
+	}
+
+	t, ok := crossoverMethodTypeForName(name)
+	if !ok {
+		method, ok := registeredCrossover(name, parameter)
+		if !ok {
+			return nil, fmt.Errorf("unknown crossover method %q", spec)
+		}
+		return method, nil
+	}
+
+	switch t {
+	case CrossoverMethodTypePoint, CrossoverMethodTypeShuffle:
+		if parameter == 0 {
+			parameter = 1
+		}
+		return NewCrossoverMethod(t, CrossoverOptions{Points: parameter}), nil
+	case CrossoverMethodTypeBlock:
+		if parameter == 0 {
+			parameter = 1
 		}
+		return NewCrossoverMethod(t, CrossoverOptions{BlockLength: parameter}), nil
+	case CrossoverMethodTypeSBX:
+		if parameter == 0 {
+			parameter = 15
+		}
+		return NewCrossoverMethod(t, CrossoverOptions{Eta: float64(parameter)}), nil
+	case CrossoverMethodTypeMajority:
+		if parameter == 0 {
+			parameter = 3
+		}
+		return NewMultiParentCrossoverMethod(t, parameter, CrossoverOptions{}), nil
+	case CrossoverMethodTypeAverage:
+		if parameter == 0 {
+			parameter = 2
+		}
+		return NewMultiParentCrossoverMethod(t, parameter, CrossoverOptions{}), nil
+	default:
+		if parameter > 0 {
+			return nil, fmt.Errorf("crossover method %q doesn't take a parameter", name)
+		}
+		return NewCrossoverMethod(t, CrossoverOptions{}), nil
+	}
+}
+
+// MARK: Public methods
+
+// ParentCount returns the number of parents that should be selected for each
+// crossover performed by the method.
+func (m CrossoverMethod) ParentCount() int {
+	if m.MultiParentFunction == nil {
+		return 2
+	}
+	return m.Parents
+}
+
+// Crossover performs crossover between the given parents using the method's
+// function, or its into function if it has neither a function nor a
+// multi-parent function.
+func (m CrossoverMethod) Crossover(parents []*Chromosome) *Chromosome {
+	switch {
+	case m.MultiParentFunction != nil:
+		return m.MultiParentFunction(parents, m.Options)
+	case m.Function == nil && m.IntoFunction != nil:
+		child := &Chromosome{Genes: make([]float64, len(parents[0].Genes))}
+		m.IntoFunction(child.Genes, parents, m.Options)
+		return child
+	default:
+		return m.Function(parents[0], parents[1], m.Options)
+	}
+}
+
+// CrossoverInto performs crossover between the given parents and writes the
+// child's genes in to `child`, using the method's into function if it has one.
+// Otherwise the child is bred by the method's other functions and copied.
+func (m CrossoverMethod) CrossoverInto(child []float64, parents []*Chromosome) {
+	if m.IntoFunction != nil {
+		m.IntoFunction(child, parents, m.Options)
+		return
+	}
+	copy(child, m.Crossover(parents).Genes)
+}
+
+// Source returns the options' source of random numbers, or the package's
+// source if the options don't have one. Crossover functions should draw random
+// numbers from it.
+func (o CrossoverOptions) Source() *rand.Rand {
+	if o.Random != nil {
+		return o.Random
 	}
+	return random
+}
+
+// MARK: Public functions
+
+// PointFunction implements the point crossover function with `Points`
+// crossover points.
+var PointFunction CrossoverMethodFunction = func(cA *Chromosome, cB *Chromosome, options CrossoverOptions) *Chromosome {
+	child := &Chromosome{Genes: make([]float64, len(cA.Genes))}
+	PointIntoFunction(child.Genes, []*Chromosome{cA, cB}, options)
+	return child
+}
+
+// UniformFunction implements the uniform crossover function, inheriting each
+// gene from the fitter parent with a probability of `Bias`.
+var UniformFunction CrossoverMethodFunction = func(cA *Chromosome, cB *Chromosome, options CrossoverOptions) *Chromosome {
+	child := &Chromosome{Genes: make([]float64, len(cA.Genes))}
+	UniformIntoFunction(child.Genes, []*Chromosome{cA, cB}, options)
+	return child
+}
+
+// BlockFunction implements the block uniform crossover function. The child's
+// genes are divided in to contiguous blocks of `BlockLength` genes, and each
+// block is inherited whole from the fitter parent with a probability of
+// `Bias`, preserving the local structure of genomes whose neighbouring genes
+// are related, such as windows of a time series.
+var BlockFunction CrossoverMethodFunction = func(cA *Chromosome, cB *Chromosome, options CrossoverOptions) *Chromosome {
+	child := &Chromosome{Genes: make([]float64, len(cA.Genes))}
+	BlockIntoFunction(child.Genes, []*Chromosome{cA, cB}, options)
+	return child
+}
+
+// ShuffleFunction implements the shuffle crossover function with `Points`
+// crossover points. The parents' loci are shuffled, point crossover is
+// performed between them and the child's loci are unshuffled, so that, unlike
+// point crossover, genes are as likely to be inherited together whatever their
+// distance apart.
+var ShuffleFunction CrossoverMethodFunction = func(cA *Chromosome, cB *Chromosome, options CrossoverOptions) *Chromosome {
+	child := &Chromosome{Genes: make([]float64, len(cA.Genes))}
+	ShuffleIntoFunction(child.Genes, []*Chromosome{cA, cB}, options)
+	return child
+}
+
+// SBXFunction implements the simulated binary crossover function with a
+// distribution index of `Eta`. Each of the child's genes is one of a pair of
+// values spread symmetrically about the mean of the parents' genes, mimicking
+// the spread of single-point crossover on binary strings, so that children are
+// likely to be near their parents but may lie beyond them.
+var SBXFunction CrossoverMethodFunction = func(cA *Chromosome, cB *Chromosome, options CrossoverOptions) *Chromosome {
+	child := &Chromosome{Genes: make([]float64, len(cA.Genes))}
+	SBXIntoFunction(child.Genes, []*Chromosome{cA, cB}, options)
+	return child
+}
+
+// MajorityFunction implements the majority crossover function. Each of the
+// child's genes takes the value held by the most parents at that locus. When
+// there is no majority, the value of a random parent is used.
+var MajorityFunction MultiParentCrossoverFunction = func(parents []*Chromosome, options CrossoverOptions) *Chromosome {
+	child := &Chromosome{Genes: make([]float64, len(parents[0].Genes))}
+	MajorityIntoFunction(child.Genes, parents, options)
+	return child
+}
+
+// AverageFunction implements the averaging crossover function. Each of the
+// child's genes is the mean of the parents' genes at that locus.
+var AverageFunction MultiParentCrossoverFunction = func(parents []*Chromosome, options CrossoverOptions) *Chromosome {
+	child := &Chromosome{Genes: make([]float64, len(parents[0].Genes))}
+	AverageIntoFunction(child.Genes, parents, options)
+	return child
+}
 
-	crossoverPoints := indexes[0:count]
-	sort.Ints(crossoverPoints)
-	crossoverPoints = append([]int{0}, crossoverPoints...)
-	crossoverPoints = append(crossoverPoints, len(cA.Genes))
+// PointIntoFunction implements `PointFunction` for the first two parents,
+// writing the child's genes in to a buffer.
+//
+// Crossover points fall between adjacent genes, so a child with `n` genes has
+// at most `n - 1` of them. Larger numbers of points are clamped, and negative
+// numbers of points copy the first parent.
+var PointIntoFunction CrossoverIntoFunction = func(child []float64, parents []*Chromosome, options CrossoverOptions) {
+	rng := options.Source()
+	genes := [2][]float64{parents[0].Genes, parents[1].Genes}
 
-	child := &Chromosome{}
-	for _, g := range cA.Genes {
-		child.Genes = append(child.Genes, g)
+	candidates := len(child) - 1
+	points := options.Points
+	if points > candidates {
+		points = candidates
 	}
 
-	for i := 0; i < len(crossoverPoints)-1; i++ {
-		for j := crossoverPoints[i]; j < crossoverPoints[i+1]; j++ {
-			if i%2 == 0 {
-				child.Genes[j] = cA.Genes[j]
+	// Select the points in order with selection sampling, so that each set of
+	// points is equally likely without shuffling or sorting the candidates.
+	parent := 0
+	for i := range child {
+		if i > 0 && points > 0 {
+			if rng.Intn(candidates) < points {
+				parent = 1 - parent
+				points--
+			}
+			candidates--
+		}
+		child[i] = genes[parent][i]
+	}
+}
+
+// UniformIntoFunction implements `UniformFunction` for the first two parents,
+// writing the child's genes in to a buffer.
+var UniformIntoFunction CrossoverIntoFunction = func(child []float64, parents []*Chromosome, options CrossoverOptions) {
+	rng := options.Source()
+	cA, cB := parents[0], parents[1]
+
+	if options.Bias == 0.0 {
+		for i := range child {
+			if rng.Intn(2) == 1 {
+				child[i] = cA.Genes[i]
 			} else {
-				child.Genes[j] = cB.Genes[j]
+				child[i] = cB.Genes[i]
 			}
 		}
+		return
 	}
 
-	return child
+	if compareValues(cA.Fitness, cB.Fitness, 0.0) < 0 {
+		cA, cB = cB, cA
+	}
+
+	for i := range child {
+		if rng.Float64() < options.Bias {
+			child[i] = cA.Genes[i]
+		} else {
+			child[i] = cB.Genes[i]
+		}
+	}
+}
+
+// BlockIntoFunction implements `BlockFunction` for the first two parents,
+// writing the child's genes in to a buffer.
+var BlockIntoFunction CrossoverIntoFunction = func(child []float64, parents []*Chromosome, options CrossoverOptions) {
+	rng := options.Source()
+	cA, cB := parents[0], parents[1]
+
+	bias := options.Bias
+	if bias == 0.0 {
+		bias = 0.5
+	} else if compareValues(cA.Fitness, cB.Fitness, 0.0) < 0 {
+		cA, cB = cB, cA
+	}
+
+	length := options.BlockLength
+	if length < 1 {
+		length = 1
+	}
+
+	for start := 0; start < len(child); start += length {
+		end := start + length
+		if end > len(child) {
+			end = len(child)
+		}
+
+		if rng.Float64() < bias {
+			copy(child[start:end], cA.Genes[start:end])
+		} else {
+			copy(child[start:end], cB.Genes[start:end])
+		}
+	}
 }
 
-// UniformFunction implements the uniform crossover function.
-var UniformFunction CrossoverMethodFunction = func(cA *Chromosome, cB *Chromosome, count int) *Chromosome {
-	child := &Chromosome{}
-	for _, g := range cA.Genes {
-		child.Genes = append(child.Genes, g)
+// ShuffleIntoFunction implements `ShuffleFunction` for the first two parents,
+// writing the child's genes in to a buffer. The number of crossover points is
+// limited as it is by `PointIntoFunction`.
+var ShuffleIntoFunction CrossoverIntoFunction = func(child []float64, parents []*Chromosome, options CrossoverOptions) {
+	rng := options.Source()
+	cA, cB := parents[0], parents[1]
+
+	candidates := len(child) - 1
+	points := options.Points
+	if points > candidates {
+		points = candidates
 	}
 
-	for i := 0; i < len(cA.Genes); i++ {
-		if rand.Intn(2) == 1 {
-			child.Genes[i] = cA.Genes[i]
+	// Count the shuffled loci that point crossover would take from the first
+	// parent.
+	inherited := 0
+	first := true
+	for i := range child {
+		if i > 0 && points > 0 {
+			if rng.Intn(candidates) < points {
+				first = !first
+				points--
+			}
+			candidates--
+		}
+		if first {
+			inherited++
+		}
+	}
+
+	// Since the shuffle is uniformly random, unshuffling the loci taken from the
+	// first parent gives a uniformly random set of loci of the same size, which
+	// is selected without shuffling.
+	remaining := len(child)
+	for i := range child {
+		if rng.Intn(remaining) < inherited {
+			child[i] = cA.Genes[i]
+			inherited--
 		} else {
-			child.Genes[i] = cB.Genes[i]
+			child[i] = cB.Genes[i]
 		}
+		remaining--
 	}
+}
 
-	return child
+// SBXIntoFunction implements `SBXFunction` for the first two parents, writing
+// the child's genes in to a buffer.
+var SBXIntoFunction CrossoverIntoFunction = func(child []float64, parents []*Chromosome, options CrossoverOptions) {
+	rng := options.Source()
+	cA, cB := parents[0], parents[1]
+	exponent := 1.0 / (options.Eta + 1.0)
+
+	for i := range child {
+		if cA.Genes[i] == cB.Genes[i] {
+			child[i] = cA.Genes[i]
+			continue
+		}
+
+		var spread float64
+		if u := rng.Float64(); u <= 0.5 {
+			spread = math.Pow(2.0*u, exponent)
+		} else {
+			spread = math.Pow(1.0/(2.0*(1.0-u)), exponent)
+		}
+
+		if rng.Intn(2) == 1 {
+			spread = -spread
+		}
+		child[i] = 0.5 * ((1.0+spread)*cA.Genes[i] + (1.0-spread)*cB.Genes[i])
+	}
+}
+
+// MajorityIntoFunction implements `MajorityFunction`, writing the child's
+// genes in to a buffer.
+var MajorityIntoFunction CrossoverIntoFunction = func(child []float64, parents []*Chromosome, options CrossoverOptions) {
+	rng := options.Source()
+
+	for i := range child {
+		child[i] = parents[rng.Intn(len(parents))].Genes[i]
+		for _, p := range parents {
+			votes := 0
+			for _, q := range parents {
+				if q.Genes[i] == p.Genes[i] {
+					votes++
+				}
+			}
+
+			if votes*2 > len(parents) {
+				child[i] = p.Genes[i]
+				break
+			}
+		}
+	}
+}
+
+// AverageIntoFunction implements `AverageFunction`, writing the child's genes
+// in to a buffer.
+var AverageIntoFunction CrossoverIntoFunction = func(child []float64, parents []*Chromosome, options CrossoverOptions) {
+	for i := range child {
+		child[i] = 0.0
+	}
+
+	for _, p := range parents {
+		floats.Add(child, p.Genes[:len(child)])
+	}
+	floats.Scale(1.0/float64(len(parents)), child)
 }
 
 // MARK: Private functions
@@ -118,7 +559,71 @@ func crossoverFunctionForType(t CrossoverMethodType) CrossoverMethodFunction {
 		return PointFunction
 	case CrossoverMethodTypeUniform:
 		return UniformFunction
+	case CrossoverMethodTypeBlock:
+		return BlockFunction
+	case CrossoverMethodTypeShuffle:
+		return ShuffleFunction
+	case CrossoverMethodTypeSBX:
+		return SBXFunction
+	default:
+		return nil
+	}
+}
+
+// crossoverIntoFunctionForType returns the into function for the given type.
+func crossoverIntoFunctionForType(t CrossoverMethodType) CrossoverIntoFunction {
+	switch t {
+	case CrossoverMethodTypePoint:
+		return PointIntoFunction
+	case CrossoverMethodTypeUniform:
+		return UniformIntoFunction
+	case CrossoverMethodTypeMajority:
+		return MajorityIntoFunction
+	case CrossoverMethodTypeAverage:
+		return AverageIntoFunction
+	case CrossoverMethodTypeBlock:
+		return BlockIntoFunction
+	case CrossoverMethodTypeShuffle:
+		return ShuffleIntoFunction
+	case CrossoverMethodTypeSBX:
+		return SBXIntoFunction
 	default:
 		return nil
 	}
 }
+
+// multiParentCrossoverFunctionForType returns the multi-parent crossover
+// function for the given type.
+func multiParentCrossoverFunctionForType(t CrossoverMethodType) MultiParentCrossoverFunction {
+	switch t {
+	case CrossoverMethodTypeMajority:
+		return MajorityFunction
+	case CrossoverMethodTypeAverage:
+		return AverageFunction
+	default:
+		return nil
+	}
+}
+
+// crossoverMethodTypeForName returns the crossover method type with the given
+// case-insensitive name.
+func crossoverMethodTypeForName(name string) (CrossoverMethodType, bool) {
+	switch strings.ToLower(name) {
+	case "point":
+		return CrossoverMethodTypePoint, true
+	case "uniform":
+		return CrossoverMethodTypeUniform, true
+	case "majority":
+		return CrossoverMethodTypeMajority, true
+	case "average":
+		return CrossoverMethodTypeAverage, true
+	case "block":
+		return CrossoverMethodTypeBlock, true
+	case "shuffle":
+		return CrossoverMethodTypeShuffle, true
+	case "sbx":
+		return CrossoverMethodTypeSBX, true
+	default:
+		return CrossoverMethodTypeCustom, false
+	}
+}
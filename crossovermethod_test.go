@@ -0,0 +1,260 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// pointParents returns a pair of parents with n genes whose genes are all zero
+// and all one, so that the parent of each of a child's genes is its value.
+func pointParents(n int) []*Chromosome {
+	cA := &Chromosome{Genes: make([]float64, n)}
+	cB := &Chromosome{Genes: make([]float64, n)}
+	for i := range cB.Genes {
+		cB.Genes[i] = 1.0
+	}
+	return []*Chromosome{cA, cB}
+}
+
+// segments returns the number of times that the parent of consecutive genes
+// changes, and an error if any gene isn't inherited from either parent or the
+// first gene isn't inherited from the first parent.
+func segments(child []float64) (int, error) {
+	switches := 0
+	for i, gene := range child {
+		if gene != 0.0 && gene != 1.0 {
+			return 0, fmt.Errorf("gene %d has value %f from neither parent", i, gene)
+		}
+		if i == 0 && gene != 0.0 {
+			return 0, fmt.Errorf("the first gene isn't inherited from the first parent")
+		}
+		if i > 0 && gene != child[i-1] {
+			switches++
+		}
+	}
+	return switches, nil
+}
+
+func TestCrossoverMethodTypeValues(t *testing.T) {
+	// The numeric values of types are persisted, so they must never change.
+	types := []CrossoverMethodType{
+		CrossoverMethodTypePoint,
+		CrossoverMethodTypeUniform,
+		CrossoverMethodTypeCustom,
+		CrossoverMethodTypeMajority,
+		CrossoverMethodTypeAverage,
+		CrossoverMethodTypeBlock,
+		CrossoverMethodTypeShuffle,
+		CrossoverMethodTypeSBX,
+	}
+	for i, ty := range types {
+		if int(ty) != i {
+			t.Errorf("%s has value %d, expected %d", ty, ty, i)
+		}
+	}
+}
+
+func TestPointIntoFunction(t *testing.T) {
+	tests := []struct {
+		genes    int
+		points   int
+		switches int
+	}{
+		{genes: 0, points: 1, switches: 0},
+		{genes: 0, points: 0, switches: 0},
+		{genes: 1, points: 1, switches: 0},
+		{genes: 1, points: 5, switches: 0},
+		{genes: 2, points: 1, switches: 1},
+		{genes: 5, points: 0, switches: 0},
+		{genes: 5, points: -3, switches: 0},
+		{genes: 5, points: 2, switches: 2},
+		{genes: 5, points: 4, switches: 4},
+		{genes: 5, points: 5, switches: 4},
+		{genes: 5, points: 100, switches: 4},
+	}
+
+	for _, test := range tests {
+		for trial := 0; trial < 100; trial++ {
+			parents := pointParents(test.genes)
+			child := make([]float64, test.genes)
+			PointIntoFunction(child, parents, CrossoverOptions{Points: test.points})
+
+			switches, err := segments(child)
+			if err != nil {
+				t.Fatalf("%d genes, %d points: %s", test.genes, test.points, err)
+			}
+			if switches != test.switches {
+				t.Fatalf("%d genes, %d points: %d crossover points, expected %d", test.genes, test.points, switches, test.switches)
+			}
+		}
+	}
+}
+
+func TestPointFunctionEmptyParents(t *testing.T) {
+	child := PointFunction(&Chromosome{}, &Chromosome{}, CrossoverOptions{Points: 2})
+	if len(child.Genes) != 0 {
+		t.Errorf("child has %d genes, expected 0", len(child.Genes))
+	}
+}
+
+func TestPointIntoFunctionUniformPoints(t *testing.T) {
+	SetRandomSource(NewRandomSource(1))
+	defer SetRandomSource(nil)
+
+	const trials = 30000
+	counts := make(map[string]int)
+	parents := pointParents(5)
+	child := make([]float64, 5)
+	for trial := 0; trial < trials; trial++ {
+		PointIntoFunction(child, parents, CrossoverOptions{Points: 2})
+		counts[fmt.Sprint(child)]++
+	}
+
+	// There are six ways to choose two of the four crossover points.
+	if len(counts) != 6 {
+		t.Fatalf("produced %d children, expected 6", len(counts))
+	}
+	for genes, count := range counts {
+		if count < trials/6*9/10 || count > trials/6*11/10 {
+			t.Errorf("child %s produced %d times, expected about %d", genes, count, trials/6)
+		}
+	}
+}
+
+func TestCrossoverBiasValidation(t *testing.T) {
+	tests := []struct {
+		bias  float64
+		valid bool
+	}{
+		{bias: 0.0, valid: true},
+		{bias: 0.25, valid: true},
+		{bias: 1.0, valid: true},
+		{bias: -0.5, valid: false},
+		{bias: 1.5, valid: false},
+		{bias: math.NaN(), valid: false},
+	}
+
+	for _, test := range tests {
+		configuration := DefaultEvolverConfiguration()
+		configuration.CrossoverMethod = NewCrossoverMethod(CrossoverMethodTypeUniform, CrossoverOptions{Bias: test.bias})
+		if err := configuration.Validate(); (err == nil) != test.valid {
+			t.Errorf("bias %f: validation error %v, expected valid %t", test.bias, err, test.valid)
+		}
+	}
+}
+
+func TestBiasedCrossoverInheritsFromFitterParent(t *testing.T) {
+	parents := pointParents(10)
+	parents[1].Fitness = 1.0
+
+	for _, into := range []CrossoverIntoFunction{UniformIntoFunction, BlockIntoFunction} {
+		child := make([]float64, 10)
+		into(child, parents, CrossoverOptions{Bias: 1.0, BlockLength: 3})
+		for i, gene := range child {
+			if gene != 1.0 {
+				t.Fatalf("gene %d inherited from the less fit parent", i)
+			}
+		}
+	}
+}
+
+func TestParseCrossoverMethodSBX(t *testing.T) {
+	tests := []struct {
+		spec string
+		eta  float64
+	}{
+		{spec: "sbx", eta: 15.0},
+		{spec: "sbx:15", eta: 15.0},
+		{spec: "SBX:2", eta: 2.0},
+	}
+
+	for _, test := range tests {
+		method, err := ParseCrossoverMethod(test.spec)
+		if err != nil {
+			t.Fatalf("%s: %s", test.spec, err)
+		}
+		if method.Type != CrossoverMethodTypeSBX || method.Options.Eta != test.eta {
+			t.Errorf("%s: parsed %s with eta %f, expected sbx with eta %f", test.spec, method.Type, method.Options.Eta, test.eta)
+		}
+	}
+
+	if _, err := ParseCrossoverMethod("sbx:0"); err == nil {
+		t.Error("parsed sbx with a distribution index of zero")
+	}
+}
+
+func TestSBXIntoFunction(t *testing.T) {
+	SetRandomSource(NewRandomSource(1))
+	defer SetRandomSource(nil)
+
+	parents := []*Chromosome{{Genes: []float64{0.0, 3.0}}, {Genes: []float64{1.0, 3.0}}}
+	child := make([]float64, 2)
+
+	const trials = 10000
+	mean, near := 0.0, 0
+	for trial := 0; trial < trials; trial++ {
+		SBXIntoFunction(child, parents, CrossoverOptions{Eta: 15.0})
+		if child[1] != 3.0 {
+			t.Fatalf("gene shared by both parents changed to %f", child[1])
+		}
+
+		mean += child[0]
+		if math.Abs(child[0]) < 0.25 || math.Abs(child[0]-1.0) < 0.25 {
+			near++
+		}
+	}
+
+	// Children are spread symmetrically about the parents' mean, and a large
+	// distribution index keeps most of them near a parent.
+	if mean /= trials; math.Abs(mean-0.5) > 0.01 {
+		t.Errorf("children have a mean gene of %f, expected 0.5", mean)
+	}
+	if near < trials*9/10 {
+		t.Errorf("%d children near their parents, expected most of %d", near, trials)
+	}
+}
+
+// benchmarkCrossoverMethods are the crossover methods that are benchmarked.
+var benchmarkCrossoverMethods = []CrossoverMethodType{
+	CrossoverMethodTypePoint,
+	CrossoverMethodTypeUniform,
+	CrossoverMethodTypeMajority,
+	CrossoverMethodTypeAverage,
+	CrossoverMethodTypeBlock,
+	CrossoverMethodTypeShuffle,
+	CrossoverMethodTypeSBX,
+}
+
+func BenchmarkCrossover(b *testing.B) {
+	for _, t := range benchmarkCrossoverMethods {
+		for _, length := range benchmarkChromosomeLengths {
+			b.Run(fmt.Sprintf("%s/%d", t, length), func(b *testing.B) {
+				method := NewCrossoverMethod(t, CrossoverOptions{Points: 1})
+				parents := benchmarkPopulation(method.ParentCount(), length)
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					method.Crossover(parents)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkCrossoverInto(b *testing.B) {
+	for _, t := range benchmarkCrossoverMethods {
+		for _, length := range benchmarkChromosomeLengths {
+			b.Run(fmt.Sprintf("%s/%d", t, length), func(b *testing.B) {
+				method := NewCrossoverMethod(t, CrossoverOptions{Points: 1})
+				parents := benchmarkPopulation(method.ParentCount(), length)
+				child := make([]float64, length)
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					method.CrossoverInto(child, parents)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Package dashboard renders a live terminal dashboard of the progress of an
+// evolution.
+//
+// The dashboard shows the current generation, the rate that generations are
+// evolved and the estimated time remaining, along with sparklines of the best
+// and mean fitness and the diversity of recent generations. It's redrawn in
+// place using ANSI escape codes, so it should be written to a terminal.
+package dashboard
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	genetics "github.com/colinc86/go-genetics"
+)
+
+// sparks are the characters of sparklines in ascending order of value.
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// Dashboard types render the progress of an evolution to a terminal.
+type Dashboard struct {
+	// The generation that the evolution stops at, used to estimate the time
+	// remaining. If zero, then the time remaining isn't shown.
+	Generations int
+
+	// The number of recent generations shown by sparklines.
+	Width int
+
+	writer io.Writer
+	lines  int
+}
+
+// MARK: Constructors
+
+// New creates and returns a new dashboard that renders to the given writer an
+// evolution that stops at the given generation.
+func New(w io.Writer, generations int) *Dashboard {
+	return &Dashboard{
+		Generations: generations,
+		Width:       40,
+		writer:      w,
+	}
+}
+
+// MARK: Public methods
+
+// Update redraws the dashboard from the statistics of each generation of an
+// evolution so far.
+func (d *Dashboard) Update(stats genetics.EvolutionStats) error {
+	if len(stats) == 0 {
+		return nil
+	}
+
+	recent := stats
+	if d.Width > 0 && len(recent) > d.Width {
+		recent = recent[len(recent)-d.Width:]
+	}
+
+	last := stats[len(stats)-1]
+	var best, mean, diversity []float64
+	for _, s := range recent {
+		best = append(best, s.Best)
+		mean = append(mean, s.Mean)
+		diversity = append(diversity, s.Diversity)
+	}
+
+	rate := 0.0
+	if elapsed := last.Elapsed - recent[0].Elapsed; len(recent) > 1 && elapsed > 0 {
+		rate = float64(len(recent)-1) / elapsed.Seconds()
+	}
+
+	progress := fmt.Sprintf("Generation %d", last.Generation)
+	if d.Generations > 0 {
+		progress += fmt.Sprintf("/%d", d.Generations)
+	}
+	progress += fmt.Sprintf("   %.1f gen/s", rate)
+	if d.Generations > 0 && rate > 0.0 {
+		remaining := math.Max(0.0, float64(d.Generations-last.Generation)/rate)
+		progress += fmt.Sprintf("   ETA %s", formatDuration(time.Duration(remaining*float64(time.Second))))
+	}
+
+	lines := []string{
+		progress,
+		fmt.Sprintf("Best       %-12.6g %s", last.Best, sparkline(best)),
+		fmt.Sprintf("Mean       %-12.6g %s", last.Mean, sparkline(mean)),
+		fmt.Sprintf("Diversity  %-12.6g %s", last.Diversity, sparkline(diversity)),
+		fmt.Sprintf("Evaluations %d   Elapsed %s", last.Evaluations, formatDuration(last.Elapsed)),
+	}
+
+	var b strings.Builder
+	if d.lines > 0 {
+		fmt.Fprintf(&b, "\x1b[%dA", d.lines)
+	}
+	for _, l := range lines {
+		fmt.Fprintf(&b, "\x1b[2K%s\n", l)
+	}
+
+	d.lines = len(lines)
+	_, err := io.WriteString(d.writer, b.String())
+	return err
+}
+
+// Watch returns a function that redraws the dashboard each generation and
+// then returns the result of `shouldContinue`, so that it can be passed to an
+// evolver's `Evolve` method. If `shouldContinue` is nil, then evolution
+// continues until the dashboard's `Generations` are evolved.
+func (d *Dashboard) Watch(shouldContinue func(state *genetics.EvolutionState) bool) func(state *genetics.EvolutionState) bool {
+	return func(state *genetics.EvolutionState) bool {
+		d.Update(state.Stats)
+		if shouldContinue == nil {
+			return state.Generation < d.Generations
+		}
+		return shouldContinue(state)
+	}
+}
+
+// Follow redraws the dashboard for each of an optimizer's events until the
+// channel is closed. See `genetics.Optimizer.Subscribe`.
+func (d *Dashboard) Follow(events <-chan genetics.OptimizerEvent) error {
+	var stats genetics.EvolutionStats
+	for event := range events {
+		stats = append(stats, event.Stats)
+		if d.Width > 0 && len(stats) > d.Width {
+			stats = stats[1:]
+		}
+
+		if err := d.Update(stats); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MARK: Private functions
+
+// sparkline returns a sparkline of the values scaled between their minimum and
+// maximum. NaN and infinite values are shown as spaces.
+func sparkline(values []float64) string {
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			min = math.Min(min, v)
+			max = math.Max(max, v)
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v) || math.IsInf(v, 0):
+			b.WriteRune(' ')
+		case max <= min:
+			b.WriteRune(sparks[len(sparks)/2])
+		default:
+			i := int((v - min) / (max - min) * float64(len(sparks)-1))
+			b.WriteRune(sparks[i])
+		}
+	}
+	return b.String()
+}
+
+// formatDuration returns the duration rounded to a precision suited to its
+// length.
+func formatDuration(d time.Duration) string {
+	switch {
+	case d >= time.Minute:
+		return d.Round(time.Second).String()
+	case d >= time.Second:
+		return d.Round(100 * time.Millisecond).String()
+	default:
+		return d.Round(time.Millisecond).String()
+	}
+}
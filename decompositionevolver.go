@@ -0,0 +1,280 @@
+package genetics
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// DecompositionEvolver types evolve a population towards the Pareto front of
+// several objectives using MOEA/D, which decomposes the problem in to
+// single-objective subproblems, optionally subject to constraints.
+//
+// Each subproblem is defined by a weight vector and minimizes the weighted
+// Tchebycheff distance from its chromosome's objectives to the best value of
+// each objective found so far. Each generation, a child is bred for every
+// subproblem from parents chosen from the subproblems with the closest weight
+// vectors, and replaces the chromosomes of those neighbours that it solves
+// better. A feasible chromosome always solves a subproblem better than an
+// infeasible one, and an infeasible chromosome solves it better than another
+// with a greater violation. A chromosome's fitness is the negated distance of
+// its subproblem.
+//
+// The configuration's crossover and mutation methods, rates and bounds are
+// used to breed children. Its selection method and elitism are not used.
+type DecompositionEvolver struct {
+	Configuration *EvolverConfiguration
+
+	// The function that returns the objectives of a chromosome.
+	ObjectiveFunction MultiObjectiveFunction
+
+	// An optional function that returns the violation of a chromosome's
+	// constraints.
+	ConstraintFunction ConstraintFunction
+
+	// The weight vector of each subproblem. If nil, then the weight vectors are
+	// generated by `WeightVectors` with `Divisions` divisions.
+	Weights [][]float64
+
+	// The number of divisions of each objective used to generate weight
+	// vectors.
+	Divisions int
+
+	// The number of subproblems in each subproblem's neighbourhood, including
+	// itself.
+	NeighbourhoodSize int
+
+	// The probability that parents are chosen from a subproblem's neighbourhood
+	// rather than from every subproblem.
+	NeighbourhoodRate float64
+
+	// The maximum number of chromosomes each child may replace. If zero, then a
+	// child may replace every chromosome of its neighbourhood.
+	MaxReplacements int
+}
+
+// MARK: Constructors
+
+// NewDecompositionEvolver creates and returns a new decomposition evolver with
+// neighbourhoods of 20 subproblems that parents are chosen from 90% of the
+// time, and children that replace at most two chromosomes.
+func NewDecompositionEvolver(configuration *EvolverConfiguration, objectiveFunction MultiObjectiveFunction, divisions int) *DecompositionEvolver {
+	return &DecompositionEvolver{
+		Configuration:     configuration,
+		ObjectiveFunction: objectiveFunction,
+		Divisions:         divisions,
+		NeighbourhoodSize: 20,
+		NeighbourhoodRate: 0.9,
+		MaxReplacements:   2,
+	}
+}
+
+// MARK: Public methods
+
+// Evolve evolves a population until `shouldContinue` returns false, and
+// returns the chromosome of each subproblem along with their Pareto front.
+//
+// The population is evaluated and its chromosomes are assigned to the
+// subproblems in order. If there are more subproblems than chromosomes, then
+// the chromosomes are cloned cyclically, and if there are fewer, then the
+// remaining chromosomes are ignored.
+func (e DecompositionEvolver) Evolve(population Population, shouldContinue func(state *EvolutionState) bool) (Population, Population, error) {
+	evolver := newObjectiveEvolver(e.Configuration, e.ObjectiveFunction, e.ConstraintFunction)
+	evolver.validate(population)
+	state := evolver.newState(population)
+
+	for _, c := range population {
+		if c.ID == 0 {
+			c.ID = nextChromosomeID()
+		}
+	}
+
+	if _, err := evolver.calculateFitnesses(population, state); err != nil {
+		return population, nil, err
+	}
+
+	weights := e.Weights
+	if weights == nil && len(population) > 0 {
+		weights = WeightVectors(len(population[0].Objectives), e.Divisions)
+	}
+	if len(weights) == 0 || len(population) == 0 {
+		return population, population.ParetoFront(), nil
+	}
+
+	ideal := make([]float64, len(weights[0]))
+	for i := range ideal {
+		ideal[i] = math.Inf(-1)
+	}
+	updateIdealPoint(ideal, population)
+
+	solutions := make(Population, len(weights))
+	for i := range solutions {
+		solutions[i] = population[i%len(population)]
+		if i >= len(population) {
+			solutions[i] = solutions[i].Clone()
+		}
+	}
+
+	neighbourhoods := e.neighbourhoods(weights)
+	setTchebycheffFitnesses(solutions, weights, ideal)
+	state.Population = solutions
+	state.Stats = append(state.Stats, newGenerationStats(solutions, state.Generation, time.Since(state.start)))
+
+	for !evolver.budgetSpent(state) && !evolver.stopped() && shouldContinue(state) {
+		state.Generation++
+
+		for i := range solutions {
+			candidates := neighbourhoods[i]
+			if random.Float64() >= e.NeighbourhoodRate {
+				candidates = nil
+			}
+
+			state.selectParent = func() *Chromosome {
+				if candidates == nil {
+					return solutions[random.Intn(len(solutions))]
+				}
+				return solutions[candidates[random.Intn(len(candidates))]]
+			}
+			state.selectMate = state.selectParent
+
+			child := evolver.breedChild(solutions, i, state)
+			if _, err := evolver.calculateFitnesses(Population{child}, state); err != nil {
+				return solutions, solutions.ParetoFront(), err
+			}
+			if len(child.Objectives) != len(ideal) {
+				continue
+			}
+			updateIdealPoint(ideal, Population{child})
+
+			if candidates == nil {
+				candidates = random.Perm(len(solutions))
+			} else {
+				candidates = append([]int(nil), candidates...)
+				random.Shuffle(len(candidates), func(a, b int) {
+					candidates[a], candidates[b] = candidates[b], candidates[a]
+				})
+			}
+
+			replacements := 0
+			for _, j := range candidates {
+				if e.MaxReplacements > 0 && replacements >= e.MaxReplacements {
+					break
+				}
+
+				if solvesBetter(child, solutions[j], weights[j], ideal) {
+					solutions[j] = child.Clone()
+					replacements++
+				}
+			}
+		}
+
+		setTchebycheffFitnesses(solutions, weights, ideal)
+		state.Population = solutions
+		state.Stats = append(state.Stats, newGenerationStats(solutions, state.Generation, time.Since(state.start)))
+	}
+
+	return solutions, solutions.ParetoFront(), nil
+}
+
+// MARK: Public functions
+
+// WeightVectors returns the weight vectors of the simplex-lattice design with
+// the given number of objectives and divisions of each objective. Each vector's
+// weights are multiples of 1/divisions that sum to one, and there are
+// C(divisions + objectives - 1, objectives - 1) vectors.
+func WeightVectors(objectives int, divisions int) [][]float64 {
+	if objectives < 1 || divisions < 1 {
+		return nil
+	}
+
+	var vectors [][]float64
+	vector := make([]int, objectives)
+	var generate func(index, remaining int)
+	generate = func(index, remaining int) {
+		if index == objectives-1 {
+			vector[index] = remaining
+			weights := make([]float64, objectives)
+			for i, v := range vector {
+				weights[i] = float64(v) / float64(divisions)
+			}
+			vectors = append(vectors, weights)
+			return
+		}
+
+		for v := 0; v <= remaining; v++ {
+			vector[index] = v
+			generate(index+1, remaining-v)
+		}
+	}
+	generate(0, divisions)
+	return vectors
+}
+
+// MARK: Private methods
+
+// neighbourhoods returns the indexes of the weight vectors closest to each
+// weight vector, including itself.
+func (e DecompositionEvolver) neighbourhoods(weights [][]float64) [][]int {
+	size := e.NeighbourhoodSize
+	if size < 1 || size > len(weights) {
+		size = len(weights)
+	}
+
+	neighbourhoods := make([][]int, len(weights))
+	for i := range weights {
+		order := make([]int, len(weights))
+		for j := range order {
+			order[j] = j
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return floats.Distance(weights[i], weights[order[a]], 2.0) < floats.Distance(weights[i], weights[order[b]], 2.0)
+		})
+		neighbourhoods[i] = order[:size]
+	}
+	return neighbourhoods
+}
+
+// MARK: Private functions
+
+// updateIdealPoint raises each objective of the ideal point to the greatest
+// value of the objective of the population.
+func updateIdealPoint(ideal []float64, population Population) {
+	for _, c := range population {
+		for i := 0; i < len(ideal) && i < len(c.Objectives); i++ {
+			ideal[i] = math.Max(ideal[i], c.Objectives[i])
+		}
+	}
+}
+
+// tchebycheff returns the weighted Tchebycheff distance from the objectives to
+// the ideal point. Zero weights are replaced by a small weight so that every
+// objective contributes.
+func tchebycheff(objectives, weights, ideal []float64) float64 {
+	distance := 0.0
+	for i := 0; i < len(ideal) && i < len(weights) && i < len(objectives); i++ {
+		distance = math.Max(distance, math.Max(weights[i], 1e-6)*math.Abs(ideal[i]-objectives[i]))
+	}
+	return distance
+}
+
+// solvesBetter returns whether or not chromosome `a` solves the subproblem
+// with the given weights better than chromosome `b`.
+func solvesBetter(a, b *Chromosome, weights, ideal []float64) bool {
+	switch {
+	case a.Violation > 0.0 || b.Violation > 0.0:
+		return a.Violation < b.Violation
+	default:
+		return tchebycheff(a.Objectives, weights, ideal) <= tchebycheff(b.Objectives, weights, ideal)
+	}
+}
+
+// setTchebycheffFitnesses sets the fitness and weight of the chromosome of
+// each subproblem to its negated Tchebycheff distance.
+func setTchebycheffFitnesses(solutions Population, weights [][]float64, ideal []float64) {
+	for i, c := range solutions {
+		c.Fitness = -tchebycheff(c.Objectives, weights[i], ideal)
+		c.weight = c.Fitness
+	}
+}
@@ -0,0 +1,192 @@
+package genetics
+
+import (
+	"errors"
+	"expvar"
+	"math"
+	"math/rand"
+	"sync/atomic"
+)
+
+// DryRunReport objects describe how an evolver's configuration behaved during
+// a dry run. See `Evolver.DryRun`.
+type DryRunReport struct {
+	// The number of generations bred.
+	Generations int
+
+	// The total number of fitness evaluations, including the evaluation of the
+	// initial population, and the mean number of evaluations of each bred
+	// generation. These are the evaluations to expect of a real evolution of
+	// the same length.
+	Evaluations              int
+	EvaluationsPerGeneration float64
+
+	// The number of parents selected, crossovers performed and genes mutated.
+	Selections int
+	Crossovers int
+	Mutations  int
+
+	// The statistics of each of the configuration's crossover and mutation
+	// methods.
+	CrossoverStats []OperatorStats
+	MutationStats  []OperatorStats
+
+	// The mean selection intensity of each generation: the difference between
+	// the mean fitness of selected parents and the mean fitness of the
+	// population, in units of the population's standard deviation of fitness.
+	SelectionIntensity float64
+
+	// The mean selection pressure of each generation: the probability that the
+	// fittest chromosome is selected divided by the probability that a
+	// chromosome is selected uniformly.
+	SelectionPressure float64
+}
+
+// MARK: Public methods
+
+// DryRun breeds `generations` generations of a copy of the population, as
+// `Evolve` would, but evaluates chromosomes with a mock fitness function that's
+// uniformly distributed in [0, 1), and reports how the evolver's configuration
+// behaved, so that it can be vetted before spending compute on the real
+// fitness function. The population and the evolver's stateful components,
+// such as its surrogate, novelty search, refinement, lineage, metrics and
+// event log, aren't used or modified. Returns an error if the configuration is
+// invalid or the dry run fails.
+//
+// Dry runs have their own random numbers and chromosome identifiers, so they
+// don't advance the package's random source or the identifiers assigned to
+// chromosomes, and are safe to run alongside evolutions. Random numbers are
+// drawn from a copy of the random source set with `SetRandomSource`, or from a
+// source seeded with zero if one isn't set, so dry runs are reproducible.
+// Custom operators that draw from `Random` still advance the package's source.
+func (e Evolver) DryRun(population Population, generations int) (*DryRunReport, error) {
+	if err := e.Configuration.Validate(); err != nil {
+		return nil, err
+	}
+	if len(population) == 0 {
+		return nil, errors.New("genetics: the population must not be empty")
+	}
+
+	source := currentRandomSource()
+	if source == nil {
+		source = NewRandomSource(0)
+	}
+	e.random = rand.New(source)
+	e.ids = &chromosomeIDs{last: atomic.LoadUint64(&lastChromosomeID)}
+
+	e.FitnessFunction = dryRunFitness
+	e.FallibleFitnessFunction = nil
+	e.StatsExporter = nil
+	e.Lineage = nil
+	e.Events = nil
+	e.Surrogate = nil
+	e.Environment = nil
+	e.Novelty = nil
+	e.Refinement = nil
+	e.RateLimiter = nil
+	e.Metrics = &Metrics{
+		Generations:           new(expvar.Int),
+		BestFitness:           new(expvar.Float),
+		Evaluations:           new(expvar.Int),
+		EvaluationsPerSecond:  new(expvar.Float),
+		MeanEvaluationSeconds: new(expvar.Float),
+		OperatorCounts:        new(expvar.Map).Init(),
+	}
+
+	copied := make(Population, len(population))
+	for i, c := range population {
+		copied[i] = c.Clone()
+		copied[i].evaluated = false
+	}
+
+	population, state, err := e.initialize(copied)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DryRunReport{}
+	initial := state.Evaluations
+	for report.Generations < generations && !e.budgetSpent(state) && !e.stopped() {
+		intensity, pressure := e.measureSelection(population, state)
+		report.SelectionIntensity += intensity
+		report.SelectionPressure += pressure
+
+		if population, err = e.evolveGeneration(population, state); err != nil {
+			return nil, err
+		}
+		report.Generations++
+	}
+
+	report.Evaluations = state.Evaluations
+	if report.Generations > 0 {
+		report.EvaluationsPerGeneration = float64(state.Evaluations-initial) / float64(report.Generations)
+		report.SelectionIntensity /= float64(report.Generations)
+		report.SelectionPressure /= float64(report.Generations)
+	}
+
+	report.Selections = dryRunCount(e.Metrics, "selection")
+	report.Crossovers = dryRunCount(e.Metrics, "crossover")
+	report.Mutations = dryRunCount(e.Metrics, "mutation")
+	report.CrossoverStats = append([]OperatorStats(nil), state.CrossoverStats...)
+	report.MutationStats = append([]OperatorStats(nil), state.MutationStats...)
+	return report, nil
+}
+
+// MARK: Private methods
+
+// measureSelection selects as many parents from the sorted population as it
+// has chromosomes and returns the selection intensity and selection pressure
+// of the selections.
+func (e Evolver) measureSelection(population Population, state *EvolutionState) (float64, float64) {
+	e.prepareSelection(population, state)
+
+	fitnesses := population.validFitnesses()
+	if len(fitnesses) == 0 {
+		return 0.0, 0.0
+	}
+
+	mean := 0.0
+	for _, f := range fitnesses {
+		mean += f
+	}
+	mean /= float64(len(fitnesses))
+
+	variance := 0.0
+	for _, f := range fitnesses {
+		variance += (f - mean) * (f - mean)
+	}
+	deviation := math.Sqrt(variance / float64(len(fitnesses)))
+
+	best := population[len(population)-1]
+	selected, bestCount := 0.0, 0
+	for i := 0; i < len(population); i++ {
+		c := state.selectParent()
+		selected += c.Fitness
+		if c == best {
+			bestCount++
+		}
+	}
+	selected /= float64(len(population))
+
+	intensity := 0.0
+	if deviation > 0.0 {
+		intensity = (selected - mean) / deviation
+	}
+	return intensity, float64(bestCount)
+}
+
+// MARK: Private functions
+
+// dryRunFitness returns a mock fitness in [0, 1) determined by the hash of the
+// chromosome's genes.
+func dryRunFitness(chromosome *Chromosome, state *EvolutionState) float64 {
+	return float64(chromosome.Hash()>>11) / (1 << 53)
+}
+
+// dryRunCount returns the number of times the named operator was applied.
+func dryRunCount(metrics *Metrics, name string) int {
+	if count, ok := metrics.OperatorCounts.Get(name).(*expvar.Int); ok {
+		return int(count.Value())
+	}
+	return 0
+}
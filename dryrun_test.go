@@ -0,0 +1,91 @@
+package genetics
+
+import (
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+// dryRunPopulation returns a population to dry run.
+func dryRunPopulation() Population {
+	return GeneratePopulation(20, 4, func(i, j int) float64 {
+		return float64(i*4+j) / 80.0
+	})
+}
+
+func TestDryRunPreservesRandomSource(t *testing.T) {
+	s := NewRandomSource(1)
+	SetRandomSource(s)
+	defer SetRandomSource(nil)
+
+	evolver := NewEvolver(DefaultEvolverConfiguration(), dryRunFitness)
+	population := dryRunPopulation()
+	last := atomic.LoadUint64(&lastChromosomeID)
+
+	if _, err := evolver.DryRun(population, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.state != 1 {
+		t.Errorf("the dry run advanced the random source to %x", s.state)
+	}
+	if current := atomic.LoadUint64(&lastChromosomeID); current != last {
+		t.Errorf("the dry run assigned identifiers up to %d", current)
+	}
+	if currentRandomSource() == nil {
+		t.Error("the dry run unset the random source")
+	}
+}
+
+func TestDryRunIsReproducible(t *testing.T) {
+	evolver := NewEvolver(DefaultEvolverConfiguration(), dryRunFitness)
+
+	report, err := evolver.DryRun(dryRunPopulation(), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repeated, err := evolver.DryRun(dryRunPopulation(), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(report, repeated) {
+		t.Errorf("repeated dry run reported %+v, expected %+v", repeated, report)
+	}
+	if currentRandomSource() != nil {
+		t.Error("the dry run left a random source set")
+	}
+}
+
+func TestDryRunAlongsideEvolution(t *testing.T) {
+	done := make(chan uint64)
+	go func() {
+		evolver := NewEvolver(DefaultEvolverConfiguration(), dryRunFitness)
+		generations := 0
+		population, _, _ := evolver.Evolve(dryRunPopulation(), func(state *EvolutionState) bool {
+			generations++
+			return generations < 20
+		})
+
+		last := uint64(0)
+		for _, c := range population {
+			if c.ID > last {
+				last = c.ID
+			}
+		}
+		done <- last
+	}()
+
+	evolver := NewEvolver(DefaultEvolverConfiguration(), dryRunFitness)
+	for i := 0; i < 5; i++ {
+		if _, err := evolver.DryRun(dryRunPopulation(), 5); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Identifiers assigned by the evolution are never assigned again.
+	last := <-done
+	if id := nextChromosomeID(); id <= last {
+		t.Errorf("assigned identifier %d after the evolution assigned %d", id, last)
+	}
+}
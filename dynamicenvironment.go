@@ -0,0 +1,183 @@
+package genetics
+
+import "math"
+
+// DynamicEnvironment objects detect changes to a fitness landscape that drifts
+// over time and respond to them.
+//
+// At the start of each generation, the fittest chromosomes of the population,
+// the sentinels, are evaluated again. If any sentinel's fitness changed by
+// more than the tolerance, then the environment has changed: every chromosome
+// is evaluated again, and the environment's responses are applied. Each
+// response is enabled by setting its parameter.
+type DynamicEnvironment struct {
+	// The number of fittest chromosomes evaluated again each generation.
+	Sentinels int
+
+	// The largest change in a sentinel's fitness that isn't considered a change
+	// of the environment.
+	Tolerance float64
+
+	// Hypermutation: after a change, the mutation rate is raised to this rate
+	// for `HypermutationGenerations` generations.
+	HypermutationRate        float64
+	HypermutationGenerations int
+
+	// Partial restart: after a change, this fraction of the least fit
+	// chromosomes is replaced by random chromosomes within the configuration's
+	// bounds, or in [-1, 1] if unbounded.
+	RestartFraction float64
+
+	// Memory: the best chromosome before each change is added to this archive,
+	// and the archive's injected chromosomes replace the least fit chromosomes
+	// after each change.
+	Memory *MemoryArchive
+
+	changes       int
+	hypermutation int
+}
+
+// MARK: Constructors
+
+// NewDynamicEnvironment creates and returns a new dynamic environment that
+// detects changes using the given number of sentinels and tolerance. Enable
+// responses by setting the environment's parameters.
+func NewDynamicEnvironment(sentinels int, tolerance float64) *DynamicEnvironment {
+	return &DynamicEnvironment{
+		Sentinels: sentinels,
+		Tolerance: tolerance,
+	}
+}
+
+// MARK: Public methods
+
+// Changes returns the number of changes detected.
+func (d *DynamicEnvironment) Changes() int {
+	return d.changes
+}
+
+// MARK: Private methods
+
+// detectChange evaluates the sentinels of the population, which must be sorted
+// in ascending order of fitness, and responds if the environment has changed.
+// Does nothing if the evolver has no dynamic environment.
+func (e Evolver) detectChange(population Population, state *EvolutionState) error {
+	d := e.Environment
+	if d == nil || len(population) == 0 {
+		return nil
+	}
+
+	if d.hypermutation > 0 {
+		d.hypermutation--
+		if d.hypermutation == 0 {
+			state.MutationRate = e.Configuration.MutationRate
+		} else {
+			state.MutationRate = d.HypermutationRate
+		}
+	}
+
+	changed := false
+	for i := 0; i < d.Sentinels && i < len(population); i++ {
+		sentinel := population[len(population)-i-1]
+		if !sentinel.evaluated || sentinel.estimated {
+			continue
+		}
+
+		e.updatePenalty(sentinel, state)
+		fitness, ok, err := e.reevaluate(sentinel, state)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		if math.Abs(fitness-sentinel.Fitness) > d.Tolerance || isInvalidFitness(fitness) != isInvalidFitness(sentinel.Fitness) {
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	d.changes++
+	for _, c := range population {
+		c.evaluated = false
+	}
+
+	var injections Population
+	if d.Memory != nil {
+		var err error
+		injections = d.Memory.injections(func(c *Chromosome) (float64, bool) {
+			if err != nil {
+				return math.NaN(), false
+			}
+
+			var fitness float64
+			var ok bool
+			fitness, ok, err = e.reevaluate(c, state)
+			return fitness, ok
+		})
+		if err != nil {
+			return err
+		}
+		d.Memory.Add(population[len(population)-1])
+	}
+
+	replaced := 0
+	if d.RestartFraction > 0.0 {
+		count := int(math.Ceil(d.RestartFraction * float64(len(population))))
+		for ; replaced < count && replaced < len(population); replaced++ {
+			population[replaced] = e.Configuration.randomChromosome(len(population[replaced].Genes))
+		}
+	}
+
+	for _, c := range injections {
+		if replaced >= len(population) {
+			break
+		}
+		population[replaced] = c
+		replaced++
+	}
+
+	if d.HypermutationRate > 0.0 && d.HypermutationGenerations > 0 {
+		d.hypermutation = d.HypermutationGenerations
+		state.MutationRate = d.HypermutationRate
+	}
+
+	if _, err := e.calculateFitnesses(population, state); err != nil {
+		return err
+	}
+
+	for _, c := range population[:replaced] {
+		e.Lineage.recordChromosome(c, LineageRecord{Generation: state.Generation})
+	}
+
+	population.sortWith(e.Configuration.Comparator)
+	return nil
+}
+
+// reevaluate evaluates the chromosome again and returns its fitness as
+// `calculateFitnesses` would set it, without changing the chromosome's fitness,
+// and whether or not the evaluation was made and succeeded. Evaluations aren't
+// made if the evaluation budget is spent or the evolver is stopped. Returns an
+// error if the evaluation panicked and the evolver's panic policy aborts.
+func (e Evolver) reevaluate(chromosome *Chromosome, state *EvolutionState) (float64, bool, error) {
+	if e.budgetSpent(state) || !e.RateLimiter.wait(e.Stop) {
+		return math.NaN(), false, nil
+	}
+
+	fitness, attempts, err := e.evaluate(chromosome, state)
+	state.Evaluations += attempts
+	if err != nil {
+		if panicErr, ok := err.(*PanicError); ok {
+			return math.NaN(), false, panicErr
+		}
+		return math.NaN(), false, nil
+	}
+
+	if r := e.Configuration.Regularization; r != nil && !isInvalidFitness(fitness) {
+		fitness -= r.Penalty(chromosome)
+	}
+	return fitness, true, nil
+}
@@ -0,0 +1,67 @@
+package genetics
+
+import "testing"
+
+func TestDetectChangeIgnoresRegularization(t *testing.T) {
+	SetRandomSource(NewRandomSource(1))
+	defer SetRandomSource(nil)
+
+	configuration := DefaultEvolverConfiguration()
+	configuration.Regularization = NewRegularization(RegularizationTypeL2, 1.0, nil)
+	configuration.Bounds = []GeneBounds{{Min: -1.0, Max: 1.0}}
+	configuration.NormalizeGenes = true
+
+	evolver := NewEvolver(configuration, func(c *Chromosome, state *EvolutionState) float64 {
+		return -c.Genes[0]
+	})
+	evolver.Environment = NewDynamicEnvironment(3, 1e-9)
+
+	population := GeneratePopulation(20, 4, func(i, j int) float64 {
+		return Random().Float64()*2.0 - 1.0
+	})
+
+	generations := 0
+	if _, _, err := evolver.Evolve(population, func(state *EvolutionState) bool {
+		generations++
+		return generations < 10
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if changes := evolver.Environment.Changes(); changes != 0 {
+		t.Errorf("detected %d changes of a static environment", changes)
+	}
+}
+
+func TestDetectChangeDetectsChange(t *testing.T) {
+	SetRandomSource(NewRandomSource(1))
+	defer SetRandomSource(nil)
+
+	configuration := DefaultEvolverConfiguration()
+	configuration.Regularization = NewRegularization(RegularizationTypeL2, 1.0, nil)
+
+	offset := 0.0
+	evolver := NewEvolver(configuration, func(c *Chromosome, state *EvolutionState) float64 {
+		return offset - c.Genes[0]
+	})
+	evolver.Environment = NewDynamicEnvironment(1, 1e-9)
+
+	population := GeneratePopulation(20, 4, func(i, j int) float64 {
+		return Random().Float64()*2.0 - 1.0
+	})
+
+	generations := 0
+	if _, _, err := evolver.Evolve(population, func(state *EvolutionState) bool {
+		generations++
+		if generations == 5 {
+			offset = 10.0
+		}
+		return generations < 10
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if changes := evolver.Environment.Changes(); changes != 1 {
+		t.Errorf("detected %d changes, expected 1", changes)
+	}
+}
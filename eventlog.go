@@ -0,0 +1,356 @@
+package genetics
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// ErrEventMismatch is returned when an evolution being replayed diverges from
+// its recorded event log.
+var ErrEventMismatch = errors.New("genetics: event mismatch")
+
+// EventType represents a type of evolution event.
+type EventType uint8
+
+// Types of evolution events.
+const (
+	// A generation began.
+	EventTypeGeneration EventType = 0
+
+	// A parent was selected for breeding.
+	EventTypeSelection EventType = 1
+
+	// A child was bred by crossover.
+	EventTypeCrossover EventType = 2
+
+	// A gene of a child was mutated.
+	EventTypeMutation EventType = 3
+
+	// A chromosome's fitness was evaluated.
+	EventTypeEvaluation EventType = 4
+
+	// A generation replaced the previous generation.
+	EventTypeReplacement EventType = 5
+)
+
+// Event objects describe a single decision made during evolution.
+type Event struct {
+	Type EventType
+
+	// The generation that the event occurred in.
+	Generation int
+
+	// The index of the selected, bred, mutated or evaluated chromosome. Selected
+	// chromosomes are indexed in the population being bred from, bred and
+	// mutated chromosomes in the population being bred, and evaluated
+	// chromosomes in the population before it's sorted.
+	Index int
+
+	// The indexes of a crossover's parents in the population being bred from.
+	Parents []int
+
+	// The index of the applied crossover or mutation operator.
+	Operator int
+
+	// The index of the mutated gene.
+	Gene int
+
+	// The mutated gene's new value or the evaluated fitness.
+	Value float64
+
+	// The hashes of the genes of a replacement generation's chromosomes in
+	// ascending order of fitness.
+	Population []uint64
+
+	// The state of the package's random source when the generation began, or
+	// nil if no random source was set.
+	Random *RandomSource
+}
+
+// EventLog objects record the events of an evolution to a compact binary
+// stream, or replay a recorded stream by verifying that an evolution's events
+// are byte-for-byte identical to the recorded events.
+//
+// The state of the package's random source is recorded at the start of every
+// generation, and restored when the generation is replayed, so a replay draws
+// the same random numbers as its recording regardless of how the random source
+// was seeded. Every decision that depends on the draws is recorded too, so that
+// a replay detects the first event at which an evolution diverges from its
+// recording. Chromosomes are identified by their position in a population
+// rather than by their identifiers, so recordings can be replayed in any
+// process.
+//
+// Evolutions that draw from math/rand's global source can't be replayed, only
+// verified, because its state can't be recorded. Set a random source with
+// `SetRandomSource` before recording.
+type EventLog struct {
+	writer io.Writer
+	reader *EventReader
+	count  int
+	err    error
+	buffer []byte
+}
+
+// EventReader objects decode events from a binary stream recorded by an event
+// log.
+type EventReader struct {
+	reader *bufio.Reader
+	err    error
+}
+
+// MARK: Constructors
+
+// NewEventRecorder creates and returns an event log that records events to the
+// writer.
+func NewEventRecorder(w io.Writer) *EventLog {
+	return &EventLog{writer: w}
+}
+
+// NewEventReplayer creates and returns an event log that verifies events
+// against those recorded in the reader, restoring the package's random source
+// to its recorded state at the start of every generation.
+func NewEventReplayer(r io.Reader) *EventLog {
+	return &EventLog{reader: NewEventReader(r)}
+}
+
+// NewEventReader creates and returns an event reader that decodes events from
+// the reader.
+func NewEventReader(r io.Reader) *EventReader {
+	return &EventReader{reader: bufio.NewReader(r)}
+}
+
+// MARK: Public methods
+
+// Count returns the number of events recorded or verified.
+func (l *EventLog) Count() int {
+	return l.count
+}
+
+// Err returns the first error encountered while recording or verifying events.
+// Once an error occurs, later events are ignored.
+func (l *EventLog) Err() error {
+	if l == nil {
+		return nil
+	}
+	return l.err
+}
+
+// Next returns the next event in the stream, or `io.EOF` if there are no more
+// events.
+func (r *EventReader) Next() (Event, error) {
+	event := Event{}
+	t, err := r.reader.ReadByte()
+	if err != nil {
+		return event, err
+	}
+
+	r.err = nil
+	event.Type = EventType(t)
+	event.Generation = int(r.readUvarint())
+
+	switch event.Type {
+	case EventTypeGeneration:
+		if r.readByte() != 0 {
+			event.Random = &RandomSource{state: r.readUint64()}
+		}
+	case EventTypeSelection:
+		event.Index = int(r.readUvarint())
+	case EventTypeCrossover:
+		event.Index = int(r.readUvarint())
+		event.Operator = int(r.readUvarint())
+		for _, i := range r.readList() {
+			event.Parents = append(event.Parents, int(i))
+		}
+	case EventTypeMutation:
+		event.Index = int(r.readUvarint())
+		event.Operator = int(r.readUvarint())
+		event.Gene = int(r.readUvarint())
+		event.Value = r.readFloat()
+	case EventTypeEvaluation:
+		event.Index = int(r.readUvarint())
+		event.Value = r.readFloat()
+	case EventTypeReplacement:
+		event.Population = r.readList()
+	default:
+		return event, fmt.Errorf("unknown event type %d", t)
+	}
+
+	if r.err == io.EOF {
+		return event, io.ErrUnexpectedEOF
+	}
+	return event, r.err
+}
+
+// MARK: String methods
+
+func (t EventType) String() string {
+	switch t {
+	case EventTypeGeneration:
+		return "generation"
+	case EventTypeSelection:
+		return "selection"
+	case EventTypeCrossover:
+		return "crossover"
+	case EventTypeMutation:
+		return "mutation"
+	case EventTypeEvaluation:
+		return "evaluation"
+	case EventTypeReplacement:
+		return "replacement"
+	default:
+		return "unknown"
+	}
+}
+
+// MARK: Private methods
+
+// record records or verifies the event. Does nothing if the log is nil or has
+// encountered an error.
+func (l *EventLog) record(event Event) {
+	if l == nil || l.err != nil {
+		return
+	}
+
+	l.buffer = event.appendBinary(l.buffer[:0])
+	l.count++
+
+	if l.writer != nil {
+		_, l.err = l.writer.Write(l.buffer)
+		return
+	}
+
+	recorded, err := l.reader.Next()
+	if err == io.EOF {
+		l.err = fmt.Errorf("%w: event %d: the recording ended before %s event", ErrEventMismatch, l.count, event.Type)
+		return
+	} else if err != nil {
+		l.err = err
+		return
+	}
+
+	// Generations are replayed from their recorded random state rather than
+	// verified against it.
+	if event.Type == EventTypeGeneration && recorded.Type == EventTypeGeneration {
+		restoreRandomSource(recorded.Random)
+		event.Random = recorded.Random
+		l.buffer = event.appendBinary(l.buffer[:0])
+	}
+
+	if !bytes.Equal(recorded.appendBinary(nil), l.buffer) {
+		l.err = fmt.Errorf("%w: event %d: recorded %+v, got %+v", ErrEventMismatch, l.count, recorded, event)
+	}
+}
+
+// appendBinary appends the binary encoding of the event to the buffer.
+func (e Event) appendBinary(buffer []byte) []byte {
+	buffer = append(buffer, byte(e.Type))
+	buffer = appendUvarint(buffer, uint64(e.Generation))
+
+	switch e.Type {
+	case EventTypeGeneration:
+		if e.Random == nil {
+			buffer = append(buffer, 0)
+		} else {
+			buffer = append(buffer, 1)
+			buffer = appendUint64(buffer, e.Random.state)
+		}
+	case EventTypeSelection:
+		buffer = appendUvarint(buffer, uint64(e.Index))
+	case EventTypeCrossover:
+		buffer = appendUvarint(buffer, uint64(e.Index))
+		buffer = appendUvarint(buffer, uint64(e.Operator))
+		buffer = appendUvarint(buffer, uint64(len(e.Parents)))
+		for _, i := range e.Parents {
+			buffer = appendUvarint(buffer, uint64(i))
+		}
+	case EventTypeMutation:
+		buffer = appendUvarint(buffer, uint64(e.Index))
+		buffer = appendUvarint(buffer, uint64(e.Operator))
+		buffer = appendUvarint(buffer, uint64(e.Gene))
+		buffer = appendFloat(buffer, e.Value)
+	case EventTypeEvaluation:
+		buffer = appendUvarint(buffer, uint64(e.Index))
+		buffer = appendFloat(buffer, e.Value)
+	case EventTypeReplacement:
+		buffer = appendUvarint(buffer, uint64(len(e.Population)))
+		for _, hash := range e.Population {
+			buffer = appendUvarint(buffer, hash)
+		}
+	}
+	return buffer
+}
+
+// readUvarint reads a variable length unsigned integer. Once reading fails,
+// the reader's error is set and zero values are returned.
+func (r *EventReader) readUvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+
+	var value uint64
+	value, r.err = binary.ReadUvarint(r.reader)
+	return value
+}
+
+// readByte reads a single byte.
+func (r *EventReader) readByte() byte {
+	if r.err != nil {
+		return 0
+	}
+
+	var value byte
+	value, r.err = r.reader.ReadByte()
+	return value
+}
+
+// readUint64 reads a fixed length unsigned integer.
+func (r *EventReader) readUint64() uint64 {
+	var bits [8]byte
+	if r.err != nil {
+		return 0
+	}
+
+	_, r.err = io.ReadFull(r.reader, bits[:])
+	return binary.LittleEndian.Uint64(bits[:])
+}
+
+// readFloat reads a fixed length float.
+func (r *EventReader) readFloat() float64 {
+	return math.Float64frombits(r.readUint64())
+}
+
+// readList reads a length-prefixed list of variable length unsigned integers.
+func (r *EventReader) readList() []uint64 {
+	n := r.readUvarint()
+	var list []uint64
+	for i := uint64(0); i < n && r.err == nil; i++ {
+		list = append(list, r.readUvarint())
+	}
+	return list
+}
+
+// MARK: Private functions
+
+// appendUvarint appends the variable length encoding of the value.
+func appendUvarint(buffer []byte, value uint64) []byte {
+	var encoded [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(encoded[:], value)
+	return append(buffer, encoded[:n]...)
+}
+
+// appendUint64 appends the fixed length encoding of the value.
+func appendUint64(buffer []byte, value uint64) []byte {
+	var encoded [8]byte
+	binary.LittleEndian.PutUint64(encoded[:], value)
+	return append(buffer, encoded[:]...)
+}
+
+// appendFloat appends the fixed length encoding of the value.
+func appendFloat(buffer []byte, value float64) []byte {
+	return appendUint64(buffer, math.Float64bits(value))
+}
@@ -0,0 +1,74 @@
+package genetics
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// eventLogEvolution evolves a fixed population for ten generations with the
+// event log and returns the fitness of the best chromosome.
+func eventLogEvolution(t *testing.T, events *EventLog, offset float64) (float64, error) {
+	t.Helper()
+
+	configuration := DefaultEvolverConfiguration()
+	evolver := NewEvolver(configuration, func(c *Chromosome, state *EvolutionState) float64 {
+		return offset - c.Genes[0]*c.Genes[0]
+	})
+	evolver.Events = events
+
+	population := GeneratePopulation(20, 4, func(i, j int) float64 {
+		return float64(i*4+j)/40.0 - 1.0
+	})
+
+	generations := 0
+	_, best, err := evolver.Evolve(population, func(state *EvolutionState) bool {
+		generations++
+		return generations < 10
+	})
+	if err != nil {
+		return 0.0, err
+	}
+	return best.Fitness, events.Err()
+}
+
+func TestEventLogReplaysRandomSource(t *testing.T) {
+	defer SetRandomSource(nil)
+
+	recording := &bytes.Buffer{}
+	SetRandomSource(NewRandomSource(1))
+	recorded, err := eventLogEvolution(t, NewEventRecorder(recording), 0.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The replay is seeded differently, so it only matches the recording if the
+	// recorded random state is restored.
+	SetRandomSource(NewRandomSource(2))
+	replayer := NewEventReplayer(bytes.NewReader(recording.Bytes()))
+	replayed, err := eventLogEvolution(t, replayer, 0.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed != recorded {
+		t.Errorf("replayed best fitness %f, recorded %f", replayed, recorded)
+	}
+	if replayer.Count() == 0 {
+		t.Error("no events were replayed")
+	}
+}
+
+func TestEventLogDetectsDivergence(t *testing.T) {
+	defer SetRandomSource(nil)
+
+	recording := &bytes.Buffer{}
+	SetRandomSource(NewRandomSource(1))
+	if _, err := eventLogEvolution(t, NewEventRecorder(recording), 0.0); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := eventLogEvolution(t, NewEventReplayer(bytes.NewReader(recording.Bytes())), 1.0)
+	if !errors.Is(err, ErrEventMismatch) {
+		t.Errorf("error %v isn't an event mismatch", err)
+	}
+}
@@ -0,0 +1,86 @@
+package genetics
+
+import (
+	"math/rand"
+	"time"
+)
+
+// EvolutionState objects describe the progress of an evolution and are passed
+// to an evolver's callbacks.
+type EvolutionState struct {
+	// The index of the current generation. The initial population is generation
+	// zero.
+	Generation int
+
+	// The configuration of the evolver performing the evolution.
+	Configuration *EvolverConfiguration
+
+	// The population of the current generation. While a generation is being
+	// bred, this is the population that its chromosomes are bred from.
+	Population Population
+
+	// The total number of fitness evaluations performed, including retries of
+	// invalid fitness values.
+	Evaluations int
+
+	// The number of panics recovered from the evolver's callbacks. See
+	// `PanicPolicy`.
+	Panics int
+
+	// The current mutation rate. This is the configuration's mutation rate unless
+	// the configuration uses an adaptive mutation rate.
+	MutationRate float64
+
+	// The statistics of each evaluated generation.
+	Stats EvolutionStats
+
+	// The statistics of each of the configuration's crossover methods.
+	CrossoverStats []OperatorStats
+
+	// The statistics of each of the configuration's mutation methods.
+	MutationStats []OperatorStats
+
+	// The time that the evolution began.
+	start time.Time
+
+	// The total and longest durations of the fitness evaluations of the most
+	// recently evaluated generation.
+	evaluationTime    time.Duration
+	slowestEvaluation time.Duration
+
+	// The population buffer that the next generation is bred in to when reusing
+	// chromosomes.
+	spare Population
+
+	// The buffer that the parents of each crossover are selected in to.
+	parents []*Chromosome
+
+	// The functions that select parents and mates from the population being
+	// bred from, along with their weight tables.
+	selectParent  func() *Chromosome
+	selectMate    func() *Chromosome
+	parentWeights weightTable
+	mateWeights   weightTable
+
+	// The first panic recovered when using the abort panic policy, and the
+	// callback being called while breeding.
+	panic    *PanicError
+	callback string
+
+	// The index of each chromosome in the population being bred from. Only
+	// built when the evolver records events.
+	indexes map[*Chromosome]int
+
+	// The evolver's source of random numbers, or nil if it uses the package's.
+	random *rand.Rand
+}
+
+// source returns the evolution's source of random numbers, which is the
+// package's source unless the evolver has its own. Built-in operators draw
+// from it.
+func (s *EvolutionState) source() *rand.Rand {
+	if s == nil || s.random == nil {
+		return random
+	}
+	return s.random
+}
@@ -0,0 +1,91 @@
+package genetics
+
+import (
+	"math"
+	"time"
+)
+
+// GenerationStats objects contain the statistics of a single generation of an
+// evolution.
+type GenerationStats struct {
+	// The index of the generation.
+	Generation int
+
+	// The highest fitness in the generation.
+	Best float64
+
+	// The fitness components of the fittest chromosome of the generation, if
+	// any. See `Chromosome.Components`.
+	BestComponents map[string]float64
+
+	// The mean and lowest fitness in the generation. NaN fitness is ignored, as
+	// it is by `Population.MeanFitness`, so both are NaN only if every fitness
+	// is.
+	Mean  float64
+	Worst float64
+
+	// The genetic diversity of the generation. See `Population.Diversity`.
+	Diversity float64
+
+	// The time elapsed since the evolution began.
+	Elapsed time.Duration
+
+	// The number of NaN and ±Inf fitness values returned by the fitness function
+	// while evaluating the generation.
+	InvalidFitnesses int
+
+	// The total number of fitness evaluations performed since the evolution
+	// began.
+	Evaluations int
+
+	// The total time spent evaluating the fitness of the generation's
+	// chromosomes, and the longest time spent evaluating a single chromosome.
+	// Compare the total time to `Elapsed` to judge how much of an evolution is
+	// spent in the fitness function.
+	EvaluationTime    time.Duration
+	SlowestEvaluation time.Duration
+}
+
+// EvolutionStats types are an array of the statistics of each generation of an
+// evolution.
+type EvolutionStats []GenerationStats
+
+// MARK: Private functions
+
+// newGenerationStats calculates the statistics of a population sorted in
+// ascending order of fitness.
+func newGenerationStats(population Population, generation int, elapsed time.Duration) GenerationStats {
+	stats := GenerationStats{
+		Generation: generation,
+		Diversity:  population.Diversity(),
+		Elapsed:    elapsed,
+	}
+
+	if len(population) > 0 {
+		stats.Best = population[len(population)-1].Fitness
+		stats.BestComponents = copyComponents(population[len(population)-1].Components)
+		stats.Mean, stats.Worst = meanAndWorstFitness(population)
+	}
+
+	return stats
+}
+
+// meanAndWorstFitness returns the mean and lowest fitness of the population's
+// chromosomes, ignoring NaN fitness, without allocating. Returns NaN if no
+// chromosome has a fitness.
+func meanAndWorstFitness(population Population) (float64, float64) {
+	sum, worst, n := 0.0, math.Inf(1), 0
+	for _, c := range population {
+		if math.IsNaN(c.Fitness) {
+			continue
+		}
+		sum += c.Fitness
+		worst = math.Min(worst, c.Fitness)
+		n++
+	}
+
+	if n == 0 {
+		return math.NaN(), math.NaN()
+	}
+	return sum / float64(n), worst
+}
@@ -0,0 +1,94 @@
+package genetics
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fitnessPopulation returns a population sorted in ascending order of fitness
+// whose chromosomes have the given fitnesses.
+func fitnessPopulation(fitnesses ...float64) Population {
+	population := make(Population, len(fitnesses))
+	for i, fitness := range fitnesses {
+		population[i] = &Chromosome{Fitness: fitness}
+	}
+	population.sortByFitness()
+	return population
+}
+
+// sameFloat returns whether the values are equal or both NaN.
+func sameFloat(a, b float64) bool {
+	return a == b || (math.IsNaN(a) && math.IsNaN(b))
+}
+
+func TestNewGenerationStatsIgnoresNaN(t *testing.T) {
+	tests := []struct {
+		fitnesses []float64
+		mean      float64
+		worst     float64
+	}{
+		{[]float64{math.NaN(), 1.0, 3.0}, 2.0, 1.0},
+		{[]float64{-2.0, math.NaN(), math.NaN(), 4.0}, 1.0, -2.0},
+		{[]float64{math.NaN()}, math.NaN(), math.NaN()},
+	}
+
+	for _, test := range tests {
+		stats := newGenerationStats(fitnessPopulation(test.fitnesses...), 0, 0)
+		if !sameFloat(stats.Mean, test.mean) {
+			t.Errorf("%v: mean is %f, expected %f", test.fitnesses, stats.Mean, test.mean)
+		}
+		if !sameFloat(stats.Worst, test.worst) {
+			t.Errorf("%v: worst is %f, expected %f", test.fitnesses, stats.Worst, test.worst)
+		}
+	}
+}
+
+func TestNewGenerationStats(t *testing.T) {
+	tests := []struct {
+		population Population
+		generation int
+		expected   GenerationStats
+	}{
+		{
+			Population{},
+			0,
+			GenerationStats{},
+		},
+		{
+			Population{
+				&Chromosome{Genes: []float64{0.0, 1.0}, Fitness: 1.0},
+				&Chromosome{Genes: []float64{2.0, 1.0}, Fitness: 3.0, Components: map[string]float64{"a": 3.0}},
+			},
+			4,
+			GenerationStats{Generation: 4, Best: 3.0, BestComponents: map[string]float64{"a": 3.0}, Mean: 2.0, Worst: 1.0, Diversity: 0.5},
+		},
+		{
+			Population{
+				&Chromosome{Genes: []float64{1.0}, Fitness: -6.0},
+				&Chromosome{Genes: []float64{1.0}, Fitness: 0.0},
+				&Chromosome{Genes: []float64{1.0}, Fitness: 9.0},
+			},
+			7,
+			GenerationStats{Generation: 7, Best: 9.0, Mean: 1.0, Worst: -6.0},
+		},
+	}
+
+	for i, test := range tests {
+		stats := newGenerationStats(test.population, test.generation, time.Second)
+		test.expected.Elapsed = time.Second
+		if !reflect.DeepEqual(stats, test.expected) {
+			t.Errorf("test %d: stats are %+v, expected %+v", i, stats, test.expected)
+		}
+	}
+}
+
+func TestNewGenerationStatsCopiesComponents(t *testing.T) {
+	components := map[string]float64{"a": 1.0}
+	stats := newGenerationStats(Population{&Chromosome{Fitness: 1.0, Components: components}}, 0, 0)
+	components["a"] = 2.0
+	if stats.BestComponents["a"] != 1.0 {
+		t.Errorf("modifying the chromosome's components modified the statistics")
+	}
+}
@@ -3,145 +3,828 @@
 package genetics
 
 import (
+	"fmt"
+	"math"
 	"math/rand"
-	"sort"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 )
 
+// The number of times an illegal child is bred before a parent is copied
+// instead.
+const maximumBreedingAttempts = 10
+
 // FitnessFunction defines a fitness function.
-type FitnessFunction func(chromosome *Chromosome) float64
+type FitnessFunction func(chromosome *Chromosome, state *EvolutionState) float64
 
-// MutationFunction defines a mutation function.
-type MutationFunction func(chromosome *Chromosome, i int) float64
+// ValidationFunction defines a function that returns an error describing why a
+// chromosome's genes are illegal, or nil if they're legal.
+type ValidationFunction func(chromosome *Chromosome) error
 
-// Evolver types evolve a population given a configuration, fitness function,
-// and mutation function.
+// Evolver types evolve a population given a configuration and fitness
+// function.
 type Evolver struct {
-	Configuration    *EvolverConfiguration
-	FitnessFunction  FitnessFunction
-	MutationFunction MutationFunction
+	Configuration   *EvolverConfiguration
+	FitnessFunction FitnessFunction
+
+	// Known chromosomes that are seeded in to the population before evolution
+	// begins. Use `Population.TopK` to warm-start from a previous evolution's
+	// best chromosomes without evaluating them again.
+	Seeds []*Chromosome
+
+	// An optional exporter that the statistics of each generation are written to
+	// as the population evolves.
+	StatsExporter *StatsExporter
+
+	// Optional metrics that are updated as the population evolves.
+	Metrics *Metrics
+
+	// An optional lineage that records the genealogy of every chromosome in the
+	// population as it evolves.
+	Lineage *Lineage
+
+	// An optional event log that records, or verifies the replay of, every
+	// decision made while evolving the population.
+	Events *EventLog
+
+	// An optional surrogate that pre-screens bred chromosomes so that only the
+	// most promising are evaluated by the fitness function.
+	Surrogate *Surrogate
+
+	// An optional channel that stops the evolution once closed. The generation
+	// being evolved is finished first, so the population and best chromosome
+	// are returned as usual. See `StopOnSignal`.
+	Stop <-chan struct{}
+
+	// An optional dynamic environment that detects and responds to changes of
+	// a fitness landscape that drifts over time.
+	Environment *DynamicEnvironment
+
+	// An optional novelty search that drives selection by the novelty of
+	// chromosomes' behaviors.
+	Novelty *NoveltySearch
+
+	// An optional refinement that improves the fittest chromosomes of each
+	// generation with local gradient steps.
+	Refinement *Refinement
+
+	// An optional fitness function that may fail, which evolvers use instead of
+	// `FitnessFunction` when evolving a population, along with the policy that
+	// its failures are retried and handled by. See `NewFallibleEvolver`.
+	FallibleFitnessFunction FallibleFitnessFunction
+	RetryPolicy             *RetryPolicy
+
+	// An optional rate limiter that every fitness evaluation waits for.
+	// Chromosomes that are waiting when the evolution is stopped are skipped.
+	RateLimiter *RateLimiter
+
+	// An optional function that repairs each bred chromosome after crossover,
+	// mutation and bounds are applied, so that problems with constraints, such
+	// as permutations, don't rely on penalties alone.
+	Repair RepairFunction
+
+	// An optional function that validates each bred chromosome after crossover,
+	// mutation and repair. Illegal chromosomes are bred again, up to 10 times,
+	// after which the child is a copy of a selected parent, so illegal chromosomes
+	// are never evaluated.
+	Validate ValidationFunction
+
+	// The source of the evolver's random numbers and the allocator of its
+	// chromosomes' identifiers. If nil, then the package's are used. Internal
+	// use only.
+	random *rand.Rand
+	ids    *chromosomeIDs
 }
 
 // MARK: Constructors
 
 // NewEvolver creates and returns a new evolver.
-func NewEvolver(configuration *EvolverConfiguration, fitnessFunction FitnessFunction, mutationFunction MutationFunction) *Evolver {
+func NewEvolver(configuration *EvolverConfiguration, fitnessFunction FitnessFunction) *Evolver {
 	return &Evolver{
-		Configuration:    configuration,
-		FitnessFunction:  fitnessFunction,
-		MutationFunction: mutationFunction,
+		Configuration:   configuration,
+		FitnessFunction: fitnessFunction,
 	}
 }
 
 // MARK: Public methods
 
-// Evolve evolves a population.
-func (e Evolver) Evolve(population Population, shouldContinue func(configuration *EvolverConfiguration, pop Population) bool) {
+// Evolve evolves a population and returns the final generation sorted in
+// ascending order of fitness along with its best chromosome. If an error stops
+// the evolution, then the most recent generation is returned with the error.
+// The order of the given population isn't modified.
+func (e Evolver) Evolve(population Population, shouldContinue func(state *EvolutionState) bool) (Population, *Chromosome, error) {
+	e.validate(population)
+	population, state, err := e.initialize(population)
+
+	for err == nil && !e.budgetSpent(state) && !e.stopped() && shouldContinue(state) {
+		population, err = e.evolveGeneration(population, state)
+	}
+
+	if len(population) == 0 {
+		return population, nil, err
+	}
+	return population, population[len(population)-1], err
+}
+
+// MARK: Private methods
+
+// validate logs any problems with evolving the population using the evolver's
+// configuration.
+func (e Evolver) validate(population Population) {
+	if err := e.Configuration.Validate(); err != nil {
+		log.Errorf("Invalid configuration: %s", err)
+	}
+
 	if len(population) == 0 {
 		log.Errorln("There are no chromosomes in the population.")
 	}
 
-	if e.Configuration.CrossoverMethod.Count >= len(population) {
-		log.Errorln("The crossover count must be less than the number of chromosomes in the population.")
+	for _, m := range e.Configuration.crossoverMethods() {
+		if m != nil && (m.Type == CrossoverMethodTypePoint || m.Type == CrossoverMethodTypeShuffle) && len(population) > 0 && m.Options.Points >= len(population[0].Genes) {
+			log.Warnln("The number of crossover points is at least the number of genes, so it will be limited to one less than the number of genes.")
+		}
 	}
 
-	if int(e.Configuration.Elitism) > len(population) {
+	if e.Configuration.EliteCount(len(population)) > len(population) {
 		log.Errorln("The elitism count must be less than or equal to the number of chromosomes in the population.")
 	}
 
-	e.calculateFitnesses(population)
-	sort.Slice(population[:], func(i, j int) bool {
-		return population[i].Fitness < population[j].Fitness
+	if e.Validate != nil {
+		for i, c := range population {
+			if err := e.Validate(c); err != nil {
+				log.Errorf("Chromosome %d of the population is illegal: %s", i, err)
+			}
+		}
+	}
+}
+
+// initialize seeds and evaluates a copy of the initial population, and returns
+// the copy sorted in ascending order of fitness along with the state of the
+// evolution.
+func (e Evolver) initialize(population Population) (Population, *EvolutionState, error) {
+	population = append(make(Population, 0, len(population)), population...)
+	state := e.newState(population)
+
+	population.Seed(e.Seeds...)
+	for _, c := range population {
+		if c.ID == 0 {
+			c.ID = e.ids.next()
+		} else {
+			e.ids.reserve(c.ID)
+		}
+	}
+
+	invalid, err := e.calculateFitnesses(population, state)
+	if err != nil {
+		return population, state, err
+	}
+
+	for _, c := range population {
+		e.Lineage.recordChromosome(c, LineageRecord{})
+	}
+
+	population.sortWith(e.Configuration.Comparator)
+	if err := e.recordReplacement(population, state); err != nil {
+		return population, state, err
+	}
+
+	e.recordStats(population, state, invalid)
+	return population, state, nil
+}
+
+// newState returns the state of an evolution of the population that hasn't
+// begun.
+func (e Evolver) newState(population Population) *EvolutionState {
+	state := &EvolutionState{
+		Configuration: e.Configuration,
+		Population:    population,
+		MutationRate:  e.Configuration.MutationRate,
+		start:         time.Now(),
+		random:        e.random,
+	}
+
+	var crossoverNames []string
+	var crossoverWeights []float64
+	for _, m := range e.Configuration.crossoverMethods() {
+		crossoverNames = append(crossoverNames, m.Type.String())
+		crossoverWeights = append(crossoverWeights, m.Weight)
+	}
+	state.CrossoverStats = newOperatorStats(crossoverNames, crossoverWeights)
+
+	var mutationNames []string
+	for _, m := range e.Configuration.mutationMethods() {
+		mutationNames = append(mutationNames, m.Type.String())
+	}
+	state.MutationStats = newOperatorStats(mutationNames, nil)
+	return state
+}
+
+// evolveGeneration breeds, evaluates and sorts the next generation of the
+// population.
+func (e Evolver) evolveGeneration(population Population, state *EvolutionState) (Population, error) {
+	state.Generation++
+	if e.Events != nil {
+		e.Events.record(Event{Type: EventTypeGeneration, Generation: state.Generation, Random: currentRandomSource()})
+	}
+	if err := e.detectChange(population, state); err != nil {
+		return population, err
+	}
+
+	for _, c := range population {
+		c.Age++
+	}
+
+	var invalid int
+	var err error
+	if e.Configuration.ReplacementStrategy == ReplacementStrategyAgeFitnessPareto {
+		population, invalid, err = e.breedAgeFitnessGeneration(population, state)
+	} else {
+		bred := e.breedSingleGeneration(population, state)
+		if state.panic != nil {
+			return population, state.panic
+		}
+		population = bred
+		state.Population = population
+		invalid, err = e.calculateFitnesses(population, state)
+	}
+	if err != nil {
+		return population, err
+	}
+
+	e.recordLineage(population, state)
+	e.creditOperators(population, state)
+
+	population.sortWith(e.Configuration.Comparator)
+	if err := e.recordReplacement(population, state); err != nil {
+		return population, err
+	}
+
+	e.recordStats(population, state, invalid)
+	e.adaptMutationRate(state)
+	return population, nil
+}
+
+// adaptMutationRate doubles the state's mutation rate if the best fitness of
+// the most recent generation didn't improve and halves it otherwise.
+func (e Evolver) adaptMutationRate(state *EvolutionState) {
+	if !e.Configuration.AdaptiveMutationRate || len(state.Stats) < 2 {
+		return
+	}
+
+	current := state.Stats[len(state.Stats)-1]
+	previous := state.Stats[len(state.Stats)-2]
+	if current.Best > previous.Best {
+		state.MutationRate = math.Max(e.Configuration.MutationRate, state.MutationRate/2.0)
+	} else {
+		state.MutationRate = math.Min(math.Max(e.Configuration.MutationRate, 0.5), state.MutationRate*2.0)
+	}
+}
+
+// recordStats appends the statistics of the sorted population to the state and
+// exports them.
+func (e Evolver) recordStats(population Population, state *EvolutionState, invalidFitnesses int) {
+	stats := newGenerationStats(population, state.Generation, time.Since(state.start))
+	if len(e.Configuration.Conditions) > 0 {
+		stats.Diversity = e.Configuration.Diversity(population)
+	}
+	stats.InvalidFitnesses = invalidFitnesses
+	stats.Evaluations = state.Evaluations
+	stats.EvaluationTime = state.evaluationTime
+	stats.SlowestEvaluation = state.slowestEvaluation
+	state.Stats = append(state.Stats, stats)
+	e.Metrics.recordGeneration(stats)
+
+	if e.StatsExporter != nil {
+		if err := e.StatsExporter.Export(stats); err != nil {
+			log.Errorf("Unable to export generation statistics: %s", err)
+		}
+	}
+}
+
+// recordReplacement records the sorted population that replaced the previous
+// generation in the evolver's event log, and returns any error encountered by
+// the log.
+func (e Evolver) recordReplacement(population Population, state *EvolutionState) error {
+	if e.Events == nil {
+		return nil
+	}
+
+	hashes := make([]uint64, len(population))
+	for i, c := range population {
+		hashes[i] = c.Hash()
+	}
+
+	e.Events.record(Event{
+		Type:       EventTypeReplacement,
+		Generation: state.Generation,
+		Population: hashes,
 	})
+	return e.Events.Err()
+}
 
-	for shouldContinue(e.Configuration, population) {
-		population = e.breedSingleGeneration(population)
-		e.calculateFitnesses(population)
+// recordSelection records the selection of the chromosome from the population
+// being bred from in the evolver's event log.
+func (e Evolver) recordSelection(chromosome *Chromosome, state *EvolutionState) {
+	if e.Events == nil {
+		return
+	}
 
-		sort.Slice(population[:], func(i, j int) bool {
-			return population[i].Fitness < population[j].Fitness
-		})
+	e.Events.record(Event{
+		Type:       EventTypeSelection,
+		Generation: state.Generation,
+		Index:      state.indexes[chromosome],
+	})
+}
+
+// recordLineage records the genealogy of each newly bred chromosome in the
+// evolver's lineage.
+func (e Evolver) recordLineage(population Population, state *EvolutionState) {
+	if e.Lineage == nil {
+		return
+	}
+
+	for _, c := range population {
+		if !c.bred.pending {
+			continue
+		}
+
+		record := LineageRecord{
+			Parents:    append([]uint64(nil), c.bred.parents...),
+			Generation: state.Generation,
+		}
+		if c.bred.crossover >= 0 {
+			record.Crossover = state.CrossoverStats[c.bred.crossover].Name
+		}
+		if c.bred.mutation >= 0 {
+			record.Mutation = state.MutationStats[c.bred.mutation].Name
+		}
+		e.Lineage.recordChromosome(c, record)
 	}
 }
 
-// MARK: Private methods
+// creditOperators records whether the operators applied to each newly bred
+// chromosome produced a child fitter than its parents.
+func (e Evolver) creditOperators(population Population, state *EvolutionState) {
+	for _, c := range population {
+		if !c.bred.pending {
+			continue
+		}
+
+		success := c.Fitness > c.bred.parentFitness
+		creditOperator(state.CrossoverStats, c.bred.crossover, success)
+		creditOperator(state.MutationStats, c.bred.mutation, success)
+		c.bred.pending = false
+	}
+
+	finishOperatorGeneration(state.CrossoverStats, e.Configuration.AdaptiveOperatorSelection)
+	finishOperatorGeneration(state.MutationStats, e.Configuration.AdaptiveOperatorSelection)
+}
 
 // shouldCrossover returns whether or not the evolver should perform crossover.
-func (e Evolver) shouldCrossover() bool {
-	return rand.Float64() <= e.Configuration.CrossoverRate
+func (e Evolver) shouldCrossover(state *EvolutionState) bool {
+	return state.source().Float64() <= e.Configuration.CrossoverRate
 }
 
 // shouldMutate returns whether or not the evolver should perform mutation.
-func (e Evolver) shouldMutate() bool {
-	return rand.Float64() <= e.Configuration.MutationRate
+func (e Evolver) shouldMutate(state *EvolutionState) bool {
+	return state.source().Float64() <= state.MutationRate
 }
 
-// calculateFitness calculates the fitness of each chromosome in a population.
-func (e Evolver) calculateFitnesses(population Population) {
+// evaluate returns the fitness of the chromosome returned by the fitness
+// function, and gives the chromosome the configuration's schema. If the
+// configuration normalizes genes or has conditions, then the chromosome's genes
+// are decoded and inactive genes are replaced by NaN while the fitness function
+// is called.
+func (e Evolver) evaluate(chromosome *Chromosome, state *EvolutionState) (fitness float64, attempts int, err error) {
+	if e.Configuration.PanicPolicy != PanicPolicyNone {
+		defer func() {
+			if value := recover(); value != nil {
+				fitness, attempts, err = math.NaN(), 1, e.recoverEvaluation(value, chromosome, state)
+			}
+		}()
+	}
+
+	if e.Configuration.Schema != nil {
+		chromosome.schema = e.Configuration.Schema
+	}
+
+	normalized := e.Configuration.NormalizeGenes && len(e.Configuration.Bounds) > 0
+	if normalized || len(e.Configuration.Conditions) > 0 {
+		genes := chromosome.Genes
+		chromosome.Genes = e.Configuration.fitnessGenes(genes)
+		defer func() {
+			chromosome.Genes = genes
+		}()
+	}
+
+	if e.FallibleFitnessFunction != nil {
+		return e.RetryPolicy.evaluate(e.FallibleFitnessFunction, chromosome, state, e.RateLimiter, e.Stop)
+	}
+	return e.FitnessFunction(chromosome, state), 1, nil
+}
+
+// calculateFitness calculates the fitness of each chromosome in a population
+// that hasn't already been evaluated and sets the chromosomes' weights. Returns
+// the number of NaN and ±Inf fitness values returned by the fitness function.
+func (e Evolver) calculateFitnesses(population Population, state *EvolutionState) (int, error) {
+	start := time.Now()
+	count := 0
+	defer func() {
+		e.Metrics.recordEvaluations(count, time.Since(start), state.evaluationTime)
+	}()
+
+	e.Surrogate.screen(population)
+
+	if n := e.Configuration.ReevaluateElites; n > 0 && state.Generation > 0 && state.Generation%n == 0 {
+		for _, c := range population {
+			c.evaluated = c.evaluated && c.estimated
+		}
+	}
+
+	state.evaluationTime = 0
+	state.slowestEvaluation = 0
+
+	timeout := e.Configuration.GenerationTimeout
+	var invalid, evaluated, skipped []*Chromosome
 	for i := 0; i < len(population); i++ {
-		fitness := e.FitnessFunction(population[i])
-		if fitness < 0.0 {
-			// log.Warnf("Negative fitness value %f may cause strange results.", fitness)
+		if population[i].evaluated {
+			e.updatePenalty(population[i], state)
+			population[i].weight = population[i].Fitness
+			continue
+		}
+
+		if e.budgetSpent(state) || (timeout > 0 && time.Since(start) > timeout) || !e.RateLimiter.wait(e.Stop) {
+			population[i].Fitness = math.NaN()
+			skipped = append(skipped, population[i])
+			continue
+		}
+
+		evaluationStart := time.Now()
+		fitness, attempts, err := e.evaluate(population[i], state)
+		count += attempts
+		state.Evaluations += attempts
+
+		policy := e.Configuration.InvalidFitnessPolicy
+		for retries := 0; err == nil && policy == InvalidFitnessPolicyRetry && isInvalidFitness(fitness) && retries < maximumFitnessRetries && e.RateLimiter.wait(e.Stop); retries++ {
+			fitness, attempts, err = e.evaluate(population[i], state)
+			count += attempts
+			state.Evaluations += attempts
+		}
+
+		duration := time.Since(evaluationStart)
+		population[i].EvaluationDuration = duration
+		state.evaluationTime += duration
+		if duration > state.slowestEvaluation {
+			state.slowestEvaluation = duration
+		}
+
+		if panicErr, ok := err.(*PanicError); ok {
+			return len(invalid), panicErr
+		}
+
+		if err != nil {
+			switch e.RetryPolicy.failurePolicy() {
+			case FitnessFailurePolicyPenalty:
+				fitness = e.RetryPolicy.PenaltyFitness
+			case FitnessFailurePolicyDrop:
+				population[i].Fitness = math.NaN()
+				skipped = append(skipped, population[i])
+				continue
+			default:
+				return len(invalid), fmt.Errorf("genetics: fitness evaluation failed in generation %d: %w", state.Generation, err)
+			}
+		}
+
+		if isInvalidFitness(fitness) {
+			invalid = append(invalid, population[i])
+			switch policy {
+			case InvalidFitnessPolicyError:
+				return len(invalid), fmt.Errorf("%w %f in generation %d", ErrInvalidFitness, fitness, state.Generation)
+			case InvalidFitnessPolicyClamp:
+				fitness = clampFitness(fitness)
+			}
+		}
+
+		if r := e.Configuration.Regularization; r != nil && !isInvalidFitness(fitness) {
+			fitness -= r.Penalty(population[i])
 		}
 
 		population[i].Fitness = fitness
 		population[i].weight = fitness
+		population[i].evaluated = true
+		if e.Surrogate != nil {
+			evaluated = append(evaluated, population[i])
+		}
+		e.Events.record(Event{
+			Type:       EventTypeEvaluation,
+			Generation: state.Generation,
+			Index:      i,
+			Value:      fitness,
+		})
+	}
+
+	if policy := e.Configuration.InvalidFitnessPolicy; policy == InvalidFitnessPolicyWorst || policy == InvalidFitnessPolicyRetry {
+		worst := worstValidFitness(population)
+		for _, c := range invalid {
+			c.Fitness = worst
+			c.weight = worst
+		}
+	}
+
+	e.Configuration.SkippedFitnessPolicy.assign(population, skipped)
+	e.refine(population, state)
+	if state.panic != nil {
+		return len(invalid), state.panic
+	}
+
+	e.Surrogate.update(evaluated)
+
+	if e.Configuration.FitnessScaling != nil {
+		e.Configuration.FitnessScaling.Scale(population)
+	}
+
+	e.Novelty.score(population, state)
+
+	return len(invalid), nil
+}
+
+// updatePenalty updates the fitness of an evaluated chromosome whose fitness
+// was penalized by a penalized fitness function with its penalty in the
+// state's generation.
+func (e Evolver) updatePenalty(chromosome *Chromosome, state *EvolutionState) {
+	if chromosome.penalizer == nil || chromosome.estimated || isInvalidFitness(chromosome.unpenalized) {
+		return
+	}
+
+	fitness := chromosome.penalizer(chromosome.unpenalized, chromosome.Violation, state)
+	if r := e.Configuration.Regularization; r != nil && !isInvalidFitness(fitness) {
+		fitness -= r.Penalty(chromosome)
+	}
+	chromosome.Fitness = fitness
+}
+
+// budgetSpent returns whether or not the evolution has performed the
+// configuration's maximum number of fitness evaluations.
+func (e Evolver) budgetSpent(state *EvolutionState) bool {
+	return e.Configuration.MaxEvaluations > 0 && state.Evaluations >= e.Configuration.MaxEvaluations
+}
+
+// source returns the evolver's source of random numbers, which is the
+// package's source unless the evolver has its own.
+func (e Evolver) source() *rand.Rand {
+	if e.random == nil {
+		return random
+	}
+	return e.random
+}
+
+// stopped returns whether or not the evolver's stop channel is closed.
+func (e Evolver) stopped() bool {
+	select {
+	case <-e.Stop:
+		return true
+	default:
+		return false
 	}
 }
 
 // breedSingleGeneration breeds a single generation of chromosomes from a population.
-func (e Evolver) breedSingleGeneration(population Population) Population {
+func (e Evolver) breedSingleGeneration(population Population, state *EvolutionState) Population {
 	var newPopulation Population
-	elite := e.applyElitism(population)
+	if e.Configuration.ReuseChromosomes {
+		newPopulation = state.spare[:0]
+	}
 
-	newPopulation = append(newPopulation, elite...)
+	e.prepareSelection(population, state)
+	newPopulation = e.applyElitism(population, newPopulation)
+	elites := len(newPopulation)
+	newPopulation = e.applyGenerationGap(population, newPopulation)
+	survivors := make(map[*Chromosome]bool, len(newPopulation)-elites)
+	for _, c := range newPopulation[elites:] {
+		survivors[c] = true
+	}
 
-	for i := len(elite); i < len(population); i++ {
-		child := e.breedChild(population)
+	for i := len(newPopulation); i < len(population) && state.panic == nil; i++ {
+		child := e.breedChild(population, i, state)
 		// log.Debugf("Got child %s\n", child)
 		newPopulation = append(newPopulation, child)
 	}
 
+	if e.Configuration.ReuseChromosomes {
+		for _, c := range population[:len(population)-elites] {
+			if !survivors[c] {
+				releaseChromosome(c)
+			}
+		}
+		state.spare = population
+	}
+
 	return newPopulation
 }
 
+// prepareSelection prepares the state to select parents from the population.
+func (e Evolver) prepareSelection(population Population, state *EvolutionState) {
+	context := SelectionContext{
+		Generation: state.Generation,
+		Random:     state.source(),
+		Maximize:   true,
+		Comparator: e.Configuration.Comparator,
+	}
+	if len(state.Stats) > 0 {
+		context.Stats = state.Stats[len(state.Stats)-1]
+	}
+
+	state.selectParent = e.Configuration.SelectionMethod.selector(population, context, &state.parentWeights)
+	state.selectMate = state.selectParent
+	if m := e.Configuration.MateChoice; m != nil && m.SelectionMethod != nil {
+		state.selectMate = m.SelectionMethod.selector(population, context, &state.mateWeights)
+	}
+
+	if e.Events != nil {
+		state.indexes = make(map[*Chromosome]int, len(population))
+		for i, c := range population {
+			state.indexes[c] = i
+		}
+	}
+}
+
 // applyElitisim applies elitism to a population and places the chromosomes that
 // survived in to the destination population.
-func (e Evolver) applyElitism(population Population) []*Chromosome {
-	var chromosomes []*Chromosome
-	for i := 0; i < int(e.Configuration.Elitism); i++ {
-		chromosomes = append(chromosomes, population[len(population)-i-1])
+func (e Evolver) applyElitism(population Population, destination Population) Population {
+	for i := 0; i < e.Configuration.EliteCount(len(population)) && i < len(population); i++ {
+		destination = append(destination, population[len(population)-i-1])
 	}
-	return chromosomes
+	return destination
 }
 
-// breedChild breeds a child chromosome from the population.
-func (e Evolver) breedChild(population Population) *Chromosome {
-	child := &Chromosome{}
-	child.Genes = make([]float64, len(population[0].Genes))
+// breedChild breeds a legal child chromosome from the population at index
+// `index` of the new population.
+func (e Evolver) breedChild(population Population, index int, state *EvolutionState) (child *Chromosome) {
+	if e.Configuration.PanicPolicy != PanicPolicyNone {
+		defer func() {
+			if value := recover(); value != nil {
+				child = e.recoverBreeding(value, population, state)
+			}
+		}()
+	}
 
-	if e.shouldCrossover() {
-		chromosome := e.Configuration.CrossoverMethod.Function(
-			e.Configuration.SelectionMethod.Function(population),
-			e.Configuration.SelectionMethod.Function(population),
-			e.Configuration.CrossoverMethod.Count,
-		)
-		copy(child.Genes, chromosome.Genes)
-		child.Fitness = chromosome.Fitness
-		child.weight = chromosome.weight
+	child = e.breedCandidate(population, index, state)
+	if e.Validate == nil {
+		return child
+	}
+
+	state.callback = "validation"
+	for attempt := 1; e.Validate(child) != nil; attempt++ {
+		if e.Configuration.ReuseChromosomes {
+			releaseChromosome(child)
+		}
+
+		if attempt >= maximumBreedingAttempts {
+			return e.copyParent(population, state)
+		}
+		child = e.breedCandidate(population, index, state)
+		state.callback = "validation"
+	}
+	return child
+}
+
+// copyParent returns an unevaluated copy of a selected parent.
+func (e Evolver) copyParent(population Population, state *EvolutionState) *Chromosome {
+	return e.copyChromosome(population, state.selectParent())
+}
+
+// copyChromosome returns an unevaluated copy of the parent.
+func (e Evolver) copyChromosome(population Population, parent *Chromosome) *Chromosome {
+	child := newChromosome(len(population[0].Genes), e.Configuration.ReuseChromosomes, e.ids)
+	copy(child.Genes, parent.Genes)
+	child.Age = parent.Age
+	child.bred = breedingRecord{
+		pending:       true,
+		parentFitness: parent.Fitness,
+		crossover:     -1,
+		mutation:      -1,
+		parents:       child.bred.parents[:0],
+	}
+	if e.Lineage != nil {
+		child.bred.parents = append(child.bred.parents, parent.ID)
+	}
+	return child
+}
+
+// breedCandidate breeds a child chromosome from the population at index
+// `index` of the new population, which may be illegal.
+func (e Evolver) breedCandidate(population Population, index int, state *EvolutionState) *Chromosome {
+	state.callback = "selection"
+	child := newChromosome(len(population[0].Genes), e.Configuration.ReuseChromosomes, e.ids)
+	frozen := e.Configuration.frozenGenes(len(child.Genes))
+	child.bred = breedingRecord{
+		pending:       true,
+		parentFitness: -math.MaxFloat64,
+		crossover:     -1,
+		mutation:      -1,
+		parents:       child.bred.parents[:0],
+	}
+
+	if e.shouldCrossover(state) {
+		child.bred.crossover = chooseOperator(state.CrossoverStats, state.source())
+		crossoverMethod := *e.Configuration.crossoverMethods()[child.bred.crossover]
+		if crossoverMethod.Options.Random == nil {
+			crossoverMethod.Options.Random = state.source()
+		}
+
+		if cap(state.parents) < crossoverMethod.ParentCount() {
+			state.parents = make([]*Chromosome, crossoverMethod.ParentCount())
+		}
+		parents := state.parents[:crossoverMethod.ParentCount()]
+		for i := range parents {
+			if i == 0 || e.Configuration.MateChoice == nil {
+				parents[i] = state.selectParent()
+			} else {
+				parents[i] = e.Configuration.MateChoice.selectMate(parents[0], state.selectMate)
+			}
+			child.bred.parentFitness = math.Max(child.bred.parentFitness, parents[i].Fitness)
+			if parents[i].Age > child.Age {
+				child.Age = parents[i].Age
+			}
+			if e.Lineage != nil {
+				child.bred.parents = append(child.bred.parents, parents[i].ID)
+			}
+			e.recordSelection(parents[i], state)
+			e.Metrics.recordOperator("selection")
+		}
+
+		state.callback = "crossover"
+		if crossoverMethod.IntoFunction != nil {
+			crossoverMethod.IntoFunction(child.Genes, parents, crossoverMethod.Options)
+		} else {
+			chromosome := crossoverMethod.Crossover(parents)
+			copy(child.Genes, chromosome.Genes)
+			child.Fitness = chromosome.Fitness
+			child.weight = chromosome.weight
+		}
+		e.Metrics.recordOperator("crossover")
+		if e.Events != nil {
+			indexes := make([]int, len(parents))
+			for i, p := range parents {
+				indexes[i] = state.indexes[p]
+			}
+			e.Events.record(Event{
+				Type:       EventTypeCrossover,
+				Generation: state.Generation,
+				Index:      index,
+				Operator:   child.bred.crossover,
+				Parents:    indexes,
+			})
+		}
+		for i, f := range frozen {
+			if f {
+				child.Genes[i] = parents[0].Genes[i]
+			}
+		}
 	} else {
-		chromosome := e.Configuration.SelectionMethod.Function(population)
+		chromosome := state.selectParent()
+		child.bred.parentFitness = chromosome.Fitness
+		child.Age = chromosome.Age
+		if e.Lineage != nil {
+			child.bred.parents = append(child.bred.parents, chromosome.ID)
+		}
+		e.recordSelection(chromosome, state)
+		e.Metrics.recordOperator("selection")
 		copy(child.Genes, chromosome.Genes)
 		child.Fitness = chromosome.Fitness
 		child.weight = chromosome.weight
 	}
 
+	state.callback = "mutation"
+	mutation := chooseOperator(state.MutationStats, state.source())
+	mutationMethod := e.Configuration.mutationMethods()[mutation]
+	active := e.Configuration.ActiveGenes(child.Genes)
 	for i := 0; i < len(child.Genes); i++ {
-		if e.shouldMutate() {
-			child.Genes[i] = e.MutationFunction(child, i)
+		if frozen != nil && frozen[i] || active != nil && !active[i] {
+			continue
+		}
+
+		if e.shouldMutate(state) {
+			child.Genes[i] = mutationMethod.Function(child, i, state)
+			child.bred.mutation = mutation
+			e.Metrics.recordOperator("mutation")
+			e.Events.record(Event{
+				Type:       EventTypeMutation,
+				Generation: state.Generation,
+				Index:      index,
+				Operator:   mutation,
+				Gene:       i,
+				Value:      child.Genes[i],
+			})
 		}
 	}
+
+	e.Configuration.clampGenes(child.Genes)
+	if e.Repair != nil {
+		state.callback = "repair"
+		e.Repair(child)
+	}
 	// log.Debugf("Returning child %s\n", child)
 	return child
 }
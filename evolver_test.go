@@ -0,0 +1,66 @@
+package genetics
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Benchmarks run at each of these population sizes and chromosome lengths.
+// Compare runs with `go test -run ^$ -bench . -benchmem -count 10` and
+// benchstat to catch regressions.
+var (
+	benchmarkPopulationSizes   = []int{100, 1000}
+	benchmarkChromosomeLengths = []int{10, 100}
+)
+
+// benchmarkWarmGenerations is the number of generations evolved before
+// generations are measured.
+const benchmarkWarmGenerations = 5
+
+func BenchmarkEvolveGeneration(b *testing.B) {
+	for _, size := range benchmarkPopulationSizes {
+		for _, length := range benchmarkChromosomeLengths {
+			for _, reuse := range []bool{false, true} {
+				name := fmt.Sprintf("%dx%d", size, length)
+				if reuse {
+					name += "/reuse"
+				}
+
+				b.Run(name, func(b *testing.B) {
+					configuration := DefaultEvolverConfiguration()
+					configuration.ReuseChromosomes = reuse
+					evolver := NewEvolver(configuration, func(chromosome *Chromosome, state *EvolutionState) float64 {
+						sum := 0.0
+						for _, g := range chromosome.Genes {
+							sum -= g * g
+						}
+						return sum
+					})
+
+					// Measure generations once the evolver is warm, after its buffers
+					// have been allocated.
+					population := benchmarkPopulation(size, length)
+					b.ReportAllocs()
+					evolver.Evolve(population, func(state *EvolutionState) bool {
+						if state.Generation == benchmarkWarmGenerations {
+							b.ResetTimer()
+						}
+						return state.Generation < benchmarkWarmGenerations+b.N
+					})
+				})
+			}
+		}
+	}
+}
+
+// benchmarkPopulation returns a population with uniformly distributed genes
+// and random fitness.
+func benchmarkPopulation(size int, length int) Population {
+	population := GeneratePopulation(uint(size), uint(length), func(i, j int) float64 {
+		return random.Float64()*2.0 - 1.0
+	})
+	for _, c := range population {
+		c.Fitness = random.Float64()
+	}
+	return population
+}
@@ -1,24 +1,953 @@
 package genetics
 
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
 // EvolverConfiguration objects contains all of the necessary information needed
 // to evolve a population of chromosomes using an evolver.
 type EvolverConfiguration struct {
 	SelectionMethod *SelectionMethod
 	CrossoverMethod *CrossoverMethod
+	MutationMethod  *MutationMethod
 	Elitism         uint
 	CrossoverRate   float64
 	MutationRate    float64
+
+	// Optional bounds that constrain the genes of bred chromosomes. Either
+	// contains the bounds of each gene, or a single bounds that applies to every
+	// gene.
+	Bounds []GeneBounds
+
+	// The fraction of the population that survives each generation as elites.
+	// The number of elites is the greater of `Elitism` and this fraction of the
+	// population rounded up.
+	ElitismRate float64
+
+	// How NaN and ±Inf values returned by the fitness function are handled.
+	InvalidFitnessPolicy InvalidFitnessPolicy
+
+	// Optional scaling applied to fitness to obtain the weights used by
+	// selection. If nil, then raw fitness is used.
+	FitnessScaling *FitnessScaling
+
+	// An optional regularization that penalizes the fitness of chromosomes to
+	// bias evolution towards simpler chromosomes or chromosomes close to a
+	// prior.
+	Regularization *Regularization
+
+	// An optional strategy for choosing mates for the first parent of each
+	// crossover. If nil, then every parent is selected independently using the
+	// selection method.
+	MateChoice *MateChoice
+
+	// Optional crossover methods that replace `CrossoverMethod`. When not empty,
+	// one of the methods is chosen for each crossover with a probability
+	// proportional to its weight.
+	CrossoverMethods []*CrossoverMethod
+
+	// Optional mutation methods that replace `MutationMethod`. When not empty,
+	// one of the methods is chosen for each bred chromosome.
+	MutationMethods []*MutationMethod
+
+	// Whether or not crossover and mutation methods are chosen adaptively. When
+	// true, each method's probability of being chosen is adjusted every
+	// generation by probability matching on how often it produces children that
+	// are fitter than their parents. Otherwise methods are chosen uniformly.
+	AdaptiveOperatorSelection bool
+
+	// Whether or not the mutation rate adapts to the progress of the evolution.
+	// The rate doubles, up to at most 0.5, each generation that the best fitness
+	// doesn't improve, and halves back towards `MutationRate` each generation
+	// that it does.
+	AdaptiveMutationRate bool
+
+	// Whether or not the chromosomes and populations of previous generations are
+	// reused to breed new generations. Reuse avoids allocating every generation,
+	// but the evolver takes ownership of the population being evolved: a
+	// generation's chromosomes, except for its elites, are overwritten while the
+	// next generation is bred, so they must be cloned to be kept.
+	//
+	// This includes the chromosomes of the population passed to `Evolve`, which
+	// are recycled along with the chromosomes that the evolver breeds. Clone the
+	// population before evolving it if its chromosomes are used afterwards.
+	ReuseChromosomes bool
+
+	// The maximum number of fitness evaluations of an evolution, or zero for no
+	// limit. Evolution stops once the budget is spent, and chromosomes of the
+	// final generation that couldn't be evaluated within the budget are skipped.
+	MaxEvaluations int
+
+	// The maximum duration of a generation's evaluation, or zero for no limit.
+	// Once exceeded, the generation's remaining chromosomes are skipped so that
+	// evolution stays responsive when fitness is expensive to evaluate.
+	GenerationTimeout time.Duration
+
+	// How the fitness of skipped chromosomes is assigned.
+	SkippedFitnessPolicy SkippedFitnessPolicy
+
+	// How panics in the fitness function and other callbacks are handled.
+	PanicPolicy PanicPolicy
+
+	// How often elites, and other chromosomes that survive from a previous
+	// generation, are evaluated again, in generations. When zero, surviving
+	// chromosomes keep the fitness of their first evaluation, which may be
+	// stale when fitness is noisy or changes over time. When one, they're
+	// evaluated again every generation.
+	ReevaluateElites int
+
+	// How each generation of the population is replaced by the next.
+	ReplacementStrategy ReplacementStrategy
+
+	// The fraction of the population that's replaced by children each
+	// generation when using the generational replacement strategy, between a
+	// steady state, which replaces a single chromosome, and full generational
+	// replacement. If zero or one, then every chromosome other than the elites
+	// is replaced.
+	GenerationGap float64
+
+	// Which chromosomes are replaced by children when the generation gap
+	// replaces only part of the population.
+	GapReplacement GapReplacement
+
+	// Whether or not bounded genes are evolved in normalized form. When true,
+	// each bounded gene of the population is its position in [0, 1] within its
+	// bounds, on the bounds' scale, so operators behave alike for genes whose
+	// ranges differ by orders of magnitude. The fitness function is passed
+	// chromosomes whose genes are decoded to their bounds, and `DecodeGenes`
+	// decodes the genes of evolved chromosomes.
+	NormalizeGenes bool
+
+	// The indexes of genes that are frozen. Frozen genes aren't subject to
+	// crossover or mutation: bred chromosomes inherit them from their first
+	// parent, so they keep the values given to the initial population.
+	FrozenGenes []int
+
+	// Optional conditions that make genes active only when other genes take
+	// certain values. See `GeneCondition`.
+	Conditions []GeneCondition
+
+	// An optional schema that names genes. Chromosomes evaluated by an evolver
+	// are given the schema so that fitness functions can access their genes by
+	// name. See `Chromosome.Get`.
+	Schema *GeneSchema
+
+	// An optional comparator that orders chromosomes when sorting the
+	// population, choosing elites and holding tournaments. If nil, then
+	// chromosomes are compared by fitness. Comparators aren't serialized.
+	Comparator Comparator
+}
+
+// evolverConfigurationSpec is the serialized representation of an evolver
+// configuration.
+type evolverConfigurationSpec struct {
+	Selection            string          `json:"selection" yaml:"selection"`
+	Crossover            crossoverSpec   `json:"crossover" yaml:"crossover"`
+	Mutation             mutationSpec    `json:"mutation" yaml:"mutation"`
+	MateChoice           *mateChoiceSpec `json:"mate_choice" yaml:"mate_choice"`
+	FitnessScaling       *scalingSpec    `json:"fitness_scaling" yaml:"fitness_scaling"`
+	Crossovers           []crossoverSpec `json:"crossovers" yaml:"crossovers"`
+	Mutations            []mutationSpec  `json:"mutations" yaml:"mutations"`
+	Elitism              uint            `json:"elitism" yaml:"elitism"`
+	CrossoverRate        float64         `json:"crossover_rate" yaml:"crossover_rate"`
+	MutationRate         float64         `json:"mutation_rate" yaml:"mutation_rate"`
+	Bounds               []GeneBounds    `json:"bounds" yaml:"bounds"`
+	ElitismRate          float64         `json:"elitism_rate" yaml:"elitism_rate"`
+	AdaptiveMutationRate bool            `json:"adaptive_mutation_rate" yaml:"adaptive_mutation_rate"`
+
+	AdaptiveOperatorSelection bool   `json:"adaptive_operator_selection" yaml:"adaptive_operator_selection"`
+	InvalidFitnessPolicy      string `json:"invalid_fitness_policy" yaml:"invalid_fitness_policy"`
+	ReuseChromosomes          bool   `json:"reuse_chromosomes" yaml:"reuse_chromosomes"`
+	MaxEvaluations            int    `json:"max_evaluations" yaml:"max_evaluations"`
+	GenerationTimeout         string `json:"generation_timeout" yaml:"generation_timeout"`
+	SkippedFitnessPolicy      string `json:"skipped_fitness_policy" yaml:"skipped_fitness_policy"`
+	ReplacementStrategy       string `json:"replacement_strategy" yaml:"replacement_strategy"`
+	FrozenGenes               []int  `json:"frozen_genes" yaml:"frozen_genes"`
+	NormalizeGenes            bool   `json:"normalize_genes" yaml:"normalize_genes"`
+	PanicPolicy               string `json:"panic_policy" yaml:"panic_policy"`
+	ReevaluateElites          int    `json:"reevaluate_elites" yaml:"reevaluate_elites"`
+
+	GenerationGap  float64 `json:"generation_gap" yaml:"generation_gap"`
+	GapReplacement string  `json:"gap_replacement" yaml:"gap_replacement"`
+
+	Conditions []GeneCondition `json:"conditions" yaml:"conditions"`
+	GeneNames  []string        `json:"gene_names" yaml:"gene_names"`
+
+	Regularization *regularizationSpec `json:"regularization" yaml:"regularization"`
+}
+
+// crossoverSpec is the serialized representation of a crossover method.
+type crossoverSpec struct {
+	Method  string  `json:"method" yaml:"method"`
+	Points  int     `json:"points" yaml:"points"`
+	Alpha   float64 `json:"alpha" yaml:"alpha"`
+	Eta     float64 `json:"eta" yaml:"eta"`
+	Bias    float64 `json:"bias" yaml:"bias"`
+	Parents int     `json:"parents" yaml:"parents"`
+	Weight  float64 `json:"weight" yaml:"weight"`
+
+	BlockLength int `json:"block_length" yaml:"block_length"`
+
+	// The number of points of point crossover. Deprecated: use points.
+	Count int `json:"count" yaml:"count"`
+}
+
+// mutationSpec is the serialized representation of a mutation method.
+type mutationSpec struct {
+	Method string  `json:"method" yaml:"method"`
+	Scale  float64 `json:"scale" yaml:"scale"`
+}
+
+// mateChoiceSpec is the serialized representation of a mate choice.
+type mateChoiceSpec struct {
+	Selection       string  `json:"selection" yaml:"selection"`
+	Candidates      int     `json:"candidates" yaml:"candidates"`
+	MinimumDistance float64 `json:"minimum_distance" yaml:"minimum_distance"`
+}
+
+// scalingSpec is the serialized representation of a fitness scaling.
+type scalingSpec struct {
+	Method    string  `json:"method" yaml:"method"`
+	Parameter float64 `json:"parameter" yaml:"parameter"`
+}
+
+// regularizationSpec is the serialized representation of a regularization.
+type regularizationSpec struct {
+	Method   string    `json:"method" yaml:"method"`
+	Strength float64   `json:"strength" yaml:"strength"`
+	Prior    []float64 `json:"prior" yaml:"prior"`
 }
 
 // MARK: Constructors
 
 // NewEvolverConfiguration creates and returns a new evolver configuration.
-func NewEvolverConfiguration(selectionMethod *SelectionMethod, crossoverMethod *CrossoverMethod, elitism uint, crossoverRate float64, mutationRate float64) *EvolverConfiguration {
+func NewEvolverConfiguration(selectionMethod *SelectionMethod, crossoverMethod *CrossoverMethod, mutationMethod *MutationMethod, elitism uint, crossoverRate float64, mutationRate float64) *EvolverConfiguration {
 	return &EvolverConfiguration{
 		SelectionMethod: selectionMethod,
 		CrossoverMethod: crossoverMethod,
+		MutationMethod:  mutationMethod,
 		Elitism:         elitism,
 		CrossoverRate:   crossoverRate,
 		MutationRate:    mutationRate,
 	}
 }
+
+// DefaultEvolverConfiguration creates and returns a new evolver configuration
+// with reasonable defaults: tournament selection between three chromosomes,
+// uniform crossover, gaussian mutation with a scale of 0.1, 5% elitism, a
+// crossover rate of 0.9 and an adaptive mutation rate starting at 0.05.
+//
+// Set the configuration's `Bounds` to constrain genes and scale mutations to
+// their ranges.
+func DefaultEvolverConfiguration() *EvolverConfiguration {
+	return &EvolverConfiguration{
+		SelectionMethod:      NewTournamentSelectionMethod(3),
+		CrossoverMethod:      NewCrossoverMethod(CrossoverMethodTypeUniform, CrossoverOptions{}),
+		MutationMethod:       NewMutationMethod(MutationMethodTypeGaussian, 0.1),
+		ElitismRate:          0.05,
+		CrossoverRate:        0.9,
+		MutationRate:         0.05,
+		AdaptiveMutationRate: true,
+	}
+}
+
+// MARK: Public methods
+
+// Validate returns an error describing the first invalid value of the
+// configuration.
+func (c EvolverConfiguration) Validate() error {
+	if c.SelectionMethod == nil || c.SelectionMethod.Function == nil {
+		return fmt.Errorf("the configuration requires a selection method")
+	}
+
+	for _, m := range c.crossoverMethods() {
+		if m == nil || (m.Function == nil && m.MultiParentFunction == nil) {
+			return fmt.Errorf("the configuration requires a crossover method")
+		}
+
+		if m.Options.Points < 0 {
+			return fmt.Errorf("the number of crossover points must be non-negative")
+		}
+
+		if m.Options.Alpha < 0.0 || m.Options.Eta < 0.0 {
+			return fmt.Errorf("the crossover alpha and eta must be non-negative")
+		}
+
+		if m.Options.BlockLength < 0 {
+			return fmt.Errorf("the crossover block length must be non-negative")
+		}
+
+		if math.IsNaN(m.Options.Bias) || m.Options.Bias < 0.0 || m.Options.Bias > 1.0 {
+			return fmt.Errorf("the crossover bias %f must be in (0, 1], or zero for no bias", m.Options.Bias)
+		}
+
+		if m.ParentCount() < 2 {
+			return fmt.Errorf("the crossover method must select at least two parents")
+		}
+
+		if m.Weight < 0.0 {
+			return fmt.Errorf("the crossover method weight must be non-negative")
+		}
+	}
+
+	for _, m := range c.mutationMethods() {
+		if m == nil || m.Function == nil {
+			return fmt.Errorf("the configuration requires a mutation method")
+		}
+	}
+
+	if c.CrossoverRate < 0.0 || c.CrossoverRate > 1.0 {
+		return fmt.Errorf("the crossover rate %f must be in the range [0, 1]", c.CrossoverRate)
+	}
+
+	if c.MutationRate < 0.0 || c.MutationRate > 1.0 {
+		return fmt.Errorf("the mutation rate %f must be in the range [0, 1]", c.MutationRate)
+	}
+
+	if c.ElitismRate < 0.0 || c.ElitismRate > 1.0 {
+		return fmt.Errorf("the elitism rate %f must be in the range [0, 1]", c.ElitismRate)
+	}
+
+	if c.InvalidFitnessPolicy > InvalidFitnessPolicyRetry {
+		return fmt.Errorf("unknown invalid fitness policy %d", c.InvalidFitnessPolicy)
+	}
+
+	if c.FitnessScaling != nil && c.FitnessScaling.Function == nil {
+		return fmt.Errorf("the fitness scaling requires a function")
+	}
+
+	if c.Regularization != nil {
+		if c.Regularization.Function == nil {
+			return fmt.Errorf("the regularization requires a function")
+		}
+
+		if c.Regularization.Strength < 0.0 {
+			return fmt.Errorf("the regularization strength must be non-negative")
+		}
+	}
+
+	if c.MateChoice != nil {
+		if c.MateChoice.SelectionMethod != nil && c.MateChoice.SelectionMethod.Function == nil {
+			return fmt.Errorf("the mate choice selection method requires a function")
+		}
+
+		if c.MateChoice.MinimumDistance < 0.0 {
+			return fmt.Errorf("the mate choice minimum distance must be non-negative")
+		}
+	}
+
+	if c.MaxEvaluations < 0 {
+		return fmt.Errorf("the maximum number of evaluations must be non-negative")
+	}
+
+	if c.ReevaluateElites < 0 {
+		return fmt.Errorf("the elite re-evaluation interval must be non-negative")
+	}
+
+	if c.GenerationTimeout < 0 {
+		return fmt.Errorf("the generation timeout must be non-negative")
+	}
+
+	if c.SkippedFitnessPolicy > SkippedFitnessPolicyInherit {
+		return fmt.Errorf("unknown skipped fitness policy %d", c.SkippedFitnessPolicy)
+	}
+
+	if c.GenerationGap < 0.0 || c.GenerationGap > 1.0 {
+		return fmt.Errorf("the generation gap %f must be in the range [0, 1]", c.GenerationGap)
+	}
+
+	if c.GapReplacement > GapReplacementRandom {
+		return fmt.Errorf("unknown gap replacement %d", c.GapReplacement)
+	}
+
+	if c.PanicPolicy > PanicPolicyContinue {
+		return fmt.Errorf("unknown panic policy %d", c.PanicPolicy)
+	}
+
+	if c.ReplacementStrategy > ReplacementStrategyAgeFitnessPareto {
+		return fmt.Errorf("unknown replacement strategy %d", c.ReplacementStrategy)
+	}
+
+	for _, i := range c.FrozenGenes {
+		if i < 0 {
+			return fmt.Errorf("the frozen gene index %d must be non-negative", i)
+		}
+	}
+
+	for i, b := range c.Bounds {
+		if b.Min > b.Max {
+			return fmt.Errorf("the minimum of bounds %d is greater than its maximum", i)
+		}
+		if b.Scale > GeneScaleExp {
+			return fmt.Errorf("unknown scale %d of bounds %d", b.Scale, i)
+		}
+		if b.Scale == GeneScaleLog && b.Min <= 0.0 {
+			return fmt.Errorf("the minimum of log-scale bounds %d must be positive", i)
+		}
+		if b.Step < 0.0 {
+			return fmt.Errorf("the step of bounds %d must be non-negative", i)
+		}
+		if b.Step > 0.0 && math.Ceil(b.Min/b.Step)*b.Step > b.Max {
+			return fmt.Errorf("bounds %d don't contain a multiple of their step %g", i, b.Step)
+		}
+	}
+
+	for i, condition := range c.Conditions {
+		if condition.Gene < 0 || condition.Parent < 0 {
+			return fmt.Errorf("the gene indexes of condition %d must be non-negative", i)
+		}
+		if len(condition.Values) == 0 {
+			return fmt.Errorf("condition %d must have at least one value", i)
+		}
+	}
+
+	for _, condition := range c.Conditions {
+		if c.conditionsReach(condition.Parent, condition.Gene) {
+			return fmt.Errorf("the conditions of gene %d form a cycle", condition.Gene)
+		}
+	}
+
+	return nil
+}
+
+// BoundsForGene returns the bounds of the gene at index `i` and whether or not
+// the gene is bounded. If the configuration normalizes genes, then the bounds
+// of bounded genes are [0, 1].
+func (c EvolverConfiguration) BoundsForGene(i int) (GeneBounds, bool) {
+	bounds, ok := c.geneBounds(i)
+	if ok && c.NormalizeGenes {
+		return GeneBounds{Min: 0.0, Max: 1.0}, true
+	}
+	return bounds, ok
+}
+
+// ActiveGenes returns whether or not each of the genes of a chromosome evolved
+// with the configuration is active given the configuration's conditions, or
+// nil if every gene is active.
+func (c EvolverConfiguration) ActiveGenes(genes []float64) []bool {
+	if len(c.Conditions) == 0 {
+		return nil
+	}
+	return activeGenes(c.Conditions, c.DecodeGenes(genes))
+}
+
+// Diversity returns the genetic diversity of the population measured as in
+// `Population.Diversity`, but excluding genes that are inactive given the
+// configuration's conditions.
+func (c EvolverConfiguration) Diversity(population Population) float64 {
+	if len(c.Conditions) == 0 {
+		return population.Diversity()
+	}
+	return conditionalDiversity(population, c.ActiveGenes)
+}
+
+// DecodeGenes returns a copy of the genes of a chromosome evolved with the
+// configuration in which normalized genes are mapped to their bounds. If the
+// configuration doesn't normalize genes, then the copy is unchanged.
+func (c EvolverConfiguration) DecodeGenes(genes []float64) []float64 {
+	decoded := make([]float64, len(genes))
+	copy(decoded, genes)
+	if c.NormalizeGenes {
+		for i := range decoded {
+			if bounds, ok := c.geneBounds(i); ok {
+				decoded[i] = bounds.Denormalize(decoded[i])
+			}
+		}
+	}
+	return decoded
+}
+
+// EncodeGenes returns a copy of the genes in which genes are normalized within
+// their bounds, so that chromosomes with known genes can be seeded in to a
+// population evolved with the configuration. It's the inverse of
+// `DecodeGenes`.
+func (c EvolverConfiguration) EncodeGenes(genes []float64) []float64 {
+	encoded := make([]float64, len(genes))
+	copy(encoded, genes)
+	if c.NormalizeGenes {
+		for i := range encoded {
+			if bounds, ok := c.geneBounds(i); ok {
+				encoded[i] = bounds.Normalize(encoded[i])
+			}
+		}
+	}
+	return encoded
+}
+
+// EliteCount returns the number of elites that survive each generation of a
+// population of the given size.
+func (c EvolverConfiguration) EliteCount(populationSize int) int {
+	count := int(math.Ceil(c.ElitismRate * float64(populationSize)))
+	if int(c.Elitism) > count {
+		count = int(c.Elitism)
+	}
+	return count
+}
+
+// Summary returns a description of the configuration's methods and
+// hyperparameters that can be serialized, for example to record the
+// configuration of a run. Methods are described by the names of their types,
+// so custom methods are described as "custom".
+func (c EvolverConfiguration) Summary() map[string]interface{} {
+	summary := map[string]interface{}{
+		"elitism":                     c.Elitism,
+		"elitism_rate":                c.ElitismRate,
+		"crossover_rate":              c.CrossoverRate,
+		"mutation_rate":               c.MutationRate,
+		"adaptive_mutation_rate":      c.AdaptiveMutationRate,
+		"adaptive_operator_selection": c.AdaptiveOperatorSelection,
+		"invalid_fitness_policy":      c.InvalidFitnessPolicy.String(),
+		"skipped_fitness_policy":      c.SkippedFitnessPolicy.String(),
+		"panic_policy":                c.PanicPolicy.String(),
+		"reevaluate_elites":           c.ReevaluateElites,
+		"replacement_strategy":        c.ReplacementStrategy.String(),
+		"generation_gap":              c.GenerationGap,
+		"gap_replacement":             c.GapReplacement.String(),
+		"max_evaluations":             c.MaxEvaluations,
+		"generation_timeout":          c.GenerationTimeout.String(),
+		"normalize_genes":             c.NormalizeGenes,
+	}
+
+	if c.SelectionMethod != nil {
+		summary["selection"] = c.SelectionMethod.Type.String()
+	}
+
+	var crossovers []map[string]interface{}
+	for _, m := range c.crossoverMethods() {
+		if m != nil {
+			crossovers = append(crossovers, map[string]interface{}{
+				"method":       m.Type.String(),
+				"points":       m.Options.Points,
+				"alpha":        m.Options.Alpha,
+				"eta":          m.Options.Eta,
+				"bias":         m.Options.Bias,
+				"block_length": m.Options.BlockLength,
+				"parents":      m.ParentCount(),
+				"weight":       m.Weight,
+			})
+		}
+	}
+	summary["crossovers"] = crossovers
+
+	var mutations []map[string]interface{}
+	for _, m := range c.mutationMethods() {
+		if m != nil {
+			mutations = append(mutations, map[string]interface{}{
+				"method": m.Type.String(),
+				"scale":  m.Scale,
+			})
+		}
+	}
+	summary["mutations"] = mutations
+
+	if len(c.Bounds) > 0 {
+		summary["bounds"] = c.Bounds
+	}
+	if len(c.FrozenGenes) > 0 {
+		summary["frozen_genes"] = c.FrozenGenes
+	}
+	if len(c.Conditions) > 0 {
+		summary["conditions"] = c.Conditions
+	}
+	if c.Schema != nil {
+		summary["gene_names"] = c.Schema.Names()
+	}
+	if c.FitnessScaling != nil {
+		summary["fitness_scaling"] = map[string]interface{}{
+			"method":    c.FitnessScaling.Type.String(),
+			"parameter": c.FitnessScaling.Parameter,
+		}
+	}
+	if c.Regularization != nil {
+		summary["regularization"] = map[string]interface{}{
+			"method":   c.Regularization.Type.String(),
+			"strength": c.Regularization.Strength,
+			"prior":    c.Regularization.Prior,
+		}
+	}
+	if c.MateChoice != nil {
+		mateChoice := map[string]interface{}{
+			"candidates":       c.MateChoice.Candidates,
+			"minimum_distance": c.MateChoice.MinimumDistance,
+		}
+		if c.MateChoice.SelectionMethod != nil {
+			mateChoice["selection"] = c.MateChoice.SelectionMethod.Type.String()
+		}
+		summary["mate_choice"] = mateChoice
+	}
+	return summary
+}
+
+// UnmarshalJSON unmarshals a configuration from JSON. Selection and crossover
+// methods are given as specs accepted by `ParseSelectionMethod` and
+// `ParseCrossoverMethod`, and the resulting configuration is validated. Mutation
+// methods default to gaussian mutation with a scale of 0.1.
+func (c *EvolverConfiguration) UnmarshalJSON(data []byte) error {
+	spec := evolverConfigurationSpec{}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return err
+	}
+	return c.apply(spec)
+}
+
+// UnmarshalYAML unmarshals a configuration from YAML. Selection and crossover
+// methods are given as specs accepted by `ParseSelectionMethod` and
+// `ParseCrossoverMethod`, and the resulting configuration is validated. Mutation
+// methods default to gaussian mutation with a scale of 0.1.
+func (c *EvolverConfiguration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	spec := evolverConfigurationSpec{}
+	if err := unmarshal(&spec); err != nil {
+		return err
+	}
+	return c.apply(spec)
+}
+
+// MARK: Private methods
+
+// crossoverMethods returns the crossover methods that may be chosen for each
+// crossover.
+func (c EvolverConfiguration) crossoverMethods() []*CrossoverMethod {
+	if len(c.CrossoverMethods) > 0 {
+		return c.CrossoverMethods
+	}
+	return []*CrossoverMethod{c.CrossoverMethod}
+}
+
+// randomChromosome returns a chromosome of the given length with genes
+// uniformly distributed within their bounds. Unbounded genes are distributed
+// in [-1, 1].
+func (c EvolverConfiguration) randomChromosome(length int) *Chromosome {
+	chromosome := newChromosome(length, false, nil)
+	for j := range chromosome.Genes {
+		bounds, ok := c.BoundsForGene(j)
+		if !ok {
+			bounds = GeneBounds{Min: -1.0, Max: 1.0}
+		}
+		chromosome.Genes[j] = bounds.Random()
+	}
+	return chromosome
+}
+
+// clampGenes limits the genes to their bounds and rounds them to their bounds'
+// steps. Genes that share the same bounds are clamped in a single pass.
+func (c EvolverConfiguration) clampGenes(genes []float64) {
+	if c.NormalizeGenes && len(c.Bounds) > 0 {
+		n := len(c.Bounds)
+		if n == 1 {
+			n = len(genes)
+		}
+
+		for i := 0; i < len(genes) && i < n; i++ {
+			genes[i] = math.Max(0.0, math.Min(1.0, genes[i]))
+		}
+		return
+	}
+
+	if len(c.Bounds) == 1 && c.Bounds[0].Step <= 0.0 {
+		min, max := c.Bounds[0].Min, c.Bounds[0].Max
+		for i, g := range genes {
+			if g < min {
+				genes[i] = min
+			} else if g > max {
+				genes[i] = max
+			}
+		}
+		return
+	}
+
+	for i := 0; i < len(genes); i++ {
+		bounds, ok := c.geneBounds(i)
+		if !ok {
+			break
+		}
+		genes[i] = bounds.Quantize(genes[i])
+	}
+}
+
+// fitnessGenes returns a copy of the genes of a chromosome evolved with the
+// configuration as they're passed to the fitness function: decoded, and with
+// inactive genes replaced by NaN.
+func (c EvolverConfiguration) fitnessGenes(genes []float64) []float64 {
+	decoded := c.DecodeGenes(genes)
+	for i, active := range activeGenes(c.Conditions, decoded) {
+		if !active {
+			decoded[i] = math.NaN()
+		}
+	}
+	return decoded
+}
+
+// conditionsReach returns whether or not the gene at index `from` depends,
+// directly or through other conditions, on the gene at index `to`.
+func (c EvolverConfiguration) conditionsReach(from int, to int) bool {
+	visited := make(map[int]bool)
+	pending := []int{from}
+	for len(pending) > 0 {
+		i := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if i == to {
+			return true
+		}
+		if visited[i] {
+			continue
+		}
+
+		visited[i] = true
+		for _, condition := range c.Conditions {
+			if condition.Gene == i {
+				pending = append(pending, condition.Parent)
+			}
+		}
+	}
+	return false
+}
+
+// geneBounds returns the bounds of the gene at index `i` given by the
+// configuration's `Bounds`, and whether or not the gene is bounded.
+func (c EvolverConfiguration) geneBounds(i int) (GeneBounds, bool) {
+	switch {
+	case len(c.Bounds) == 1:
+		return c.Bounds[0], true
+	case i < len(c.Bounds):
+		return c.Bounds[i], true
+	default:
+		return GeneBounds{}, false
+	}
+}
+
+// frozenGenes returns whether or not each of `length` genes is frozen, or nil
+// if no genes are frozen.
+func (c EvolverConfiguration) frozenGenes(length int) []bool {
+	if len(c.FrozenGenes) == 0 {
+		return nil
+	}
+
+	frozen := make([]bool, length)
+	for _, i := range c.FrozenGenes {
+		if i >= 0 && i < length {
+			frozen[i] = true
+		}
+	}
+	return frozen
+}
+
+// mutationMethods returns the mutation methods that may be chosen for each bred
+// chromosome.
+func (c EvolverConfiguration) mutationMethods() []*MutationMethod {
+	if len(c.MutationMethods) > 0 {
+		return c.MutationMethods
+	}
+	return []*MutationMethod{c.MutationMethod}
+}
+
+// apply sets the configuration's values from the serialized representation.
+func (c *EvolverConfiguration) apply(spec evolverConfigurationSpec) error {
+	selectionMethod, err := ParseSelectionMethod(spec.Selection)
+	if err != nil {
+		return err
+	}
+
+	var crossoverMethods []*CrossoverMethod
+	for _, s := range spec.Crossovers {
+		m, err := s.method()
+		if err != nil {
+			return err
+		}
+		crossoverMethods = append(crossoverMethods, m)
+	}
+
+	var crossoverMethod *CrossoverMethod
+	if spec.Crossover.Method != "" || len(crossoverMethods) == 0 {
+		if crossoverMethod, err = spec.Crossover.method(); err != nil {
+			return err
+		}
+	} else {
+		crossoverMethod = crossoverMethods[0]
+	}
+
+	var mutationMethods []*MutationMethod
+	for _, s := range spec.Mutations {
+		m, err := s.method()
+		if err != nil {
+			return err
+		}
+		mutationMethods = append(mutationMethods, m)
+	}
+
+	mutationMethod, err := spec.Mutation.method()
+	if err != nil {
+		return err
+	}
+
+	var fitnessScaling *FitnessScaling
+	if spec.FitnessScaling != nil {
+		fitnessScaling, err = ParseFitnessScaling(spec.FitnessScaling.Method, spec.FitnessScaling.Parameter)
+		if err != nil {
+			return err
+		}
+	}
+
+	var regularization *Regularization
+	if spec.Regularization != nil {
+		regularization, err = ParseRegularization(spec.Regularization.Method, spec.Regularization.Strength, spec.Regularization.Prior)
+		if err != nil {
+			return err
+		}
+	}
+
+	var mateChoice *MateChoice
+	if spec.MateChoice != nil {
+		if mateChoice, err = spec.MateChoice.mateChoice(); err != nil {
+			return err
+		}
+	}
+
+	invalidFitnessPolicy, err := ParseInvalidFitnessPolicy(spec.InvalidFitnessPolicy)
+	if err != nil {
+		return err
+	}
+
+	skippedFitnessPolicy, err := ParseSkippedFitnessPolicy(spec.SkippedFitnessPolicy)
+	if err != nil {
+		return err
+	}
+
+	gapReplacement, err := ParseGapReplacement(spec.GapReplacement)
+	if err != nil {
+		return err
+	}
+
+	panicPolicy, err := ParsePanicPolicy(spec.PanicPolicy)
+	if err != nil {
+		return err
+	}
+
+	replacementStrategy, err := ParseReplacementStrategy(spec.ReplacementStrategy)
+	if err != nil {
+		return err
+	}
+
+	var schema *GeneSchema
+	if len(spec.GeneNames) > 0 {
+		if schema, err = NewGeneSchema(spec.GeneNames...); err != nil {
+			return err
+		}
+	}
+
+	var generationTimeout time.Duration
+	if spec.GenerationTimeout != "" {
+		if generationTimeout, err = time.ParseDuration(spec.GenerationTimeout); err != nil {
+			return fmt.Errorf("invalid generation timeout: %w", err)
+		}
+	}
+
+	configuration := EvolverConfiguration{
+		SelectionMethod: selectionMethod,
+		CrossoverMethod: crossoverMethod,
+		MutationMethod:  mutationMethod,
+		FitnessScaling:  fitnessScaling,
+		Regularization:  regularization,
+		MateChoice:      mateChoice,
+		Elitism:         spec.Elitism,
+		CrossoverRate:   spec.CrossoverRate,
+		MutationRate:    spec.MutationRate,
+		Bounds:          spec.Bounds,
+		ElitismRate:     spec.ElitismRate,
+
+		CrossoverMethods:     crossoverMethods,
+		MutationMethods:      mutationMethods,
+		AdaptiveMutationRate: spec.AdaptiveMutationRate,
+
+		AdaptiveOperatorSelection: spec.AdaptiveOperatorSelection,
+		InvalidFitnessPolicy:      invalidFitnessPolicy,
+		ReuseChromosomes:          spec.ReuseChromosomes,
+		MaxEvaluations:            spec.MaxEvaluations,
+		GenerationTimeout:         generationTimeout,
+		SkippedFitnessPolicy:      skippedFitnessPolicy,
+		PanicPolicy:               panicPolicy,
+		ReevaluateElites:          spec.ReevaluateElites,
+		GenerationGap:             spec.GenerationGap,
+		GapReplacement:            gapReplacement,
+		ReplacementStrategy:       replacementStrategy,
+		FrozenGenes:               spec.FrozenGenes,
+		NormalizeGenes:            spec.NormalizeGenes,
+		Conditions:                spec.Conditions,
+		Schema:                    schema,
+	}
+
+	if err := configuration.Validate(); err != nil {
+		return err
+	}
+
+	*c = configuration
+	return nil
+}
+
+// method returns the crossover method described by the spec.
+func (s crossoverSpec) method() (*CrossoverMethod, error) {
+	m, err := ParseCrossoverMethod(s.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Points > 0 {
+		m.Options.Points = s.Points
+	} else if s.Count > 0 {
+		m.Options.Points = s.Count
+	}
+
+	if s.Alpha > 0.0 {
+		m.Options.Alpha = s.Alpha
+	}
+
+	if s.Eta > 0.0 {
+		m.Options.Eta = s.Eta
+	}
+
+	if s.Bias > 0.0 {
+		m.Options.Bias = s.Bias
+	}
+
+	if s.BlockLength > 0 {
+		m.Options.BlockLength = s.BlockLength
+	}
+
+	if s.Parents > 0 && m.MultiParentFunction != nil {
+		m.Parents = s.Parents
+	}
+
+	m.Weight = s.Weight
+
+	return m, nil
+}
+
+// method returns the mutation method described by the spec. The method defaults
+// to gaussian mutation and the scale defaults to 0.1.
+func (s mutationSpec) method() (*MutationMethod, error) {
+	if s.Method == "" {
+		s.Method = "gaussian"
+	}
+
+	if s.Scale == 0.0 {
+		s.Scale = 0.1
+	}
+
+	return ParseMutationMethod(s.Method, s.Scale)
+}
+
+// mateChoice returns the mate choice described by the spec.
+func (s mateChoiceSpec) mateChoice() (*MateChoice, error) {
+	var selectionMethod *SelectionMethod
+	if s.Selection != "" {
+		m, err := ParseSelectionMethod(s.Selection)
+		if err != nil {
+			return nil, err
+		}
+		selectionMethod = m
+	}
+
+	return NewMateChoice(selectionMethod, s.Candidates, s.MinimumDistance), nil
+}
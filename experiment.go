@@ -0,0 +1,310 @@
+package genetics
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// Experiment types compare evolver configurations by evolving several
+// populations with every combination of a set of hyperparameter values.
+//
+// Each run seeds the package's random number generator, see `Random`, with
+// the experiment's seed plus the index of the run, so the n-th run of every
+// configuration starts from the same population and comparisons between
+// configurations are fair. Because of this, experiments shouldn't be run
+// concurrently with other work that uses the generator.
+type Experiment struct {
+	// The configuration that the parameters are applied to.
+	Configuration *EvolverConfiguration
+
+	// The fitness function that evaluates chromosomes.
+	FitnessFunction FitnessFunction
+
+	// The hyperparameters to vary. Every combination of their values is
+	// evaluated.
+	Parameters []ExperimentParameter
+
+	// The size of each run's population and the length of its chromosomes.
+	PopulationSize   uint
+	ChromosomeLength uint
+
+	// The function that generates the genes of each run's initial population. It
+	// should draw random numbers from `Random`. If nil, genes are uniformly
+	// distributed within the configuration's bounds, or in [-1, 1] if unbounded.
+	GeneratingFunction func(i, j int) float64
+
+	// The maximum number of generations of each run.
+	Generations int
+
+	// The number of runs of each configuration.
+	Runs int
+
+	// The seed of the first run.
+	Seed int64
+
+	// An optional fitness that stops a run once reached. Runs that reach it are
+	// counted as successful.
+	TargetFitness *float64
+
+	// An optional name of the experiment used to identify its recorded runs.
+	Name string
+
+	// An optional recorder that each run is recorded to once it finishes, such
+	// as a results database.
+	Recorder RunRecorder
+}
+
+// RunRecorder types record the runs of experiments.
+type RunRecorder interface {
+	RecordRun(record RunRecord) error
+}
+
+// RunRecord objects describe a finished run of an experiment.
+type RunRecord struct {
+	// The name of the experiment.
+	Experiment string
+
+	// The value of each of the experiment's parameters, by name.
+	Parameters map[string]float64
+
+	// The configuration that the parameters were applied to.
+	Configuration *EvolverConfiguration
+
+	// The index of the run among the runs of its configuration, and the seed
+	// that the package's random number generator was seeded with.
+	Run  int
+	Seed int64
+
+	// The statistics of each generation of the run.
+	Stats EvolutionStats
+
+	// The total number of fitness evaluations performed.
+	Evaluations int
+
+	// The best chromosome of the run.
+	Best *Chromosome
+}
+
+// ExperimentParameter objects define a hyperparameter varied by an experiment.
+type ExperimentParameter struct {
+	// The name of the parameter used in reports.
+	Name string
+
+	// The values of the parameter to evaluate.
+	Values []float64
+
+	// The function that applies a value of the parameter to a configuration.
+	Apply func(configuration *EvolverConfiguration, value float64)
+}
+
+// ExperimentResult objects describe the runs of a single configuration of an
+// experiment.
+type ExperimentResult struct {
+	// The value of each of the experiment's parameters.
+	Values []float64
+
+	// The configuration that the values were applied to.
+	Configuration *EvolverConfiguration
+
+	// The best fitness of each run.
+	BestFitnesses []float64
+
+	// The number of generations evolved by each run.
+	Generations []int
+
+	// The mean and standard deviation of the runs' best fitness.
+	MeanBestFitness   float64
+	BestFitnessStdDev float64
+
+	// The fraction of runs that reached the experiment's target fitness.
+	SuccessRate float64
+}
+
+// MARK: Constructors
+
+// NewExperiment creates and returns a new experiment that evolves `runs`
+// populations for up to `generations` generations with each combination of
+// the parameters' values.
+func NewExperiment(configuration *EvolverConfiguration, fitnessFunction FitnessFunction, populationSize uint, chromosomeLength uint, generations int, runs int, parameters ...ExperimentParameter) *Experiment {
+	return &Experiment{
+		Configuration:    configuration,
+		FitnessFunction:  fitnessFunction,
+		Parameters:       parameters,
+		PopulationSize:   populationSize,
+		ChromosomeLength: chromosomeLength,
+		Generations:      generations,
+		Runs:             runs,
+	}
+}
+
+// MARK: Public methods
+
+// Run runs the experiment and returns a result for each combination of the
+// parameters' values. Combinations are ordered with the first parameter
+// varying fastest.
+func (e Experiment) Run() ([]ExperimentResult, error) {
+	for _, p := range e.Parameters {
+		if len(p.Values) == 0 {
+			return nil, fmt.Errorf("parameter %s has no values", p.Name)
+		}
+	}
+
+	var results []ExperimentResult
+	indexes := make([]int, len(e.Parameters))
+	for {
+		values := make([]float64, len(e.Parameters))
+		configuration := *e.Configuration
+		for i, p := range e.Parameters {
+			values[i] = p.Values[indexes[i]]
+			p.Apply(&configuration, values[i])
+		}
+
+		if err := configuration.Validate(); err != nil {
+			return results, fmt.Errorf("invalid configuration %s: %w", e.describe(values), err)
+		}
+
+		result, err := e.runConfiguration(&configuration, values)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+
+		i := 0
+		for ; i < len(indexes); i++ {
+			if indexes[i]++; indexes[i] < len(e.Parameters[i].Values) {
+				break
+			}
+			indexes[i] = 0
+		}
+		if i == len(indexes) {
+			return results, nil
+		}
+	}
+}
+
+// WriteReport writes a table of the results to the writer with a column for
+// each of the experiment's parameters.
+func (e Experiment) WriteReport(w io.Writer, results []ExperimentResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	var header []string
+	for _, p := range e.Parameters {
+		header = append(header, p.Name)
+	}
+	header = append(header, "mean_best_fitness", "stddev", "success_rate")
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	for _, r := range results {
+		var row []string
+		for _, v := range r.Values {
+			row = append(row, fmt.Sprintf("%g", v))
+		}
+		row = append(row,
+			fmt.Sprintf("%f", r.MeanBestFitness),
+			fmt.Sprintf("%f", r.BestFitnessStdDev),
+			fmt.Sprintf("%.2f", r.SuccessRate),
+		)
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// MARK: Private methods
+
+// runConfiguration evolves the experiment's runs with the configuration.
+func (e Experiment) runConfiguration(configuration *EvolverConfiguration, values []float64) (ExperimentResult, error) {
+	result := ExperimentResult{
+		Values:        values,
+		Configuration: configuration,
+	}
+
+	evolver := NewEvolver(configuration, e.FitnessFunction)
+	successes := 0
+	for run := 0; run < e.Runs; run++ {
+		random.Seed(e.Seed + int64(run))
+
+		generations := 0
+		var last *EvolutionState
+		_, best, err := evolver.Evolve(e.population(configuration), func(state *EvolutionState) bool {
+			generations = state.Generation
+			last = state
+			if e.TargetFitness != nil && len(state.Population) > 0 && state.Population[len(state.Population)-1].Fitness >= *e.TargetFitness {
+				return false
+			}
+			return state.Generation < e.Generations
+		})
+		if err != nil {
+			return result, fmt.Errorf("run %d of configuration %s: %w", run, e.describe(values), err)
+		}
+
+		if err = e.record(configuration, values, run, last, best); err != nil {
+			return result, fmt.Errorf("unable to record run %d of configuration %s: %w", run, e.describe(values), err)
+		}
+
+		result.BestFitnesses = append(result.BestFitnesses, best.Fitness)
+		result.Generations = append(result.Generations, generations)
+		if e.TargetFitness != nil && best.Fitness >= *e.TargetFitness {
+			successes++
+		}
+	}
+
+	if e.Runs > 0 {
+		result.MeanBestFitness, result.BestFitnessStdDev = stat.MeanStdDev(result.BestFitnesses, nil)
+		if e.Runs == 1 {
+			result.BestFitnessStdDev = 0.0
+		}
+		result.SuccessRate = float64(successes) / float64(e.Runs)
+	}
+	return result, nil
+}
+
+// record records a finished run with the experiment's recorder.
+func (e Experiment) record(configuration *EvolverConfiguration, values []float64, run int, state *EvolutionState, best *Chromosome) error {
+	if e.Recorder == nil {
+		return nil
+	}
+
+	record := RunRecord{
+		Experiment:    e.Name,
+		Parameters:    make(map[string]float64, len(values)),
+		Configuration: configuration,
+		Run:           run,
+		Seed:          e.Seed + int64(run),
+		Best:          best,
+	}
+	for i, v := range values {
+		record.Parameters[e.Parameters[i].Name] = v
+	}
+	if state != nil {
+		record.Stats = state.Stats
+		record.Evaluations = state.Evaluations
+	}
+	return e.Recorder.RecordRun(record)
+}
+
+// population returns the initial population of a run.
+func (e Experiment) population(configuration *EvolverConfiguration) Population {
+	if e.GeneratingFunction != nil {
+		return GeneratePopulation(e.PopulationSize, e.ChromosomeLength, e.GeneratingFunction)
+	}
+
+	population := make(Population, e.PopulationSize)
+	for i := range population {
+		population[i] = configuration.randomChromosome(int(e.ChromosomeLength))
+	}
+	return population
+}
+
+// describe returns a description of the parameter values.
+func (e Experiment) describe(values []float64) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprintf("%s=%g", e.Parameters[i].Name, v)
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
@@ -0,0 +1,173 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// FitnessAggregationType represents a type of fitness aggregation.
+type FitnessAggregationType uint
+
+// Types of fitness aggregation.
+const (
+	FitnessAggregationTypeMean   FitnessAggregationType = 0
+	FitnessAggregationTypeMin    FitnessAggregationType = 1
+	FitnessAggregationTypeMedian FitnessAggregationType = 2
+	FitnessAggregationTypeCustom FitnessAggregationType = 3
+)
+
+// FitnessAggregationFunction takes the fitness of a chromosome on each of
+// several data segments and returns its overall fitness.
+type FitnessAggregationFunction func(fitnesses []float64) float64
+
+// SegmentFitnessFunction defines a fitness function that evaluates a
+// chromosome on one of several data segments, such as charts or folds of a
+// series.
+type SegmentFitnessFunction func(chromosome *Chromosome, segment int, state *EvolutionState) float64
+
+// CaseErrorFunction defines a function that returns the error of a chromosome
+// on each of several fitness cases, such as the trading error on each candle.
+type CaseErrorFunction func(chromosome *Chromosome, state *EvolutionState) []float64
+
+// FitnessAggregation wraps an aggregation type and function together.
+type FitnessAggregation struct {
+	Type     FitnessAggregationType
+	Function FitnessAggregationFunction
+}
+
+// MARK: Constructors
+
+// NewFitnessAggregation creates a new fitness aggregation from the given type.
+// To use a custom function, use the `NewCustomFitnessAggregation` constructor.
+func NewFitnessAggregation(t FitnessAggregationType) *FitnessAggregation {
+	return &FitnessAggregation{
+		Type:     t,
+		Function: fitnessAggregationFunctionForType(t),
+	}
+}
+
+// NewCustomFitnessAggregation creates a new custom fitness aggregation from the
+// provided function.
+func NewCustomFitnessAggregation(f FitnessAggregationFunction) *FitnessAggregation {
+	return &FitnessAggregation{
+		Type:     FitnessAggregationTypeCustom,
+		Function: f,
+	}
+}
+
+// ParseFitnessAggregation creates a new fitness aggregation from an
+// aggregation name. Valid names are "mean", "min" and "median".
+func ParseFitnessAggregation(name string) (*FitnessAggregation, error) {
+	switch strings.ToLower(name) {
+	case "mean":
+		return NewFitnessAggregation(FitnessAggregationTypeMean), nil
+	case "min":
+		return NewFitnessAggregation(FitnessAggregationTypeMin), nil
+	case "median":
+		return NewFitnessAggregation(FitnessAggregationTypeMedian), nil
+	default:
+		return nil, fmt.Errorf("unknown fitness aggregation %q", name)
+	}
+}
+
+// MARK: Public functions
+
+// MeanAggregationFunction returns the mean fitness.
+var MeanAggregationFunction FitnessAggregationFunction = func(fitnesses []float64) float64 {
+	if len(fitnesses) == 0 {
+		return 0.0
+	}
+
+	sum := 0.0
+	for _, f := range fitnesses {
+		sum += f
+	}
+	return sum / float64(len(fitnesses))
+}
+
+// MinAggregationFunction returns the lowest fitness, which rewards chromosomes
+// that perform well on every segment.
+var MinAggregationFunction FitnessAggregationFunction = func(fitnesses []float64) float64 {
+	if len(fitnesses) == 0 {
+		return 0.0
+	}
+
+	min := math.Inf(1)
+	for _, f := range fitnesses {
+		min = math.Min(min, f)
+	}
+	return min
+}
+
+// MedianAggregationFunction returns the median fitness, which is robust to
+// segments with outlying fitness. The fitnesses are sorted in place.
+var MedianAggregationFunction FitnessAggregationFunction = func(fitnesses []float64) float64 {
+	n := len(fitnesses)
+	if n == 0 {
+		return 0.0
+	}
+
+	sort.Float64s(fitnesses)
+	if n%2 == 1 {
+		return fitnesses[n/2]
+	}
+	return (fitnesses[n/2-1] + fitnesses[n/2]) / 2.0
+}
+
+// NewSegmentedFitnessFunction returns a fitness function that evaluates
+// chromosomes on each of the given number of segments and aggregates their
+// fitness, so that optimized chromosomes generalize across segments rather
+// than overfitting one of them. Use it as the fitness function of an evolver
+// or optimizer.
+func NewSegmentedFitnessFunction(segments int, fitnessFunction SegmentFitnessFunction, aggregation *FitnessAggregation) FitnessFunction {
+	return func(chromosome *Chromosome, state *EvolutionState) float64 {
+		fitnesses := make([]float64, segments)
+		for i := range fitnesses {
+			fitnesses[i] = fitnessFunction(chromosome, i, state)
+		}
+		return aggregation.Function(fitnesses)
+	}
+}
+
+// NewKFoldFitnessFunction returns a fitness function that splits a series of
+// data points in to `k` contiguous folds of near-equal length, evaluates
+// chromosomes on each fold and aggregates their fitness.
+func NewKFoldFitnessFunction(seriesLength int, k int, fitnessFunction WindowedFitnessFunction, aggregation *FitnessAggregation) FitnessFunction {
+	return NewSegmentedFitnessFunction(k, func(chromosome *Chromosome, segment int, state *EvolutionState) float64 {
+		return fitnessFunction(chromosome, segment*seriesLength/k, (segment+1)*seriesLength/k, state)
+	}, aggregation)
+}
+
+// NewCaseFitnessFunction returns a fitness function that records the error of
+// each chromosome on each fitness case in its `CaseErrors`, and aggregates the
+// negated errors as its fitness. Case errors are used by lexicase selection.
+func NewCaseFitnessFunction(f CaseErrorFunction, aggregation *FitnessAggregation) FitnessFunction {
+	return func(chromosome *Chromosome, state *EvolutionState) float64 {
+		chromosome.CaseErrors = append(chromosome.CaseErrors[:0], f(chromosome, state)...)
+
+		fitnesses := make([]float64, len(chromosome.CaseErrors))
+		for i, e := range chromosome.CaseErrors {
+			fitnesses[i] = -e
+		}
+		return aggregation.Function(fitnesses)
+	}
+}
+
+// MARK: Private functions
+
+// fitnessAggregationFunctionForType returns the fitness aggregation function
+// for the given type.
+func fitnessAggregationFunctionForType(t FitnessAggregationType) FitnessAggregationFunction {
+	switch t {
+	case FitnessAggregationTypeMean:
+		return MeanAggregationFunction
+	case FitnessAggregationTypeMin:
+		return MinAggregationFunction
+	case FitnessAggregationTypeMedian:
+		return MedianAggregationFunction
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,87 @@
+package genetics
+
+import "math"
+
+// Fitness types are composite fitness values made of a scalar score and named
+// components that describe it.
+type Fitness struct {
+	// The scalar fitness that evolvers maximize.
+	Score float64
+
+	// The named components of the fitness, such as profit, drawdown and trade
+	// count.
+	Components map[string]float64
+}
+
+// ComponentFitnessFunction types return the composite fitness of a chromosome.
+type ComponentFitnessFunction func(chromosome *Chromosome, state *EvolutionState) Fitness
+
+// ComponentKey types describe a fitness component that a comparator orders
+// chromosomes by.
+type ComponentKey struct {
+	// The name of the component.
+	Name string
+
+	// Whether or not lower values of the component are better.
+	Minimize bool
+
+	// The amount by which values of the component must differ to be unequal.
+	Epsilon float64
+}
+
+// componentComparator types compare chromosomes' fitness components in order.
+type componentComparator struct {
+	keys []ComponentKey
+}
+
+// MARK: Public methods
+
+// Compare compares the chromosomes' components in order, then their fitness.
+func (c componentComparator) Compare(a, b *Chromosome) int {
+	for _, key := range c.keys {
+		x, y := component(a, key.Name), component(b, key.Name)
+		if key.Minimize {
+			x, y = -x, -y
+		}
+
+		if result := compareValues(x, y, key.Epsilon); result != 0 {
+			return result
+		}
+	}
+	return compareValues(a.Fitness, b.Fitness, 0.0)
+}
+
+// MARK: Public functions
+
+// NewComponentFitnessFunction returns a fitness function that records the
+// components of each chromosome's composite fitness in its `Components`, and
+// returns its score as its fitness. Components are available to observers of
+// the evolution, to comparators and to exports of the chromosome and its
+// generation's statistics.
+func NewComponentFitnessFunction(f ComponentFitnessFunction) FitnessFunction {
+	return func(chromosome *Chromosome, state *EvolutionState) float64 {
+		fitness := f(chromosome, state)
+		chromosome.Components = copyComponents(fitness.Components)
+		return fitness.Score
+	}
+}
+
+// NewComponentComparator returns a comparator that compares chromosomes'
+// fitness components in order of priority, so that a later component only
+// decides between chromosomes whose earlier components are equal. Chromosomes
+// whose components are all equal are compared by fitness. Missing and NaN
+// components are worse than every other value.
+func NewComponentComparator(keys ...ComponentKey) Comparator {
+	return componentComparator{keys: keys}
+}
+
+// MARK: Private functions
+
+// component returns the chromosome's named fitness component, or NaN if it
+// doesn't have the component.
+func component(c *Chromosome, name string) float64 {
+	if value, ok := c.Components[name]; ok {
+		return value
+	}
+	return math.NaN()
+}
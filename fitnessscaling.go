@@ -0,0 +1,179 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// FitnessScalingType represents a type of fitness scaling.
+type FitnessScalingType uint
+
+// Types of fitness scaling.
+const (
+	FitnessScalingTypeLinear FitnessScalingType = 0
+	FitnessScalingTypeSigma  FitnessScalingType = 1
+	FitnessScalingTypePower  FitnessScalingType = 2
+	FitnessScalingTypeCustom FitnessScalingType = 3
+)
+
+// FitnessScalingFunction takes a population of evaluated chromosomes and returns
+// the selection weight of each chromosome.
+type FitnessScalingFunction func(population Population, parameter float64) []float64
+
+// FitnessScaling wraps a scaling type, function and parameter together.
+type FitnessScaling struct {
+	Type      FitnessScalingType
+	Function  FitnessScalingFunction
+	Parameter float64
+}
+
+// MARK: String methods
+
+func (t FitnessScalingType) String() string {
+	switch t {
+	case FitnessScalingTypeLinear:
+		return "linear"
+	case FitnessScalingTypeSigma:
+		return "sigma"
+	case FitnessScalingTypePower:
+		return "power"
+	default:
+		return "custom"
+	}
+}
+
+// MARK: Constructors
+
+// NewFitnessScaling creates a new fitness scaling from the given fitness
+// scaling type and parameter. To use a custom function, use the
+// `NewCustomFitnessScaling` constructor.
+func NewFitnessScaling(t FitnessScalingType, parameter float64) *FitnessScaling {
+	return &FitnessScaling{
+		Type:      t,
+		Function:  fitnessScalingFunctionForType(t),
+		Parameter: parameter,
+	}
+}
+
+// NewCustomFitnessScaling creates a new custom fitness scaling from the provided
+// fitness scaling function and parameter.
+func NewCustomFitnessScaling(f FitnessScalingFunction, parameter float64) *FitnessScaling {
+	return &FitnessScaling{
+		Type:      FitnessScalingTypeCustom,
+		Function:  f,
+		Parameter: parameter,
+	}
+}
+
+// ParseFitnessScaling creates a new fitness scaling with the given parameter
+// from a scaling name. Valid names are "linear", "sigma" and "power".
+func ParseFitnessScaling(name string, parameter float64) (*FitnessScaling, error) {
+	switch strings.ToLower(name) {
+	case "linear":
+		return NewFitnessScaling(FitnessScalingTypeLinear, parameter), nil
+	case "sigma":
+		return NewFitnessScaling(FitnessScalingTypeSigma, parameter), nil
+	case "power":
+		return NewFitnessScaling(FitnessScalingTypePower, parameter), nil
+	default:
+		return nil, fmt.Errorf("unknown fitness scaling %q", name)
+	}
+}
+
+// MARK: Public methods
+
+// Scale sets the selection weight of each chromosome in the population to its
+// scaled fitness.
+func (s FitnessScaling) Scale(population Population) {
+	weights := s.Function(population, s.Parameter)
+	for i := 0; i < len(population) && i < len(weights); i++ {
+		population[i].weight = weights[i]
+	}
+}
+
+// MARK: Public functions
+
+// LinearScalingFunction implements linear fitness scaling. Fitness is scaled so
+// that the mean fitness is preserved and the best fitness becomes `parameter`
+// times the mean, which is the target selection pressure. If that would make
+// any weight negative, then the scaling instead maps the worst fitness to zero.
+var LinearScalingFunction FitnessScalingFunction = func(population Population, parameter float64) []float64 {
+	weights := make([]float64, len(population))
+	if len(population) == 0 {
+		return weights
+	}
+
+	min, max := math.MaxFloat64, -math.MaxFloat64
+	for _, c := range population {
+		min = math.Min(min, c.Fitness)
+		max = math.Max(max, c.Fitness)
+	}
+	mean := population.SumFitnesses() / float64(len(population))
+
+	a, b := 1.0, 0.0
+	if parameter > 1.0 && min > (parameter*mean-max)/(parameter-1.0) {
+		if delta := max - mean; delta > 0.0 {
+			a = (parameter - 1.0) * mean / delta
+			b = mean * (max - parameter*mean) / delta
+		}
+	} else if delta := mean - min; delta > 0.0 {
+		a = mean / delta
+		b = -min * mean / delta
+	}
+
+	for i, c := range population {
+		weights[i] = a*c.Fitness + b
+	}
+	return weights
+}
+
+// SigmaScalingFunction implements sigma truncation. Each weight is the
+// chromosome's fitness less the mean fitness plus `parameter` standard
+// deviations, truncated at zero.
+var SigmaScalingFunction FitnessScalingFunction = func(population Population, parameter float64) []float64 {
+	weights := make([]float64, len(population))
+	if len(population) == 0 {
+		return weights
+	}
+
+	mean := population.SumFitnesses() / float64(len(population))
+	variance := 0.0
+	for _, c := range population {
+		variance += (c.Fitness - mean) * (c.Fitness - mean)
+	}
+	sigma := math.Sqrt(variance / float64(len(population)))
+
+	for i, c := range population {
+		weights[i] = math.Max(0.0, c.Fitness-(mean-parameter*sigma))
+	}
+	return weights
+}
+
+// PowerScalingFunction implements power law scaling. Each weight is the
+// chromosome's fitness raised to the power of `parameter`. Negative fitness is
+// treated as zero.
+var PowerScalingFunction FitnessScalingFunction = func(population Population, parameter float64) []float64 {
+	weights := make([]float64, len(population))
+	for i, c := range population {
+		weights[i] = math.Pow(math.Max(0.0, c.Fitness), parameter)
+	}
+	return weights
+}
+
+// MARK: Private functions
+
+// fitnessScalingFunctionForType returns the fitness scaling function for the
+// given type.
+func fitnessScalingFunctionForType(t FitnessScalingType) FitnessScalingFunction {
+	switch t {
+	case FitnessScalingTypeLinear:
+		return LinearScalingFunction
+	case FitnessScalingTypeSigma:
+		return SigmaScalingFunction
+	case FitnessScalingTypePower:
+		return PowerScalingFunction
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,187 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+)
+
+// GeneScale represents the scale that a gene's values are distributed on
+// within its bounds.
+type GeneScale uint
+
+// Gene scales.
+const (
+	// Values are distributed evenly between the bounds.
+	GeneScaleLinear GeneScale = 0
+
+	// The logarithms of values are distributed evenly between the logarithms of
+	// the bounds, so each order of magnitude is equally represented. The
+	// minimum of log-scale bounds must be positive; bounds whose minimum isn't
+	// are treated as linear.
+	GeneScaleLog GeneScale = 1
+
+	// The exponentials of values are distributed evenly between the
+	// exponentials of the bounds, so values are concentrated towards the
+	// maximum. It's the inverse of the log scale.
+	GeneScaleExp GeneScale = 2
+)
+
+// GeneBounds objects define the closed interval that the value of a gene lies
+// in.
+type GeneBounds struct {
+	Min float64 `json:"min" yaml:"min"`
+	Max float64 `json:"max" yaml:"max"`
+
+	// The scale of the gene's values. Random genes are distributed evenly on
+	// the scale, mutations are made on the scale and genes are normalized on the
+	// scale. See `EvolverConfiguration.NormalizeGenes`.
+	Scale GeneScale `json:"scale,omitempty" yaml:"scale,omitempty"`
+
+	// An optional step that the gene's values are multiples of, such as 0.5,
+	// or 1 for integers. Random genes, mutated genes and decoded genes are
+	// rounded to the nearest multiple within the bounds. Zero if the values are
+	// continuous.
+	Step float64 `json:"step,omitempty" yaml:"step,omitempty"`
+}
+
+// MARK: String methods
+
+func (s GeneScale) String() string {
+	switch s {
+	case GeneScaleLinear:
+		return "linear"
+	case GeneScaleLog:
+		return "log"
+	case GeneScaleExp:
+		return "exp"
+	default:
+		return "unknown"
+	}
+}
+
+// MARK: Public methods
+
+// Contains returns whether or not the value lies within the bounds.
+func (b GeneBounds) Contains(value float64) bool {
+	return value >= b.Min && value <= b.Max
+}
+
+// Clamp returns the value limited to the bounds.
+func (b GeneBounds) Clamp(value float64) float64 {
+	return math.Max(b.Min, math.Min(b.Max, value))
+}
+
+// Quantize returns the value limited to the bounds and rounded to the nearest
+// multiple of the bounds' step that lies within them. If the bounds don't have
+// a step, or no multiple of it lies within them, then the value is only limited
+// to the bounds.
+func (b GeneBounds) Quantize(value float64) float64 {
+	if b.Step <= 0.0 {
+		return b.Clamp(value)
+	}
+
+	low, high := math.Ceil(b.Min/b.Step)*b.Step, math.Floor(b.Max/b.Step)*b.Step
+	if low > high {
+		return b.Clamp(value)
+	}
+	return math.Max(low, math.Min(high, math.Round(value/b.Step)*b.Step))
+}
+
+// Normalize returns the position of the value within the bounds on the
+// bounds' scale, in [0, 1]. Values outside of the bounds are clamped.
+func (b GeneBounds) Normalize(value float64) float64 {
+	min, max, value := b.Min, b.Max, b.Clamp(value)
+	switch b.scale() {
+	case GeneScaleLog:
+		min, max, value = math.Log(min), math.Log(max), math.Log(value)
+	case GeneScaleExp:
+		// Exponentials are taken relative to the maximum to avoid overflow.
+		min, max, value = math.Exp(min-b.Max), 1.0, math.Exp(value-b.Max)
+	}
+
+	if max <= min {
+		return 0.0
+	}
+	return (value - min) / (max - min)
+}
+
+// Denormalize returns the value at the position, in [0, 1], within the bounds
+// on the bounds' scale, rounded to the bounds' step. Other than rounding, it's
+// the inverse of `Normalize`. Positions outside of [0, 1] are clamped.
+func (b GeneBounds) Denormalize(position float64) float64 {
+	position = math.Max(0.0, math.Min(1.0, position))
+
+	var value float64
+	switch b.scale() {
+	case GeneScaleLog:
+		value = b.Clamp(math.Exp(math.Log(b.Min) + position*(math.Log(b.Max)-math.Log(b.Min))))
+	case GeneScaleExp:
+		min := math.Exp(b.Min - b.Max)
+		value = b.Clamp(b.Max + math.Log(min+position*(1.0-min)))
+	default:
+		value = b.Min + position*(b.Max-b.Min)
+	}
+
+	if b.Step > 0.0 {
+		return b.Quantize(value)
+	}
+	return value
+}
+
+// Random returns a random value within the bounds that's uniformly distributed
+// on the bounds' scale.
+func (b GeneBounds) Random() float64 {
+	return b.randomFrom(random)
+}
+
+// MarshalText encodes the scale as its name.
+func (s GeneScale) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes the scale from its name. See `ParseGeneScale`.
+func (s *GeneScale) UnmarshalText(text []byte) error {
+	scale, err := ParseGeneScale(string(text))
+	if err != nil {
+		return err
+	}
+
+	*s = scale
+	return nil
+}
+
+// MARK: Public functions
+
+// ParseGeneScale returns the gene scale with the given name. Valid names are
+// "linear", "log" and "exp". An empty name is the linear scale.
+func ParseGeneScale(name string) (GeneScale, error) {
+	switch strings.ToLower(name) {
+	case "", "linear":
+		return GeneScaleLinear, nil
+	case "log":
+		return GeneScaleLog, nil
+	case "exp":
+		return GeneScaleExp, nil
+	default:
+		return GeneScaleLinear, fmt.Errorf("unknown gene scale %q", name)
+	}
+}
+
+// MARK: Private methods
+
+// scale returns the scale that the bounds' values are distributed on, which is
+// linear if the bounds have a log scale but their minimum isn't positive.
+func (b GeneBounds) scale() GeneScale {
+	if b.Scale == GeneScaleLog && b.Min <= 0.0 {
+		return GeneScaleLinear
+	}
+	return b.Scale
+}
+
+// randomFrom returns a random value within the bounds that's uniformly
+// distributed on the bounds' scale, drawing from the given source.
+func (b GeneBounds) randomFrom(rng *rand.Rand) float64 {
+	return b.Denormalize(rng.Float64())
+}
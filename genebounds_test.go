@@ -0,0 +1,154 @@
+package genetics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormalizeRoundTrip(t *testing.T) {
+	tests := []struct {
+		bounds GeneBounds
+		values []float64
+	}{
+		{GeneBounds{Min: -2.0, Max: 6.0}, []float64{-2.0, 0.0, 1.5, 6.0}},
+		{GeneBounds{Min: 0.001, Max: 1000.0, Scale: GeneScaleLog}, []float64{0.001, 0.1, 1.0, 250.0, 1000.0}},
+		{GeneBounds{Min: -1.0, Max: 3.0, Scale: GeneScaleExp}, []float64{-1.0, 0.0, 2.5, 3.0}},
+		{GeneBounds{Min: 4.0, Max: 4.0}, []float64{4.0}},
+	}
+
+	for _, test := range tests {
+		for _, value := range test.values {
+			position := test.bounds.Normalize(value)
+			if position < 0.0 || position > 1.0 {
+				t.Errorf("%+v: %f is normalized to %f, outside of [0, 1]", test.bounds, value, position)
+			}
+			if result := test.bounds.Denormalize(position); math.Abs(result-value) > 1e-9*math.Max(1.0, math.Abs(value)) {
+				t.Errorf("%+v: %f is denormalized to %f", test.bounds, value, result)
+			}
+		}
+	}
+}
+
+func TestNormalizeClamps(t *testing.T) {
+	tests := []struct {
+		bounds   GeneBounds
+		value    float64
+		expected float64
+	}{
+		{GeneBounds{Min: 0.0, Max: 10.0}, -5.0, 0.0},
+		{GeneBounds{Min: 0.0, Max: 10.0}, 15.0, 1.0},
+		{GeneBounds{Min: 1.0, Max: 100.0, Scale: GeneScaleLog}, 0.0, 0.0},
+		{GeneBounds{Min: 1.0, Max: 100.0, Scale: GeneScaleLog}, 10.0, 0.5},
+		{GeneBounds{Min: 1.0, Max: 1.0}, 1.0, 0.0},
+	}
+
+	for _, test := range tests {
+		if position := test.bounds.Normalize(test.value); math.Abs(position-test.expected) > 1e-12 {
+			t.Errorf("%+v: %f is normalized to %f, expected %f", test.bounds, test.value, position, test.expected)
+		}
+	}
+
+	bounds := GeneBounds{Min: 0.0, Max: 10.0}
+	if value := bounds.Denormalize(-1.0); value != 0.0 {
+		t.Errorf("position -1 is denormalized to %f, expected 0", value)
+	}
+	if value := bounds.Denormalize(2.0); value != 10.0 {
+		t.Errorf("position 2 is denormalized to %f, expected 10", value)
+	}
+}
+
+func TestLogScaleWithNonPositiveMinimum(t *testing.T) {
+	// Log-scale bounds whose minimum isn't positive are treated as linear
+	// rather than producing NaN.
+	for _, min := range []float64{0.0, -4.0} {
+		bounds := GeneBounds{Min: min, Max: 4.0, Scale: GeneScaleLog}
+		linear := GeneBounds{Min: min, Max: 4.0}
+
+		for _, value := range []float64{min, min / 2.0, 1.0, 4.0} {
+			position := bounds.Normalize(value)
+			if position != linear.Normalize(value) {
+				t.Errorf("min %f: %f is normalized to %f, expected %f", min, value, position, linear.Normalize(value))
+			}
+			if result := bounds.Denormalize(position); math.Abs(result-value) > 1e-12 {
+				t.Errorf("min %f: %f is denormalized to %f", min, value, result)
+			}
+		}
+	}
+}
+
+func TestExpScaleWithLargeRange(t *testing.T) {
+	// Exponentials relative to the maximum underflow to zero far below it, but
+	// must never overflow or produce NaN.
+	for _, bounds := range []GeneBounds{
+		{Min: -1000.0, Max: 1000.0, Scale: GeneScaleExp},
+		{Min: 0.0, Max: 1e6, Scale: GeneScaleExp},
+		{Min: -1e308, Max: 1e308, Scale: GeneScaleExp},
+	} {
+		if position := bounds.Normalize(bounds.Min); position != 0.0 {
+			t.Errorf("%+v: the minimum is normalized to %f, expected 0", bounds, position)
+		}
+		if position := bounds.Normalize(bounds.Max); position != 1.0 {
+			t.Errorf("%+v: the maximum is normalized to %f, expected 1", bounds, position)
+		}
+		if value := bounds.Denormalize(0.0); value != bounds.Min {
+			t.Errorf("%+v: position 0 is denormalized to %f, expected the minimum", bounds, value)
+		}
+		if value := bounds.Denormalize(1.0); value != bounds.Max {
+			t.Errorf("%+v: position 1 is denormalized to %f, expected the maximum", bounds, value)
+		}
+
+		previous := math.Inf(-1)
+		for _, position := range []float64{0.0, 1e-300, 0.25, 0.5, 0.75, 1.0} {
+			value := bounds.Denormalize(position)
+			if !bounds.Contains(value) {
+				t.Errorf("%+v: position %g is denormalized to %f, outside of the bounds", bounds, position, value)
+			}
+			if value < previous {
+				t.Errorf("%+v: position %g is denormalized to %f, less than a lower position", bounds, position, value)
+			}
+			previous = value
+		}
+
+		for _, value := range []float64{bounds.Max - 500.0, bounds.Max - 1.0} {
+			if result := bounds.Denormalize(bounds.Normalize(value)); math.Abs(result-value) > 1e-9*math.Max(1.0, math.Abs(value)) {
+				t.Errorf("%+v: %f is denormalized to %f", bounds, value, result)
+			}
+		}
+	}
+}
+
+func TestQuantize(t *testing.T) {
+	tests := []struct {
+		bounds   GeneBounds
+		value    float64
+		expected float64
+	}{
+		{GeneBounds{Min: 0.0, Max: 10.0}, 3.3, 3.3},
+		{GeneBounds{Min: 0.0, Max: 10.0}, 12.0, 10.0},
+		{GeneBounds{Min: 0.0, Max: 10.0, Step: 0.5}, 3.3, 3.5},
+		{GeneBounds{Min: 0.0, Max: 10.0, Step: 1.0}, -4.0, 0.0},
+		{GeneBounds{Min: 0.2, Max: 9.7, Step: 1.0}, 0.3, 1.0},
+		{GeneBounds{Min: 0.2, Max: 9.7, Step: 1.0}, 9.6, 9.0},
+
+		// No multiple of the step lies within the bounds.
+		{GeneBounds{Min: 0.2, Max: 0.4, Step: 1.0}, 0.3, 0.3},
+		{GeneBounds{Min: 0.2, Max: 0.4, Step: 1.0}, 0.9, 0.4},
+		{GeneBounds{Min: 0.2, Max: 0.4, Step: 1.0}, -1.0, 0.2},
+	}
+
+	for _, test := range tests {
+		if value := test.bounds.Quantize(test.value); math.Abs(value-test.expected) > 1e-12 {
+			t.Errorf("%+v: %f is quantized to %f, expected %f", test.bounds, test.value, value, test.expected)
+		}
+	}
+}
+
+func TestDenormalizeWithStepLargerThanRange(t *testing.T) {
+	bounds := GeneBounds{Min: 0.2, Max: 0.4, Step: 1.0}
+	for _, position := range []float64{0.0, 0.5, 1.0} {
+		expected := 0.2 + position*0.2
+		if value := bounds.Denormalize(position); math.Abs(value-expected) > 1e-12 {
+			t.Errorf("position %f is denormalized to %f, expected %f", position, value, expected)
+		}
+	}
+}
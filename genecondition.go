@@ -0,0 +1,147 @@
+package genetics
+
+import "math"
+
+// GeneCondition objects make a gene conditional on the value of another gene,
+// typically a categorical gene whose bounds have a step of one, such as a gene
+// that chooses an algorithm whose hyperparameters are the conditional genes.
+//
+// A conditional gene is only active when its parent gene is active and takes
+// one of the condition's values. Inactive genes aren't mutated, are excluded
+// from the population's diversity, and are passed to the fitness function as
+// NaN so that they can't influence fitness. A gene with several conditions is
+// only active when every condition is met.
+type GeneCondition struct {
+	// The index of the conditional gene.
+	Gene int `json:"gene" yaml:"gene"`
+
+	// The index of the gene that the conditional gene depends on.
+	Parent int `json:"parent" yaml:"parent"`
+
+	// The values of the parent gene for which the conditional gene is active.
+	// If the configuration normalizes genes, then these are decoded values.
+	Values []float64 `json:"values" yaml:"values"`
+}
+
+// MARK: Constructors
+
+// NewGeneCondition creates and returns a new condition that makes the gene
+// active only when its parent gene takes one of the values.
+func NewGeneCondition(gene int, parent int, values ...float64) GeneCondition {
+	return GeneCondition{
+		Gene:   gene,
+		Parent: parent,
+		Values: values,
+	}
+}
+
+// MARK: Public methods
+
+// Matches returns whether or not the value of the parent gene is one of the
+// condition's values.
+func (c GeneCondition) Matches(value float64) bool {
+	for _, v := range c.Values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// MARK: Private functions
+
+// activeGenes returns whether or not each of the decoded genes is active given
+// the conditions, or nil if there are no conditions. Genes in a cycle of
+// conditions are inactive.
+func activeGenes(conditions []GeneCondition, genes []float64) []bool {
+	if len(conditions) == 0 {
+		return nil
+	}
+
+	const (
+		unresolved = iota
+		resolving
+		resolved
+	)
+
+	active := make([]bool, len(genes))
+	states := make([]int, len(genes))
+	var resolve func(i int) bool
+	resolve = func(i int) bool {
+		switch states[i] {
+		case resolving:
+			return false
+		case resolved:
+			return active[i]
+		}
+
+		states[i] = resolving
+		result := true
+		for _, c := range conditions {
+			if c.Gene != i {
+				continue
+			}
+
+			if c.Parent < 0 || c.Parent >= len(genes) || !resolve(c.Parent) || !c.Matches(genes[c.Parent]) {
+				result = false
+				break
+			}
+		}
+
+		active[i] = result
+		states[i] = resolved
+		return result
+	}
+
+	for i := range genes {
+		resolve(i)
+	}
+	return active
+}
+
+// conditionalDiversity returns the diversity of the population measured as the
+// mean standard deviation of the chromosomes' genes at each locus, where only
+// active genes are included. Loci at which no chromosome's gene is active are
+// excluded.
+func conditionalDiversity(population Population, active func(genes []float64) []bool) float64 {
+	if len(population) == 0 || len(population[0].Genes) == 0 {
+		return 0.0
+	}
+
+	length := len(population[0].Genes)
+	actives := make([][]bool, len(population))
+	for j, c := range population {
+		actives[j] = active(c.Genes)
+	}
+
+	sum := 0.0
+	loci := 0
+	for i := 0; i < length; i++ {
+		n := 0
+		mean := 0.0
+		for j, c := range population {
+			if actives[j] == nil || actives[j][i] {
+				mean += c.Genes[i]
+				n++
+			}
+		}
+		if n == 0 {
+			continue
+		}
+		mean /= float64(n)
+
+		variance := 0.0
+		for j, c := range population {
+			if actives[j] == nil || actives[j][i] {
+				variance += (c.Genes[i] - mean) * (c.Genes[i] - mean)
+			}
+		}
+		sum += math.Sqrt(variance / float64(n))
+		loci++
+	}
+
+	if loci == 0 {
+		return 0.0
+	}
+	return sum / float64(loci)
+}
@@ -0,0 +1,131 @@
+package genetics
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestActiveGenes(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []GeneCondition
+		genes      []float64
+		expected   []bool
+	}{
+		{
+			"no conditions",
+			nil,
+			[]float64{0.0, 1.0},
+			nil,
+		},
+		{
+			"matching parent",
+			[]GeneCondition{NewGeneCondition(1, 0, 1.0, 2.0)},
+			[]float64{2.0, 5.0},
+			[]bool{true, true},
+		},
+		{
+			"unmatched parent",
+			[]GeneCondition{NewGeneCondition(1, 0, 1.0)},
+			[]float64{2.0, 5.0},
+			[]bool{true, false},
+		},
+		{
+			"inactive grandparent",
+			[]GeneCondition{NewGeneCondition(1, 0, 1.0), NewGeneCondition(2, 1, 5.0)},
+			[]float64{2.0, 5.0, 3.0},
+			[]bool{true, false, false},
+		},
+		{
+			"every condition must be met",
+			[]GeneCondition{NewGeneCondition(2, 0, 1.0), NewGeneCondition(2, 1, 1.0)},
+			[]float64{1.0, 0.0, 3.0},
+			[]bool{true, true, false},
+		},
+		{
+			"cycle",
+			[]GeneCondition{NewGeneCondition(0, 1, 1.0), NewGeneCondition(1, 0, 1.0), NewGeneCondition(2, 0, 1.0)},
+			[]float64{1.0, 1.0, 1.0, 1.0},
+			[]bool{false, false, false, true},
+		},
+		{
+			"self cycle",
+			[]GeneCondition{NewGeneCondition(0, 0, 1.0)},
+			[]float64{1.0, 1.0},
+			[]bool{false, true},
+		},
+		{
+			"parent index out of range",
+			[]GeneCondition{NewGeneCondition(0, 2, 1.0), NewGeneCondition(1, -1, 1.0)},
+			[]float64{1.0, 1.0},
+			[]bool{false, false},
+		},
+		{
+			"gene index out of range",
+			[]GeneCondition{NewGeneCondition(5, 0, 1.0)},
+			[]float64{1.0, 1.0},
+			[]bool{true, true},
+		},
+	}
+
+	for _, test := range tests {
+		if active := activeGenes(test.conditions, test.genes); !reflect.DeepEqual(active, test.expected) {
+			t.Errorf("%s: active genes are %v, expected %v", test.name, active, test.expected)
+		}
+	}
+}
+
+func TestConditionalDiversity(t *testing.T) {
+	// The second gene is only active when the first is one.
+	conditions := []GeneCondition{NewGeneCondition(1, 0, 1.0)}
+	active := func(genes []float64) []bool {
+		return activeGenes(conditions, genes)
+	}
+
+	tests := []struct {
+		name       string
+		population Population
+		expected   float64
+	}{
+		{
+			"empty population",
+			Population{},
+			0.0,
+		},
+		{
+			"inactive genes are excluded",
+			Population{
+				&Chromosome{Genes: []float64{1.0, 2.0}},
+				&Chromosome{Genes: []float64{1.0, 4.0}},
+				&Chromosome{Genes: []float64{0.0, 100.0}},
+			},
+			// The first locus has a standard deviation of sqrt(2)/3 and the
+			// second of 1.
+			(math.Sqrt(2.0)/3.0 + 1.0) / 2.0,
+		},
+		{
+			"loci without active genes are excluded",
+			Population{
+				&Chromosome{Genes: []float64{0.0, 2.0}},
+				&Chromosome{Genes: []float64{2.0, 4.0}},
+			},
+			1.0,
+		},
+	}
+
+	for _, test := range tests {
+		if diversity := conditionalDiversity(test.population, active); math.Abs(diversity-test.expected) > 1e-12 {
+			t.Errorf("%s: diversity is %f, expected %f", test.name, diversity, test.expected)
+		}
+	}
+
+	population := Population{
+		&Chromosome{Genes: []float64{0.0, 1.0}},
+		&Chromosome{Genes: []float64{2.0, 3.0}},
+	}
+	unconditional := func(genes []float64) []bool { return nil }
+	if diversity := conditionalDiversity(population, unconditional); diversity != population.Diversity() {
+		t.Errorf("diversity without conditions is %f, expected %f", diversity, population.Diversity())
+	}
+}
@@ -0,0 +1,392 @@
+package genetics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// GeneSchema objects name the genes of chromosomes so that fitness functions
+// can access genes by name rather than by position. Set an evolver
+// configuration's `Schema` to attach the schema to the chromosomes that the
+// evolver evaluates.
+//
+// Schemas may also be derived from structs whose fields are tagged with the
+// bounds of their genes, see `NewGeneSchemaFromStruct`.
+type GeneSchema struct {
+	names   []string
+	indexes map[string]int
+	bounds  []GeneBounds
+}
+
+// MARK: Constructors
+
+// NewGeneSchema creates and returns a new schema that names the genes of
+// chromosomes in order. Returns an error if a name is empty or repeated.
+func NewGeneSchema(names ...string) (*GeneSchema, error) {
+	s := &GeneSchema{
+		names:   make([]string, len(names)),
+		indexes: make(map[string]int, len(names)),
+	}
+
+	for i, name := range names {
+		if name == "" {
+			return nil, fmt.Errorf("the name of gene %d must not be empty", i)
+		}
+		if _, ok := s.indexes[name]; ok {
+			return nil, fmt.Errorf("the name %q of gene %d isn't unique", name, i)
+		}
+
+		s.names[i] = name
+		s.indexes[name] = i
+	}
+	return s, nil
+}
+
+// NewGeneSchemaFromStruct creates and returns a new schema derived from the
+// fields of a struct, or a pointer to a struct, that have a `gene` tag. Each
+// tagged field is a gene, in the order that the fields are declared, and its
+// tag gives the gene's name and bounds as comma separated options:
+//
+//	type Parameters struct {
+//		Period    int     `gene:"period,min=2,max=50"`
+//		Threshold float64 `gene:"min=0,max=100"`
+//		Rate      float64 `gene:"min=0.0001,max=1,scale=log"`
+//		Long      bool    `gene:""`
+//	}
+//
+// A gene is named by the first option that isn't a key-value pair, or by its
+// field's name. The options are:
+//
+//	min, max: the gene's bounds, required for all but bool genes
+//	type:     "float", "int" or "bool", inferred from the field's kind
+//	step:     the gene's step, see `GeneBounds.Step`
+//	scale:    the gene's scale, see `ParseGeneScale`
+//
+// Int genes have a step of one, and bool genes are bounded by [0, 1] with a
+// step of one. Fields tagged `gene:"-"` are ignored.
+func NewGeneSchemaFromStruct(v interface{}) (*GeneSchema, error) {
+	t := reflect.TypeOf(v)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, errors.New("genetics: a schema can only be derived from a struct")
+	}
+
+	var names []string
+	var bounds []GeneBounds
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("gene")
+		if !ok || tag == "-" || field.PkgPath != "" {
+			continue
+		}
+
+		name, b, err := parseGeneTag(field, tag)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		bounds = append(bounds, b)
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("genetics: the struct %s doesn't have any fields with a gene tag", t)
+	}
+
+	s, err := NewGeneSchema(names...)
+	if err != nil {
+		return nil, err
+	}
+	s.bounds = bounds
+	return s, nil
+}
+
+// MARK: Public methods
+
+// Names returns the names of the genes in order.
+func (s GeneSchema) Names() []string {
+	names := make([]string, len(s.names))
+	copy(names, s.names)
+	return names
+}
+
+// Len returns the number of named genes.
+func (s GeneSchema) Len() int {
+	return len(s.names)
+}
+
+// Bounds returns the bounds of each gene, or nil if the schema wasn't derived
+// from a struct.
+func (s GeneSchema) Bounds() []GeneBounds {
+	if s.bounds == nil {
+		return nil
+	}
+
+	bounds := make([]GeneBounds, len(s.bounds))
+	copy(bounds, s.bounds)
+	return bounds
+}
+
+// Population generates a population of chromosomes with the schema whose genes
+// are uniformly distributed within their bounds. Genes of schemas without
+// bounds are in [-1, 1].
+func (s *GeneSchema) Population(populationSize uint) Population {
+	population := GeneratePopulation(populationSize, uint(len(s.names)), func(i, j int) float64 {
+		if s.bounds == nil {
+			return GeneBounds{Min: -1.0, Max: 1.0}.Random()
+		}
+		return s.bounds[j].Random()
+	})
+
+	for _, c := range population {
+		c.schema = s
+	}
+	return population
+}
+
+// Configure returns a copy of the configuration with the schema and bounded by
+// the schema's bounds, if it has any.
+func (s *GeneSchema) Configure(configuration *EvolverConfiguration) *EvolverConfiguration {
+	configured := *configuration
+	configured.Schema = s
+	if s.bounds != nil {
+		configured.Bounds = s.Bounds()
+	}
+	return &configured
+}
+
+// Encode returns a new chromosome with the schema whose genes are the values of
+// the fields of the struct, or pointer to a struct, with the same names. It's
+// the inverse of `DecodeInto`. Float and integer fields are encoded as their
+// values and bool fields as zero or one. Genes without a field of the same name
+// are zero.
+func (s *GeneSchema) Encode(v interface{}) (*Chromosome, error) {
+	value := reflect.ValueOf(v)
+	if value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, errors.New("genetics: encoding requires a struct")
+	}
+
+	chromosome := newChromosome(len(s.names), false, nil)
+	chromosome.schema = s
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name, _ := geneTagName(field)
+		j, ok := s.indexes[name]
+		if name == "-" || !ok {
+			continue
+		}
+
+		gene, err := geneFieldValue(value.Field(i))
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode field %s as gene %q: %w", field.Name, name, err)
+		}
+		chromosome.Genes[j] = gene
+	}
+	return chromosome, nil
+}
+
+// Index returns the index of the named gene, and whether or not the schema has
+// the gene.
+func (s GeneSchema) Index(name string) (int, bool) {
+	i, ok := s.indexes[name]
+	return i, ok
+}
+
+// Lookup returns the value of the chromosome's named gene, and whether or not
+// the schema has the gene and the chromosome has a gene at its index.
+func (s GeneSchema) Lookup(chromosome *Chromosome, name string) (float64, bool) {
+	i, ok := s.indexes[name]
+	if !ok || i >= len(chromosome.Genes) {
+		return 0.0, false
+	}
+	return chromosome.Genes[i], true
+}
+
+// DecodeInto sets the fields of the struct pointed to by `v` to the values of
+// the chromosome's genes with the same names. A field's gene is named by its
+// `gene` tag, or is the field's name if it doesn't have one, and fields tagged
+// `gene:"-"` are ignored. Fields without a gene of the same name are left
+// unchanged.
+//
+// Float fields are set to the gene's value, integer fields to the gene's value
+// rounded to the nearest integer, and bool fields to whether or not the gene is
+// at least 0.5. Returns an error if a field of another kind has a gene, or if
+// a tag names a gene that the schema doesn't have.
+func (s GeneSchema) DecodeInto(chromosome *Chromosome, v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return errors.New("genetics: decoding requires a non-nil pointer to a struct")
+	}
+
+	value = value.Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name, tagged := geneTagName(field)
+		if name == "-" {
+			continue
+		}
+
+		gene, ok := s.Lookup(chromosome, name)
+		if !ok {
+			if _, named := s.indexes[name]; tagged && !named {
+				return fmt.Errorf("the gene %q of field %s isn't in the schema", name, field.Name)
+			}
+			continue
+		}
+
+		if err := setGeneField(value.Field(i), gene); err != nil {
+			return fmt.Errorf("unable to decode gene %q in to field %s: %w", name, field.Name, err)
+		}
+	}
+	return nil
+}
+
+// MARK: Private functions
+
+// geneTagName returns the name of the gene of a struct field given by its
+// `gene` tag, or its name, and whether or not the field's tag names the gene.
+func geneTagName(field reflect.StructField) (string, bool) {
+	tag, ok := field.Tag.Lookup("gene")
+	if !ok {
+		return field.Name, false
+	}
+	if tag == "-" {
+		return tag, true
+	}
+
+	for _, option := range strings.Split(tag, ",") {
+		if option = strings.TrimSpace(option); option != "" && !strings.Contains(option, "=") {
+			return option, true
+		}
+	}
+	return field.Name, false
+}
+
+// parseGeneTag returns the name and bounds of the gene of a struct field given
+// by its `gene` tag.
+func parseGeneTag(field reflect.StructField, tag string) (string, GeneBounds, error) {
+	name, _ := geneTagName(field)
+	bounds := GeneBounds{}
+
+	geneType := "float"
+	switch field.Type.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		geneType = "int"
+	case reflect.Bool:
+		geneType = "bool"
+	case reflect.Float32, reflect.Float64:
+	default:
+		return "", bounds, fmt.Errorf("genetics: unsupported kind %s of field %s", field.Type.Kind(), field.Name)
+	}
+
+	hasMin, hasMax := false, false
+	for _, option := range strings.Split(tag, ",") {
+		i := strings.Index(option, "=")
+		if i < 0 {
+			continue
+		}
+
+		key, value := strings.TrimSpace(option[:i]), strings.TrimSpace(option[i+1:])
+		var err error
+		switch key {
+		case "min":
+			bounds.Min, err = strconv.ParseFloat(value, 64)
+			hasMin = true
+		case "max":
+			bounds.Max, err = strconv.ParseFloat(value, 64)
+			hasMax = true
+		case "step":
+			bounds.Step, err = strconv.ParseFloat(value, 64)
+		case "scale":
+			bounds.Scale, err = ParseGeneScale(value)
+		case "type":
+			geneType = value
+		default:
+			err = fmt.Errorf("unknown option %q", key)
+		}
+		if err != nil {
+			return "", bounds, fmt.Errorf("genetics: invalid gene tag of field %s: %w", field.Name, err)
+		}
+	}
+
+	switch geneType {
+	case "float":
+	case "int":
+		bounds.Step = 1.0
+	case "bool":
+		if !hasMin && !hasMax {
+			bounds.Min, bounds.Max = 0.0, 1.0
+			hasMin, hasMax = true, true
+		}
+		bounds.Step = 1.0
+	default:
+		return "", bounds, fmt.Errorf("genetics: unknown gene type %q of field %s", geneType, field.Name)
+	}
+
+	if !hasMin || !hasMax {
+		return "", bounds, fmt.Errorf("genetics: the gene tag of field %s must have a min and max", field.Name)
+	}
+	return name, bounds, nil
+}
+
+// setGeneField sets the value of a struct field to the value of a gene. Integer
+// and bool fields are set to zero values if the gene is NaN, such as an
+// inactive conditional gene.
+func setGeneField(field reflect.Value, gene float64) error {
+	switch field.Kind() {
+	case reflect.Float32, reflect.Float64:
+		field.SetFloat(gene)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if math.IsNaN(gene) {
+			gene = 0.0
+		}
+		field.SetInt(int64(math.Round(gene)))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if math.IsNaN(gene) {
+			gene = 0.0
+		}
+		field.SetUint(uint64(math.Max(0.0, math.Round(gene))))
+	case reflect.Bool:
+		field.SetBool(gene >= 0.5)
+	default:
+		return fmt.Errorf("unsupported kind %s", field.Kind())
+	}
+	return nil
+}
+
+// geneFieldValue returns the value of a gene encoding the value of a struct
+// field.
+func geneFieldValue(field reflect.Value) (float64, error) {
+	switch field.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return field.Float(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(field.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(field.Uint()), nil
+	case reflect.Bool:
+		if field.Bool() {
+			return 1.0, nil
+		}
+		return 0.0, nil
+	default:
+		return 0.0, fmt.Errorf("unsupported kind %s", field.Kind())
+	}
+}
@@ -0,0 +1,96 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	genetics "github.com/colinc86/go-genetics"
+)
+
+// EventEncoder types write optimizer events to a writer in the server-sent
+// events format.
+//
+// Each generation is written as a `generation` event whose data is the JSON
+// encoding of the generation's statistics. Generations that improve on the best
+// fitness are followed by a `best` event whose data is the JSON encoding of
+// the generation's best chromosome.
+type EventEncoder struct {
+	writer io.Writer
+}
+
+// MARK: Constructors
+
+// NewEventEncoder creates and returns a new event encoder that writes to the
+// given writer.
+func NewEventEncoder(w io.Writer) *EventEncoder {
+	return &EventEncoder{writer: w}
+}
+
+// MARK: Public methods
+
+// Encode writes the event. If the writer is an `http.Flusher`, then it's
+// flushed so that clients receive the event immediately.
+func (x *EventEncoder) Encode(event genetics.OptimizerEvent) error {
+	if err := x.write("generation", newGenerationStats(event.Stats)); err != nil {
+		return err
+	}
+
+	if event.Improved && event.Best != nil {
+		if err := x.write("best", newChromosome(event.Best)); err != nil {
+			return err
+		}
+	}
+
+	if flusher, ok := x.writer.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+// MARK: Private methods
+
+// write writes an event with the given name and the JSON encoding of the value
+// as its data.
+func (x *EventEncoder) write(name string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(x.writer, "event: %s\ndata: %s\n\n", name, data)
+	return err
+}
+
+// events streams the optimizer's events until the client disconnects.
+func (h *Handler) events(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	if _, ok := w.(http.Flusher); !ok {
+		http.Error(w, "streaming isn't supported", http.StatusInternalServerError)
+		return
+	}
+
+	events, cancel := h.optimizer.Subscribe()
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	w.(http.Flusher).Flush()
+
+	encoder := NewEventEncoder(w)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-events:
+			if err := encoder.Encode(event); err != nil {
+				return
+			}
+		}
+	}
+}
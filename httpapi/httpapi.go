@@ -0,0 +1,336 @@
+// Package httpapi provides an HTTP control plane for long-running
+// optimizations.
+//
+// A `Handler` serves the following endpoints relative to the path it's mounted
+// at:
+//
+//	GET  /status         the optimizer's generation, evaluations and state
+//	GET  /best           the best chromosome of the latest generation
+//	GET  /stats          the statistics of each evolved generation
+//	POST /pause          pauses the optimizer before its next generation
+//	POST /resume         resumes a paused optimizer
+//	GET  /mutation-rate  the current mutation rate
+//	PUT  /mutation-rate  sets the mutation rate, e.g. {"mutation_rate": 0.05}
+//	GET  /checkpoint     a checkpoint of the latest generation
+//	GET  /events         a stream of server-sent generation events
+//
+// Responses are JSON, with fitnesses and other values that aren't finite, such
+// as the NaN fitness of an invalid chromosome, encoded as null. Checkpoints are
+// encoded as protobuf messages instead when requested with `?format=proto`.
+// Mount a handler with `http.StripPrefix` to serve it below a path prefix.
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+
+	genetics "github.com/colinc86/go-genetics"
+)
+
+// Handler types serve the HTTP control API of an optimizer.
+type Handler struct {
+	optimizer *genetics.Optimizer
+	mux       *http.ServeMux
+}
+
+// status is the response of the status endpoint.
+type status struct {
+	Generation   int     `json:"generation"`
+	Evaluations  int     `json:"evaluations"`
+	Optimizing   bool    `json:"optimizing"`
+	Paused       bool    `json:"paused"`
+	MutationRate float64 `json:"mutation_rate"`
+}
+
+// number is a float that's encoded as null if it isn't finite, because JSON
+// can't represent NaN or infinity.
+type number float64
+
+// chromosome is the serialized representation of a chromosome. Its fields
+// shadow the chromosome's float fields so that they're encoded as numbers.
+type chromosome struct {
+	*genetics.Chromosome
+	Genes      []number
+	Fitness    number
+	Components map[string]number `json:",omitempty"`
+	Objectives []number          `json:",omitempty"`
+	Violation  number            `json:",omitempty"`
+	CaseErrors []number          `json:",omitempty"`
+	Behavior   []number          `json:",omitempty"`
+	Novelty    number            `json:",omitempty"`
+}
+
+// checkpoint is the serialized representation of a checkpoint.
+type checkpoint struct {
+	*genetics.Checkpoint
+	Population []chromosome `json:"population"`
+}
+
+// generationStats is the serialized representation of a generation's
+// statistics.
+type generationStats struct {
+	Generation  int     `json:"generation"`
+	Best        number  `json:"best"`
+	Mean        number  `json:"mean"`
+	Worst       number  `json:"worst"`
+	Diversity   number  `json:"diversity"`
+	Elapsed     float64 `json:"elapsed"`
+	Invalid     int     `json:"invalid_fitnesses"`
+	Evaluations int     `json:"evaluations"`
+}
+
+// mutationRate is the request and response body of the mutation rate
+// endpoint.
+type mutationRate struct {
+	MutationRate *float64 `json:"mutation_rate"`
+}
+
+// MARK: Constructors
+
+// NewHandler creates and returns a new handler that controls the optimizer.
+func NewHandler(optimizer *genetics.Optimizer) *Handler {
+	h := &Handler{
+		optimizer: optimizer,
+		mux:       http.NewServeMux(),
+	}
+
+	h.mux.HandleFunc("/status", h.status)
+	h.mux.HandleFunc("/best", h.best)
+	h.mux.HandleFunc("/stats", h.stats)
+	h.mux.HandleFunc("/pause", h.pause)
+	h.mux.HandleFunc("/resume", h.resume)
+	h.mux.HandleFunc("/mutation-rate", h.mutationRate)
+	h.mux.HandleFunc("/checkpoint", h.checkpoint)
+	h.mux.HandleFunc("/events", h.events)
+	return h
+}
+
+// MARK: Public methods
+
+// ServeHTTP serves a request to one of the handler's endpoints.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// MarshalJSON encodes the number, or null if it isn't finite.
+func (n number) MarshalJSON() ([]byte, error) {
+	if math.IsNaN(float64(n)) || math.IsInf(float64(n), 0) {
+		return []byte("null"), nil
+	}
+	return json.Marshal(float64(n))
+}
+
+// MARK: Private methods
+
+// status responds with the optimizer's progress.
+func (h *Handler) status(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	result := h.optimizer.Result()
+	s := status{
+		Generation:   h.optimizer.Generations(),
+		Optimizing:   h.optimizer.IsOptimizing(),
+		Paused:       h.optimizer.IsPaused(),
+		MutationRate: h.optimizer.MutationRate(),
+	}
+	if result != nil {
+		s.Evaluations = result.Evaluations
+	}
+	writeJSON(w, s)
+}
+
+// best responds with the optimizer's best chromosome.
+func (h *Handler) best(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	best := h.optimizer.CurrentBest()
+	if best == nil {
+		http.Error(w, "the population hasn't been evaluated", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, newChromosome(best))
+}
+
+// stats responds with the statistics of each evolved generation.
+func (h *Handler) stats(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	stats := []generationStats{}
+	if result := h.optimizer.Result(); result != nil {
+		for _, s := range result.History {
+			stats = append(stats, newGenerationStats(s))
+		}
+	}
+	writeJSON(w, stats)
+}
+
+// pause pauses the optimizer.
+func (h *Handler) pause(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	h.optimizer.Pause()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// resume resumes the optimizer.
+func (h *Handler) resume(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	h.optimizer.Resume()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// mutationRate responds with, or sets, the optimizer's mutation rate.
+func (h *Handler) mutationRate(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodPut, http.MethodPost) {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		body := mutationRate{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request body: %s", err), http.StatusBadRequest)
+			return
+		}
+
+		if body.MutationRate == nil || *body.MutationRate < 0.0 || *body.MutationRate > 1.0 {
+			http.Error(w, "mutation_rate must be in the range [0, 1]", http.StatusBadRequest)
+			return
+		}
+		h.optimizer.SetMutationRate(*body.MutationRate)
+	}
+
+	rate := h.optimizer.MutationRate()
+	writeJSON(w, mutationRate{MutationRate: &rate})
+}
+
+// checkpoint responds with a checkpoint of the optimizer's latest generation.
+func (h *Handler) checkpoint(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	checkpoint := h.optimizer.Checkpoint()
+	if checkpoint == nil {
+		http.Error(w, "the population hasn't been evaluated", http.StatusNotFound)
+		return
+	}
+
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "json":
+		w.Header().Set("Content-Disposition", `attachment; filename="checkpoint.json"`)
+		writeJSON(w, newCheckpoint(checkpoint))
+	case "proto":
+		data, err := checkpoint.MarshalProto()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.Header().Set("Content-Disposition", `attachment; filename="checkpoint.pb"`)
+		w.Write(data)
+	default:
+		http.Error(w, fmt.Sprintf("unknown checkpoint format %q", format), http.StatusBadRequest)
+	}
+}
+
+// MARK: Private functions
+
+// newChromosome returns the serialized representation of the chromosome.
+func newChromosome(c *genetics.Chromosome) chromosome {
+	components := map[string]number(nil)
+	if c.Components != nil {
+		components = make(map[string]number, len(c.Components))
+		for name, value := range c.Components {
+			components[name] = number(value)
+		}
+	}
+
+	return chromosome{
+		Chromosome: c,
+		Genes:      newNumbers(c.Genes),
+		Fitness:    number(c.Fitness),
+		Components: components,
+		Objectives: newNumbers(c.Objectives),
+		Violation:  number(c.Violation),
+		CaseErrors: newNumbers(c.CaseErrors),
+		Behavior:   newNumbers(c.Behavior),
+		Novelty:    number(c.Novelty),
+	}
+}
+
+// newCheckpoint returns the serialized representation of the checkpoint.
+func newCheckpoint(c *genetics.Checkpoint) checkpoint {
+	population := make([]chromosome, len(c.Population))
+	for i, chromosome := range c.Population {
+		population[i] = newChromosome(chromosome)
+	}
+	return checkpoint{Checkpoint: c, Population: population}
+}
+
+// newNumbers returns the values as numbers, or nil if the values are nil.
+func newNumbers(values []float64) []number {
+	if values == nil {
+		return nil
+	}
+
+	numbers := make([]number, len(values))
+	for i, value := range values {
+		numbers[i] = number(value)
+	}
+	return numbers
+}
+
+// newGenerationStats returns the serialized representation of the statistics.
+func newGenerationStats(s genetics.GenerationStats) generationStats {
+	return generationStats{
+		Generation:  s.Generation,
+		Best:        number(s.Best),
+		Mean:        number(s.Mean),
+		Worst:       number(s.Worst),
+		Diversity:   number(s.Diversity),
+		Elapsed:     s.Elapsed.Seconds(),
+		Invalid:     s.InvalidFitnesses,
+		Evaluations: s.Evaluations,
+	}
+}
+
+// allowMethods responds with an error and returns false if the request's
+// method isn't one of the given methods.
+func allowMethods(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, m := range methods {
+		if r.Method == m {
+			return true
+		}
+	}
+
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	return false
+}
+
+// writeJSON responds with the JSON encoding of the value.
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
@@ -0,0 +1,98 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"math"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	genetics "github.com/colinc86/go-genetics"
+)
+
+// decode returns the JSON object written to the recorder.
+func decode(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+
+	if w.Code != 200 {
+		t.Fatalf("responded with status %d: %s", w.Code, w.Body)
+	}
+
+	object := map[string]interface{}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &object); err != nil {
+		t.Fatal(err)
+	}
+	return object
+}
+
+func TestChromosomeMatchesChromosomeJSON(t *testing.T) {
+	c := &genetics.Chromosome{
+		ID:         7,
+		Genes:      []float64{0.5, -1.0},
+		Fitness:    2.5,
+		Components: map[string]float64{"profit": 3.0},
+		Age:        4,
+		Objectives: []float64{1.0, 2.0},
+		Novelty:    0.25,
+		Metadata:   map[string]interface{}{"name": "a"},
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{}
+	if err := json.Unmarshal(data, &expected); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	writeJSON(w, newChromosome(c))
+	if object := decode(t, w); !reflect.DeepEqual(object, expected) {
+		t.Errorf("encoded %v, expected %v", object, expected)
+	}
+}
+
+func TestChromosomeEncodesNonFiniteValuesAsNull(t *testing.T) {
+	c := &genetics.Chromosome{
+		Genes:      []float64{math.Inf(1)},
+		Fitness:    math.NaN(),
+		Components: map[string]float64{"profit": math.Inf(-1)},
+	}
+
+	w := httptest.NewRecorder()
+	writeJSON(w, newCheckpoint(&genetics.Checkpoint{Population: genetics.Population{c}}))
+	object := decode(t, w)
+
+	population := object["population"].([]interface{})
+	encoded := population[0].(map[string]interface{})
+	if fitness, ok := encoded["Fitness"]; !ok || fitness != nil {
+		t.Errorf("encoded fitness %v, expected null", fitness)
+	}
+	if genes := encoded["Genes"].([]interface{}); genes[0] != nil {
+		t.Errorf("encoded gene %v, expected null", genes[0])
+	}
+	if profit := encoded["Components"].(map[string]interface{})["profit"]; profit != nil {
+		t.Errorf("encoded component %v, expected null", profit)
+	}
+}
+
+func TestGenerationStatsEncodesNonFiniteValuesAsNull(t *testing.T) {
+	w := httptest.NewRecorder()
+	writeJSON(w, newGenerationStats(genetics.GenerationStats{
+		Generation: 1,
+		Best:       math.Inf(1),
+		Mean:       math.NaN(),
+		Worst:      -1.0,
+	}))
+	object := decode(t, w)
+
+	for _, key := range []string{"best", "mean"} {
+		if value, ok := object[key]; !ok || value != nil {
+			t.Errorf("encoded %s %v, expected null", key, value)
+		}
+	}
+	if object["worst"] != -1.0 {
+		t.Errorf("encoded worst %v, expected -1", object["worst"])
+	}
+}
@@ -0,0 +1,112 @@
+package genetics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// The number of times a chromosome is re-evaluated when using the retry policy.
+const maximumFitnessRetries = 3
+
+// ErrInvalidFitness is returned when a fitness function returns NaN or ±Inf
+// and the evolver's configuration uses the error policy.
+var ErrInvalidFitness = errors.New("genetics: invalid fitness")
+
+// InvalidFitnessPolicy represents how NaN and ±Inf fitness values are handled.
+type InvalidFitnessPolicy uint
+
+// Invalid fitness policies.
+const (
+	// Invalid fitness values are counted but otherwise left as is.
+	InvalidFitnessPolicyNone InvalidFitnessPolicy = 0
+
+	// Evolution stops with an `ErrInvalidFitness` error.
+	InvalidFitnessPolicyError InvalidFitnessPolicy = 1
+
+	// ±Inf is clamped to ±`math.MaxFloat64` and NaN is replaced with
+	// -`math.MaxFloat64`.
+	InvalidFitnessPolicyClamp InvalidFitnessPolicy = 2
+
+	// Invalid fitness values are replaced with the worst valid fitness in the
+	// population.
+	InvalidFitnessPolicyWorst InvalidFitnessPolicy = 3
+
+	// Chromosomes are evaluated again up to three times, after which the worst
+	// policy is applied.
+	InvalidFitnessPolicyRetry InvalidFitnessPolicy = 4
+)
+
+// MARK: String methods
+
+func (p InvalidFitnessPolicy) String() string {
+	switch p {
+	case InvalidFitnessPolicyNone:
+		return "none"
+	case InvalidFitnessPolicyError:
+		return "error"
+	case InvalidFitnessPolicyClamp:
+		return "clamp"
+	case InvalidFitnessPolicyWorst:
+		return "worst"
+	case InvalidFitnessPolicyRetry:
+		return "retry"
+	default:
+		return "unknown"
+	}
+}
+
+// MARK: Public functions
+
+// ParseInvalidFitnessPolicy returns the invalid fitness policy with the given
+// name. Valid names are "none", "error", "clamp", "worst" and "retry". An empty
+// name is the none policy.
+func ParseInvalidFitnessPolicy(name string) (InvalidFitnessPolicy, error) {
+	switch strings.ToLower(name) {
+	case "", "none":
+		return InvalidFitnessPolicyNone, nil
+	case "error":
+		return InvalidFitnessPolicyError, nil
+	case "clamp":
+		return InvalidFitnessPolicyClamp, nil
+	case "worst":
+		return InvalidFitnessPolicyWorst, nil
+	case "retry":
+		return InvalidFitnessPolicyRetry, nil
+	default:
+		return InvalidFitnessPolicyNone, fmt.Errorf("unknown invalid fitness policy %q", name)
+	}
+}
+
+// MARK: Private functions
+
+// isInvalidFitness returns whether or not the fitness is NaN or ±Inf.
+func isInvalidFitness(fitness float64) bool {
+	return math.IsNaN(fitness) || math.IsInf(fitness, 0)
+}
+
+// clampFitness returns the fitness with ±Inf clamped to ±`math.MaxFloat64` and
+// NaN replaced with -`math.MaxFloat64`.
+func clampFitness(fitness float64) float64 {
+	if math.IsNaN(fitness) {
+		return -math.MaxFloat64
+	}
+	return math.Max(-math.MaxFloat64, math.Min(math.MaxFloat64, fitness))
+}
+
+// worstValidFitness returns the lowest fitness in the population that isn't
+// NaN or ±Inf, or -`math.MaxFloat64` if there isn't one.
+func worstValidFitness(population Population) float64 {
+	worst := math.Inf(1)
+	for _, c := range population {
+		if !isInvalidFitness(c.Fitness) {
+			worst = math.Min(worst, c.Fitness)
+		}
+	}
+
+	if math.IsInf(worst, 1) {
+		return -math.MaxFloat64
+	}
+	return worst
+}
@@ -0,0 +1,241 @@
+package genetics
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// LandscapeAnalyzer types measure properties of the fitness landscape defined
+// by a fitness function, a chromosome length and the bounds and mutation
+// method of a configuration. The measurements help to choose operators before
+// evolving a population. Unbounded genes are sampled from [-1, 1].
+type LandscapeAnalyzer struct {
+	Configuration    *EvolverConfiguration
+	FitnessFunction  FitnessFunction
+	ChromosomeLength int
+}
+
+// GeneSensitivity objects describe how sensitive fitness is to a gene.
+type GeneSensitivity struct {
+	// The index of the gene.
+	Gene int
+
+	// The mean absolute change in fitness when the gene is perturbed.
+	Importance float64
+
+	// The largest absolute change in fitness when the gene is perturbed.
+	MaxChange float64
+}
+
+// MARK: Constructors
+
+// NewLandscapeAnalyzer creates and returns a new landscape analyzer.
+func NewLandscapeAnalyzer(configuration *EvolverConfiguration, fitnessFunction FitnessFunction, chromosomeLength int) *LandscapeAnalyzer {
+	return &LandscapeAnalyzer{
+		Configuration:    configuration,
+		FitnessFunction:  fitnessFunction,
+		ChromosomeLength: chromosomeLength,
+	}
+}
+
+// MARK: Public methods
+
+// Sample returns `n` uniformly distributed and evaluated chromosomes.
+func (a LandscapeAnalyzer) Sample(n int) Population {
+	state := a.state()
+	population := make(Population, n)
+	for i := range population {
+		population[i] = a.randomChromosome()
+		a.evaluate(population[i], state)
+	}
+	return population
+}
+
+// FitnessDistanceCorrelation returns the correlation between the fitness of
+// the samples and their distance to the fittest sample. Values close to -1
+// indicate that fitness improves towards the optimum and the landscape is easy
+// to search, values close to 0 indicate a landscape that gives little guidance,
+// and positive values indicate a deceptive landscape.
+func (a LandscapeAnalyzer) FitnessDistanceCorrelation(samples Population) float64 {
+	if len(samples) < 2 {
+		return 0.0
+	}
+
+	best := samples[0]
+	for _, c := range samples {
+		if c.Fitness > best.Fitness {
+			best = c
+		}
+	}
+
+	fitnesses := make([]float64, len(samples))
+	distances := make([]float64, len(samples))
+	for i, c := range samples {
+		fitnesses[i] = c.Fitness
+		distances[i] = c.Distance(best)
+	}
+	return stat.Correlation(fitnesses, distances, nil)
+}
+
+// RandomWalk returns the fitness at each of the given number of steps of a
+// random walk. Each step applies the configuration's mutation method to a
+// single random gene.
+func (a LandscapeAnalyzer) RandomWalk(steps int) []float64 {
+	state := a.state()
+	mutationMethod := a.Configuration.mutationMethods()[0]
+
+	c := a.randomChromosome()
+	fitnesses := make([]float64, steps)
+	for i := range fitnesses {
+		fitnesses[i] = a.evaluate(c, state)
+		if len(c.Genes) > 0 {
+			j := random.Intn(len(c.Genes))
+			c.Genes[j] = mutationMethod.Function(c, j, state)
+			a.Configuration.clampGenes(c.Genes)
+		}
+	}
+	return fitnesses
+}
+
+// Autocorrelation returns the autocorrelation, at the given lag, of the
+// fitness along a random walk of the given number of steps. Values close to 1
+// indicate a smooth landscape for the configuration's mutation method, and
+// values close to 0 indicate a rugged landscape.
+func (a LandscapeAnalyzer) Autocorrelation(steps int, lag int) float64 {
+	return autocorrelation(a.RandomWalk(steps), lag)
+}
+
+// CorrelationLength returns the correlation length of the landscape measured
+// along a random walk of the given number of steps. It's the expected number
+// of mutations after which fitness becomes uncorrelated.
+func (a LandscapeAnalyzer) CorrelationLength(steps int) float64 {
+	r := math.Abs(a.Autocorrelation(steps, 1))
+	if r == 0.0 {
+		return 0.0
+	}
+	if r >= 1.0 {
+		return math.Inf(1)
+	}
+	return -1.0 / math.Log(r)
+}
+
+// LocalOptima returns the local optima reached by hill climbing from the given
+// number of random chromosomes. Each climb applies the configuration's
+// mutation method to a random gene and keeps improvements, stopping after the
+// given number of consecutive attempts fail to improve fitness. The number of
+// distinct optima found indicates how multimodal the landscape is.
+func (a LandscapeAnalyzer) LocalOptima(starts int, attempts int) Population {
+	state := a.state()
+	mutationMethod := a.Configuration.mutationMethods()[0]
+
+	optima := make(Population, starts)
+	for i := range optima {
+		c := a.randomChromosome()
+		a.evaluate(c, state)
+
+		for failures := 0; failures < attempts && len(c.Genes) > 0; {
+			neighbour := c.Clone()
+			j := random.Intn(len(neighbour.Genes))
+			neighbour.Genes[j] = mutationMethod.Function(neighbour, j, state)
+			a.Configuration.clampGenes(neighbour.Genes)
+
+			if a.evaluate(neighbour, state) > c.Fitness {
+				c = neighbour
+				failures = 0
+			} else {
+				failures++
+			}
+		}
+		optima[i] = c
+	}
+	return optima
+}
+
+// Sensitivity perturbs each gene of the chromosome in turn to `samples` evenly
+// spaced values within the gene's bounds, and returns the sensitivity of
+// fitness to each gene in descending order of importance. Genes with low
+// importance have little effect on fitness near the chromosome and are
+// candidates for removal from the parameter space.
+func (a LandscapeAnalyzer) Sensitivity(chromosome *Chromosome, samples int) []GeneSensitivity {
+	state := a.state()
+	base := a.FitnessFunction(chromosome, state)
+
+	c := chromosome.Clone()
+	sensitivities := make([]GeneSensitivity, len(c.Genes))
+	for j := range c.Genes {
+		sensitivities[j].Gene = j
+		if samples < 1 {
+			continue
+		}
+
+		bounds, ok := a.Configuration.BoundsForGene(j)
+		if !ok {
+			bounds = GeneBounds{Min: -1.0, Max: 1.0}
+		}
+
+		for k := 0; k < samples; k++ {
+			c.Genes[j] = bounds.Denormalize(0.5)
+			if samples > 1 {
+				c.Genes[j] = bounds.Denormalize(float64(k) / float64(samples-1))
+			}
+
+			change := math.Abs(a.evaluate(c, state) - base)
+			sensitivities[j].Importance += change / float64(samples)
+			sensitivities[j].MaxChange = math.Max(sensitivities[j].MaxChange, change)
+		}
+		c.Genes[j] = chromosome.Genes[j]
+	}
+
+	sort.SliceStable(sensitivities, func(i, j int) bool {
+		return sensitivities[i].Importance > sensitivities[j].Importance
+	})
+	return sensitivities
+}
+
+// MARK: Private methods
+
+// state returns the evolution state that chromosomes are evaluated with.
+func (a LandscapeAnalyzer) state() *EvolutionState {
+	return &EvolutionState{
+		Configuration: a.Configuration,
+		MutationRate:  a.Configuration.MutationRate,
+	}
+}
+
+// randomChromosome returns a chromosome with uniformly distributed genes.
+func (a LandscapeAnalyzer) randomChromosome() *Chromosome {
+	return a.Configuration.randomChromosome(a.ChromosomeLength)
+}
+
+// evaluate evaluates the chromosome and returns its fitness.
+func (a LandscapeAnalyzer) evaluate(c *Chromosome, state *EvolutionState) float64 {
+	c.Fitness = a.FitnessFunction(c, state)
+	c.evaluated = true
+	return c.Fitness
+}
+
+// MARK: Private functions
+
+// autocorrelation returns the autocorrelation of the series at the given lag.
+func autocorrelation(series []float64, lag int) float64 {
+	if lag < 0 || lag >= len(series) {
+		return 0.0
+	}
+
+	mean := stat.Mean(series, nil)
+	variance := 0.0
+	for _, x := range series {
+		variance += (x - mean) * (x - mean)
+	}
+	if variance == 0.0 {
+		return 0.0
+	}
+
+	covariance := 0.0
+	for i := 0; i+lag < len(series); i++ {
+		covariance += (series[i] - mean) * (series[i+lag] - mean)
+	}
+	return covariance / variance
+}
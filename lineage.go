@@ -0,0 +1,155 @@
+package genetics
+
+import (
+	"sort"
+	"sync"
+)
+
+// LineageRecord objects describe how a chromosome was created.
+type LineageRecord struct {
+	// The chromosome's identifier.
+	ID uint64
+
+	// The identifiers of the chromosome's parents. Chromosomes of the initial
+	// population have no parents, and chromosomes bred without crossover have a
+	// single parent.
+	Parents []uint64
+
+	// The names of the crossover and mutation operators applied to the
+	// chromosome, or empty if the operator wasn't applied.
+	Crossover string
+	Mutation  string
+
+	// The generation that the chromosome was created in.
+	Generation int
+
+	// The chromosome's fitness once evaluated.
+	Fitness float64
+}
+
+// Lineage objects record the genealogy of every chromosome created by an
+// evolver so that it can be queried after evolution. Records are kept for the
+// lifetime of the lineage, so memory grows with the number of chromosomes bred.
+type Lineage struct {
+	mutex   sync.RWMutex
+	records map[uint64]*LineageRecord
+}
+
+// MARK: Constructors
+
+// NewLineage creates and returns a new, empty lineage.
+func NewLineage() *Lineage {
+	return &Lineage{
+		records: make(map[uint64]*LineageRecord),
+	}
+}
+
+// MARK: Public methods
+
+// Len returns the number of chromosomes in the lineage.
+func (l *Lineage) Len() int {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return len(l.records)
+}
+
+// Record returns the record of the chromosome with the given identifier.
+func (l *Lineage) Record(id uint64) (LineageRecord, bool) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	r, ok := l.records[id]
+	if !ok {
+		return LineageRecord{}, false
+	}
+	return *r, true
+}
+
+// RecordForChromosome returns the record of the given chromosome.
+func (l *Lineage) RecordForChromosome(chromosome *Chromosome) (LineageRecord, bool) {
+	return l.Record(chromosome.ID)
+}
+
+// Ancestors returns the records of the chromosome's ancestors up to the given
+// number of generations back, ordered by identifier. If `generations` is
+// negative, then every ancestor is returned.
+func (l *Lineage) Ancestors(id uint64, generations int) []LineageRecord {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	visited := make(map[uint64]bool)
+	frontier := []uint64{id}
+	for depth := 0; len(frontier) > 0 && (generations < 0 || depth < generations); depth++ {
+		var next []uint64
+		for _, child := range frontier {
+			r, ok := l.records[child]
+			if !ok {
+				continue
+			}
+
+			for _, parent := range r.Parents {
+				if !visited[parent] {
+					visited[parent] = true
+					next = append(next, parent)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	ancestors := make([]LineageRecord, 0, len(visited))
+	for ancestor := range visited {
+		if r, ok := l.records[ancestor]; ok {
+			ancestors = append(ancestors, *r)
+		}
+	}
+
+	sort.Slice(ancestors, func(i, j int) bool {
+		return ancestors[i].ID < ancestors[j].ID
+	})
+	return ancestors
+}
+
+// Children returns the records of the chromosomes that have the chromosome
+// with the given identifier as a parent, ordered by identifier.
+func (l *Lineage) Children(id uint64) []LineageRecord {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	var children []LineageRecord
+	for _, r := range l.records {
+		for _, parent := range r.Parents {
+			if parent == id {
+				children = append(children, *r)
+				break
+			}
+		}
+	}
+
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].ID < children[j].ID
+	})
+	return children
+}
+
+// MARK: Private methods
+
+// recordChromosome records how the chromosome was created. Records of the
+// initial population don't replace existing records. Does nothing if the
+// lineage is nil.
+func (l *Lineage) recordChromosome(chromosome *Chromosome, record LineageRecord) {
+	if l == nil {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if _, ok := l.records[chromosome.ID]; ok && record.Generation == 0 {
+		return
+	}
+
+	record.ID = chromosome.ID
+	record.Fitness = chromosome.Fitness
+	l.records[record.ID] = &record
+}
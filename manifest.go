@@ -0,0 +1,212 @@
+package genetics
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"runtime/debug"
+)
+
+// OperatorVersion is the version of the behaviour of the package's built-in
+// selection, crossover and mutation operators. It's incremented whenever a
+// change to an operator changes the random numbers it draws or the children
+// it breeds, so that manifests recorded with a different version can be
+// identified as unreproducible.
+const OperatorVersion = 1
+
+// modulePath is the path of the package's module.
+const modulePath = "github.com/colinc86/go-genetics"
+
+// ErrNotReproduced is returned when re-running a manifest's run doesn't
+// reproduce its results.
+var ErrNotReproduced = errors.New("genetics: run not reproduced")
+
+// Manifest objects describe a run in enough detail that its results can be
+// reproduced exactly. See `RunReproducibly` and `Rerun`.
+//
+// A run is only reproducible if its fitness function is deterministic, its
+// custom operators draw random numbers from `Random`, and its configuration
+// doesn't have a generation timeout.
+type Manifest struct {
+	// The seed of the package's random number generator.
+	Seed int64 `json:"seed" yaml:"seed"`
+
+	// The size of the initial population, the length of its chromosomes and the
+	// number of generations evolved.
+	PopulationSize   uint `json:"population_size" yaml:"population_size"`
+	ChromosomeLength uint `json:"chromosome_length" yaml:"chromosome_length"`
+	Generations      int  `json:"generations" yaml:"generations"`
+
+	// A summary of the configuration, see `EvolverConfiguration.Summary`, and a
+	// digest of the summary.
+	Configuration       map[string]interface{} `json:"configuration" yaml:"configuration"`
+	ConfigurationDigest string                 `json:"configuration_digest" yaml:"configuration_digest"`
+
+	// The version of the package's operators, see `OperatorVersion`.
+	OperatorVersion int `json:"operator_version" yaml:"operator_version"`
+
+	// A fingerprint of the code that performed the run: the Go version, and the
+	// version and checksum of the package's module when built as a dependency.
+	GoVersion     string `json:"go_version" yaml:"go_version"`
+	ModuleVersion string `json:"module_version,omitempty" yaml:"module_version,omitempty"`
+	ModuleSum     string `json:"module_sum,omitempty" yaml:"module_sum,omitempty"`
+
+	// A digest of the statistics of each generation, excluding elapsed time.
+	StatsDigest string `json:"stats_digest" yaml:"stats_digest"`
+
+	// The fitness and hash of the best chromosome, see `Chromosome.Hash`.
+	BestFitness float64 `json:"best_fitness" yaml:"best_fitness"`
+	BestHash    uint64  `json:"best_hash" yaml:"best_hash"`
+}
+
+// MARK: Public methods
+
+// Rerun re-runs the manifest's run with the evolver and returns the final
+// population and its best chromosome. The evolver must have the same fitness
+// function as the recorded run, and `generatingFunction` must be the same
+// function, or nil if it was nil.
+//
+// Returns `ErrNotReproduced` if the evolver's configuration or the package's
+// operators differ from those of the recorded run, or if the run's statistics
+// or best chromosome differ from those recorded.
+func (m Manifest) Rerun(evolver *Evolver, generatingFunction func(i, j int) float64) (Population, *Chromosome, error) {
+	if m.OperatorVersion != OperatorVersion {
+		return nil, nil, fmt.Errorf("%w: recorded with operator version %d, but the current version is %d", ErrNotReproduced, m.OperatorVersion, OperatorVersion)
+	}
+
+	digest, err := configurationDigest(evolver.Configuration)
+	if err != nil {
+		return nil, nil, err
+	}
+	if digest != m.ConfigurationDigest {
+		return nil, nil, fmt.Errorf("%w: the configuration differs from the recorded configuration", ErrNotReproduced)
+	}
+
+	population, best, manifest, err := RunReproducibly(evolver, m.Seed, m.PopulationSize, m.ChromosomeLength, m.Generations, generatingFunction)
+	if err != nil {
+		return population, best, err
+	}
+
+	switch {
+	case manifest.Generations != m.Generations:
+		return population, best, fmt.Errorf("%w: evolved %d generations rather than %d", ErrNotReproduced, manifest.Generations, m.Generations)
+	case manifest.StatsDigest != m.StatsDigest:
+		return population, best, fmt.Errorf("%w: the generation statistics differ", ErrNotReproduced)
+	case manifest.BestHash != m.BestHash || math.Float64bits(manifest.BestFitness) != math.Float64bits(m.BestFitness):
+		return population, best, fmt.Errorf("%w: the best chromosome differs", ErrNotReproduced)
+	}
+	return population, best, nil
+}
+
+// MARK: Public functions
+
+// RunReproducibly evolves a population for the given number of generations,
+// and returns the final population, its best chromosome and a manifest that the
+// run can be reproduced from.
+//
+// The package's random number generator is seeded with the seed, and the
+// initial population is generated with `generatingFunction`, which should draw
+// random numbers from `Random`. If it's nil, then genes are uniformly
+// distributed within the configuration's bounds, or in [-1, 1] if unbounded.
+func RunReproducibly(evolver *Evolver, seed int64, populationSize uint, chromosomeLength uint, generations int, generatingFunction func(i, j int) float64) (Population, *Chromosome, *Manifest, error) {
+	digest, err := configurationDigest(evolver.Configuration)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	random.Seed(seed)
+
+	var population Population
+	if generatingFunction != nil {
+		population = GeneratePopulation(populationSize, chromosomeLength, generatingFunction)
+	} else {
+		population = make(Population, populationSize)
+		for i := range population {
+			population[i] = evolver.Configuration.randomChromosome(int(chromosomeLength))
+		}
+	}
+
+	var last *EvolutionState
+	population, best, err := evolver.Evolve(population, func(state *EvolutionState) bool {
+		last = state
+		return state.Generation < generations
+	})
+	if err != nil {
+		return population, best, nil, err
+	}
+
+	manifest := &Manifest{
+		Seed:                seed,
+		PopulationSize:      populationSize,
+		ChromosomeLength:    chromosomeLength,
+		Configuration:       evolver.Configuration.Summary(),
+		ConfigurationDigest: digest,
+		OperatorVersion:     OperatorVersion,
+		GoVersion:           runtime.Version(),
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		module := &info.Main
+		for _, dependency := range info.Deps {
+			if dependency.Path == modulePath {
+				module = dependency
+			}
+		}
+
+		if module.Path == modulePath {
+			manifest.ModuleVersion = module.Version
+			manifest.ModuleSum = module.Sum
+		}
+	}
+
+	if last != nil {
+		manifest.Generations = last.Generation
+		manifest.StatsDigest = statsDigest(last.Stats)
+	}
+	if best != nil {
+		manifest.BestFitness = best.Fitness
+		manifest.BestHash = best.Hash()
+	}
+	return population, best, manifest, nil
+}
+
+// MARK: Private functions
+
+// configurationDigest returns the hexadecimal SHA-256 hash of the JSON
+// encoding of the configuration's summary.
+func configurationDigest(configuration *EvolverConfiguration) (string, error) {
+	summary, err := json.Marshal(configuration.Summary())
+	if err != nil {
+		return "", fmt.Errorf("unable to summarize configuration: %w", err)
+	}
+
+	hash := sha256.Sum256(summary)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// statsDigest returns the hexadecimal SHA-256 hash of the statistics of each
+// generation, excluding elapsed time.
+func statsDigest(stats EvolutionStats) string {
+	hash := sha256.New()
+	var buffer [8]byte
+	write := func(value uint64) {
+		binary.LittleEndian.PutUint64(buffer[:], value)
+		hash.Write(buffer[:])
+	}
+
+	for _, s := range stats {
+		write(uint64(s.Generation))
+		write(canonicalBits(s.Best))
+		write(canonicalBits(s.Mean))
+		write(canonicalBits(s.Worst))
+		write(canonicalBits(s.Diversity))
+		write(uint64(s.InvalidFitnesses))
+		write(uint64(s.Evaluations))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,200 @@
+package genetics
+
+import (
+	"math"
+	"time"
+)
+
+// MAPElites types illuminate the space of behaviors of a problem by keeping the
+// fittest chromosome, the elite, of each cell of a grid over the behavior
+// descriptors. The result is an archive of diverse, high-performing
+// chromosomes rather than a single optimum.
+//
+// Each generation, a batch of children is bred from parents chosen uniformly
+// from the elites, using the configuration's crossover and mutation methods,
+// rates and bounds. Each child replaces the elite of its cell if the cell is
+// empty or the child is fitter. The configuration's selection method and
+// elitism are not used.
+type MAPElites struct {
+	Configuration   *EvolverConfiguration
+	FitnessFunction FitnessFunction
+
+	// The function that describes the behavior of chromosomes whose `Behavior`
+	// wasn't recorded by the fitness function.
+	BehaviorFunction BehaviorFunction
+
+	// The range of each behavior descriptor. Descriptors outside of their range
+	// are placed in the nearest cell.
+	Bounds []GeneBounds
+
+	// The number of cells along each behavior descriptor.
+	Resolution []int
+
+	// The number of children bred each generation.
+	BatchSize int
+
+	elites map[int]*Chromosome
+}
+
+// MARK: Constructors
+
+// NewMAPElites creates and returns a new MAP-Elites archive over behavior
+// descriptors with the given bounds and number of cells along each descriptor.
+// A batch of 100 children is bred each generation.
+func NewMAPElites(configuration *EvolverConfiguration, fitnessFunction FitnessFunction, behaviorFunction BehaviorFunction, bounds []GeneBounds, resolution []int) *MAPElites {
+	return &MAPElites{
+		Configuration:    configuration,
+		FitnessFunction:  fitnessFunction,
+		BehaviorFunction: behaviorFunction,
+		Bounds:           bounds,
+		Resolution:       resolution,
+		BatchSize:        100,
+	}
+}
+
+// MARK: Public methods
+
+// Evolve evaluates and archives the chromosomes of the population, then breeds
+// and archives children until `shouldContinue` returns false. Returns the
+// elites in ascending order of fitness. Chromosomes already in the archive,
+// such as from a previous call, are kept.
+func (m *MAPElites) Evolve(population Population, shouldContinue func(state *EvolutionState) bool) (Population, error) {
+	evolver := NewEvolver(m.Configuration, m.FitnessFunction)
+	evolver.validate(population)
+	state := evolver.newState(population)
+
+	for _, c := range population {
+		if c.ID == 0 {
+			c.ID = nextChromosomeID()
+		}
+	}
+
+	if err := m.insert(evolver, population, state); err != nil {
+		return m.Elites(), err
+	}
+
+	elites := m.Elites()
+	state.Population = elites
+	state.Stats = append(state.Stats, newGenerationStats(elites, state.Generation, time.Since(state.start)))
+
+	for len(elites) > 0 && !evolver.budgetSpent(state) && !evolver.stopped() && shouldContinue(state) {
+		state.Generation++
+		state.selectParent = func() *Chromosome {
+			return elites[random.Intn(len(elites))]
+		}
+		state.selectMate = state.selectParent
+
+		children := make(Population, m.BatchSize)
+		for i := range children {
+			children[i] = evolver.breedChild(elites, i, state)
+		}
+
+		if err := m.insert(evolver, children, state); err != nil {
+			return m.Elites(), err
+		}
+
+		elites = m.Elites()
+		state.Population = elites
+		state.Stats = append(state.Stats, newGenerationStats(elites, state.Generation, time.Since(state.start)))
+	}
+
+	return elites, nil
+}
+
+// Elites returns the elite of every occupied cell in ascending order of
+// fitness.
+func (m MAPElites) Elites() Population {
+	elites := make(Population, 0, len(m.elites))
+	for _, c := range m.elites {
+		elites = append(elites, c)
+	}
+	elites.sortByFitness()
+	return elites
+}
+
+// Elite returns the elite of the cell with the given index along each behavior
+// descriptor, or nil if the cell is empty.
+func (m MAPElites) Elite(cell []int) *Chromosome {
+	index := 0
+	for i, r := range m.Resolution {
+		if i >= len(cell) || cell[i] < 0 || cell[i] >= r {
+			return nil
+		}
+		index = index*r + cell[i]
+	}
+	return m.elites[index]
+}
+
+// Cell returns the index, along each behavior descriptor, of the cell that the
+// behavior belongs to.
+func (m MAPElites) Cell(behavior []float64) []int {
+	cell := make([]int, len(m.Resolution))
+	for i, r := range m.Resolution {
+		if i >= len(behavior) || i >= len(m.Bounds) || m.Bounds[i].Max <= m.Bounds[i].Min {
+			continue
+		}
+
+		j := int(math.Floor((behavior[i] - m.Bounds[i].Min) / (m.Bounds[i].Max - m.Bounds[i].Min) * float64(r)))
+		cell[i] = int(math.Max(0.0, math.Min(float64(r-1), float64(j))))
+	}
+	return cell
+}
+
+// Coverage returns the fraction of cells that are occupied by an elite.
+func (m MAPElites) Coverage() float64 {
+	cells := 1
+	for _, r := range m.Resolution {
+		cells *= r
+	}
+	if cells <= 0 {
+		return 0.0
+	}
+	return float64(len(m.elites)) / float64(cells)
+}
+
+// QDScore returns the quality-diversity score of the archive, which is the sum
+// of the elites' fitness less `offset` each. Choose an offset no greater than
+// the lowest possible fitness so that every elite adds to the score.
+func (m MAPElites) QDScore(offset float64) float64 {
+	score := 0.0
+	for _, c := range m.elites {
+		if !math.IsNaN(c.Fitness) {
+			score += c.Fitness - offset
+		}
+	}
+	return score
+}
+
+// MARK: Private methods
+
+// insert evaluates the chromosomes and records their behaviors, then makes each
+// chromosome the elite of its cell if the cell is empty or the chromosome is
+// fitter than the cell's elite.
+func (m *MAPElites) insert(evolver *Evolver, chromosomes Population, state *EvolutionState) error {
+	if _, err := evolver.calculateFitnesses(chromosomes, state); err != nil {
+		return err
+	}
+
+	if m.elites == nil {
+		m.elites = make(map[int]*Chromosome)
+	}
+
+	for _, c := range chromosomes {
+		if len(c.Behavior) == 0 && m.BehaviorFunction != nil {
+			c.Behavior = append(c.Behavior[:0], m.BehaviorFunction(c, state)...)
+		}
+		if math.IsNaN(c.Fitness) || len(c.Behavior) == 0 {
+			continue
+		}
+
+		index := 0
+		for i, j := range m.Cell(c.Behavior) {
+			index = index*m.Resolution[i] + j
+		}
+
+		if elite, ok := m.elites[index]; !ok || c.Fitness > elite.Fitness {
+			m.elites[index] = c
+		}
+	}
+	return nil
+}
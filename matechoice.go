@@ -0,0 +1,71 @@
+package genetics
+
+import "math"
+
+// The maximum number of mates selected while searching for a mate that isn't
+// too genetically similar to the first parent.
+const maximumMateAttempts = 10
+
+// MateChoice objects define how mates are chosen for the first parent of each
+// crossover.
+type MateChoice struct {
+	// The selection method used to select mates. If nil, then the evolver
+	// configuration's selection method is used.
+	SelectionMethod *SelectionMethod
+
+	// The number of candidate mates that are selected. When greater than one, the
+	// candidate that is most genetically distant from the first parent is chosen
+	// (negative assortative mating).
+	Candidates int
+
+	// The minimum genetic distance between mates. Candidates that are closer to
+	// the first parent are rejected (incest prevention). If no acceptable
+	// candidate is found after several attempts, then the most distant candidate
+	// is chosen.
+	MinimumDistance float64
+}
+
+// MARK: Constructors
+
+// NewMateChoice creates and returns a new mate choice.
+func NewMateChoice(selectionMethod *SelectionMethod, candidates int, minimumDistance float64) *MateChoice {
+	return &MateChoice{
+		SelectionMethod: selectionMethod,
+		Candidates:      candidates,
+		MinimumDistance: minimumDistance,
+	}
+}
+
+// MARK: Private methods
+
+// selectMate selects a mate for the parent from the candidates chosen by the
+// selection function.
+func (m MateChoice) selectMate(parent *Chromosome, selectCandidate func() *Chromosome) *Chromosome {
+	candidates := m.Candidates
+	if candidates < 1 {
+		candidates = 1
+	}
+
+	var best *Chromosome
+	bestDistance := -math.MaxFloat64
+	accepted := 0
+	for attempt := 0; attempt < maximumMateAttempts*candidates && accepted < candidates; attempt++ {
+		candidate := selectCandidate()
+		distance := parent.Distance(candidate)
+		if distance >= m.MinimumDistance {
+			if accepted == 0 {
+				bestDistance = -math.MaxFloat64
+			}
+			accepted++
+		} else if accepted > 0 {
+			continue
+		}
+
+		if distance > bestDistance {
+			best = candidate
+			bestDistance = distance
+		}
+	}
+
+	return best
+}
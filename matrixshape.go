@@ -0,0 +1,124 @@
+package genetics
+
+import "gonum.org/v1/gonum/mat"
+
+// MatrixShape objects describe a matrix of genes stored in row-major order in
+// a chromosome's genes, such as the weights of a layer of a neural network or
+// the cells of a grid layout. A chromosome may contain several matrices at
+// different offsets.
+type MatrixShape struct {
+	// The number of rows and columns of the matrix.
+	Rows int
+	Cols int
+
+	// The index of the gene at the first row and column of the matrix.
+	Offset int
+}
+
+// MARK: Constructors
+
+// NewMatrixShape creates and returns a new matrix shape at the start of a
+// chromosome's genes.
+func NewMatrixShape(rows int, cols int) MatrixShape {
+	return MatrixShape{
+		Rows: rows,
+		Cols: cols,
+	}
+}
+
+// MARK: Public methods
+
+// Len returns the number of genes in the matrix.
+func (s MatrixShape) Len() int {
+	return s.Rows * s.Cols
+}
+
+// End returns the index of the gene following the last gene of the matrix.
+func (s MatrixShape) End() int {
+	return s.Offset + s.Len()
+}
+
+// Index returns the index of the gene at the given row and column.
+func (s MatrixShape) Index(row int, col int) int {
+	return s.Offset + row*s.Cols + col
+}
+
+// At returns the chromosome's gene at the given row and column.
+func (s MatrixShape) At(chromosome *Chromosome, row int, col int) float64 {
+	return chromosome.Genes[s.Index(row, col)]
+}
+
+// Set sets the chromosome's gene at the given row and column.
+func (s MatrixShape) Set(chromosome *Chromosome, row int, col int, value float64) {
+	chromosome.Genes[s.Index(row, col)] = value
+}
+
+// Matrix returns the chromosome's genes as a matrix. The matrix shares its
+// storage with the chromosome's genes, so changes to one change the other.
+func (s MatrixShape) Matrix(chromosome *Chromosome) *mat.Dense {
+	return mat.NewDense(s.Rows, s.Cols, chromosome.Genes[s.Offset:s.End()])
+}
+
+// RowCrossoverFunction returns a crossover function that copies each row of
+// the matrix whole from a random parent. Genes outside of the matrix are
+// crossed over uniformly. Use it with `NewCustomCrossoverMethod`.
+func (s MatrixShape) RowCrossoverFunction() CrossoverMethodFunction {
+	return func(cA *Chromosome, cB *Chromosome, options CrossoverOptions) *Chromosome {
+		child := s.uniformOutside(cA, cB, options)
+		for row := 0; row < s.Rows; row++ {
+			parent := cA
+			if options.Source().Intn(2) == 1 {
+				parent = cB
+			}
+			copy(child.Genes[s.Index(row, 0):s.Index(row+1, 0)], parent.Genes[s.Index(row, 0):s.Index(row+1, 0)])
+		}
+		return child
+	}
+}
+
+// ColumnCrossoverFunction returns a crossover function that copies each column
+// of the matrix whole from a random parent. Genes outside of the matrix are
+// crossed over uniformly. Use it with `NewCustomCrossoverMethod`.
+func (s MatrixShape) ColumnCrossoverFunction() CrossoverMethodFunction {
+	return func(cA *Chromosome, cB *Chromosome, options CrossoverOptions) *Chromosome {
+		child := s.uniformOutside(cA, cB, options)
+		for col := 0; col < s.Cols; col++ {
+			parent := cA
+			if options.Source().Intn(2) == 1 {
+				parent = cB
+			}
+			for row := 0; row < s.Rows; row++ {
+				child.Genes[s.Index(row, col)] = parent.Genes[s.Index(row, col)]
+			}
+		}
+		return child
+	}
+}
+
+// MARK: Public functions
+
+// GenerateMatrixPopulation generates a new population of chromosomes whose
+// genes form a matrix of the given shape.
+func GenerateMatrixPopulation(populationSize uint, shape MatrixShape, generatingFunction func(i, row, col int) float64) Population {
+	return GeneratePopulation(populationSize, uint(shape.End()), func(i, j int) float64 {
+		if j < shape.Offset {
+			return 0.0
+		}
+		return generatingFunction(i, (j-shape.Offset)/shape.Cols, (j-shape.Offset)%shape.Cols)
+	})
+}
+
+// MARK: Private methods
+
+// uniformOutside returns a child whose genes outside of the matrix are crossed
+// over uniformly from the parents.
+func (s MatrixShape) uniformOutside(cA *Chromosome, cB *Chromosome, options CrossoverOptions) *Chromosome {
+	child := &Chromosome{Genes: make([]float64, len(cA.Genes))}
+	for i := range child.Genes {
+		child.Genes[i] = cA.Genes[i]
+		if (i < s.Offset || i >= s.End()) && options.Source().Intn(2) == 1 {
+			child.Genes[i] = cB.Genes[i]
+		}
+	}
+	return child
+}
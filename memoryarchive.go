@@ -0,0 +1,184 @@
+package genetics
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ArchiveInjectionPolicy represents which archived chromosomes a memory archive
+// injects in to a population.
+type ArchiveInjectionPolicy uint
+
+// Archive injection policies.
+const (
+	// Every archived chromosome is injected.
+	ArchiveInjectionPolicyAll ArchiveInjectionPolicy = 0
+
+	// The archived chromosomes are evaluated in the current environment and the
+	// fittest are injected.
+	ArchiveInjectionPolicyBest ArchiveInjectionPolicy = 1
+)
+
+// ArchiveReplacementPolicy represents which archived chromosome is replaced
+// when a chromosome is added to a full memory archive.
+type ArchiveReplacementPolicy uint
+
+// Archive replacement policies.
+const (
+	// The oldest archived chromosome is replaced.
+	ArchiveReplacementPolicyOldest ArchiveReplacementPolicy = 0
+
+	// The archived chromosome closest to the added chromosome is replaced, which
+	// keeps the archive diverse when the landscape alternates between a few
+	// environments.
+	ArchiveReplacementPolicyClosest ArchiveReplacementPolicy = 1
+)
+
+// MemoryArchive objects store the best chromosomes of past environments of a
+// dynamic fitness landscape and inject them back in to the population when the
+// landscape changes, so that environments that recur are quickly recovered.
+type MemoryArchive struct {
+	// The maximum number of archived chromosomes.
+	Size int
+
+	// Which archived chromosomes are injected.
+	InjectionPolicy ArchiveInjectionPolicy
+
+	// The maximum number of chromosomes injected by the best policy. If zero,
+	// then every archived chromosome is injected.
+	Injections int
+
+	// Which archived chromosome is replaced when the archive is full.
+	ReplacementPolicy ArchiveReplacementPolicy
+
+	chromosomes Population
+}
+
+// MARK: Constructors
+
+// NewMemoryArchive creates and returns a new, empty memory archive of the
+// given size.
+func NewMemoryArchive(size int) *MemoryArchive {
+	return &MemoryArchive{Size: size}
+}
+
+// MARK: Public methods
+
+// Len returns the number of archived chromosomes.
+func (a *MemoryArchive) Len() int {
+	return len(a.chromosomes)
+}
+
+// Chromosomes returns the archived chromosomes.
+func (a *MemoryArchive) Chromosomes() Population {
+	return a.chromosomes
+}
+
+// Add adds a copy of the chromosome to the archive, replacing an archived
+// chromosome according to the replacement policy if the archive is full.
+func (a *MemoryArchive) Add(chromosome *Chromosome) {
+	if a.Size <= 0 {
+		return
+	}
+
+	c := chromosome.Clone()
+	if len(a.chromosomes) < a.Size {
+		a.chromosomes = append(a.chromosomes, c)
+		return
+	}
+
+	replaced := 0
+	if a.ReplacementPolicy == ArchiveReplacementPolicyClosest {
+		for i := range a.chromosomes {
+			if c.Distance(a.chromosomes[i]) < c.Distance(a.chromosomes[replaced]) {
+				replaced = i
+			}
+		}
+	}
+
+	a.chromosomes = append(a.chromosomes[:replaced], a.chromosomes[replaced+1:]...)
+	a.chromosomes = append(a.chromosomes, c)
+}
+
+// MARK: String methods
+
+func (p ArchiveInjectionPolicy) String() string {
+	switch p {
+	case ArchiveInjectionPolicyAll:
+		return "all"
+	case ArchiveInjectionPolicyBest:
+		return "best"
+	default:
+		return "unknown"
+	}
+}
+
+func (p ArchiveReplacementPolicy) String() string {
+	switch p {
+	case ArchiveReplacementPolicyOldest:
+		return "oldest"
+	case ArchiveReplacementPolicyClosest:
+		return "closest"
+	default:
+		return "unknown"
+	}
+}
+
+// MARK: Public functions
+
+// ParseArchiveInjectionPolicy returns the archive injection policy with the
+// given name. Valid names are "all" and "best". An empty name is the all
+// policy.
+func ParseArchiveInjectionPolicy(name string) (ArchiveInjectionPolicy, error) {
+	switch strings.ToLower(name) {
+	case "", "all":
+		return ArchiveInjectionPolicyAll, nil
+	case "best":
+		return ArchiveInjectionPolicyBest, nil
+	default:
+		return ArchiveInjectionPolicyAll, fmt.Errorf("unknown archive injection policy %q", name)
+	}
+}
+
+// ParseArchiveReplacementPolicy returns the archive replacement policy with
+// the given name. Valid names are "oldest" and "closest". An empty name is the
+// oldest policy.
+func ParseArchiveReplacementPolicy(name string) (ArchiveReplacementPolicy, error) {
+	switch strings.ToLower(name) {
+	case "", "oldest":
+		return ArchiveReplacementPolicyOldest, nil
+	case "closest":
+		return ArchiveReplacementPolicyClosest, nil
+	default:
+		return ArchiveReplacementPolicyOldest, fmt.Errorf("unknown archive replacement policy %q", name)
+	}
+}
+
+// MARK: Private methods
+
+// injections returns copies of the archived chromosomes to inject in to a
+// population, with new identifiers. The best policy evaluates the copies with
+// the evaluation function and returns them evaluated, unless the evaluation
+// function fails to evaluate them.
+func (a *MemoryArchive) injections(evaluate func(c *Chromosome) (float64, bool)) Population {
+	injections := make(Population, len(a.chromosomes))
+	for i, c := range a.chromosomes {
+		injections[i] = c.Clone()
+		injections[i].ID = nextChromosomeID()
+	}
+
+	if a.InjectionPolicy != ArchiveInjectionPolicyBest {
+		return injections
+	}
+
+	for _, c := range injections {
+		c.Fitness, c.evaluated = evaluate(c)
+		c.weight = c.Fitness
+	}
+
+	n := a.Injections
+	if n <= 0 {
+		n = len(injections)
+	}
+	return injections.TopK(n)
+}
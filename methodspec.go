@@ -0,0 +1,27 @@
+package genetics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MARK: Private functions
+
+// parseMethodSpec splits a method spec of the form "name" or "name:parameter"
+// in to its lowercased name and integer parameter. The returned parameter is
+// zero when the spec doesn't contain one.
+func parseMethodSpec(spec string) (string, int, error) {
+	parts := strings.SplitN(strings.TrimSpace(spec), ":", 2)
+	name := strings.ToLower(strings.TrimSpace(parts[0]))
+	if len(parts) == 1 {
+		return name, 0, nil
+	}
+
+	parameter, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil || parameter < 1 {
+		return name, 0, fmt.Errorf("invalid parameter %q in method spec %q", parts[1], spec)
+	}
+
+	return name, parameter, nil
+}
@@ -0,0 +1,94 @@
+package genetics
+
+import (
+	"expvar"
+	"time"
+)
+
+// Metrics types expose the live progress of an evolver as expvar variables so
+// that long-running evolutions can be monitored.
+type Metrics struct {
+	// The number of generations evolved.
+	Generations *expvar.Int
+
+	// The best fitness of the most recent generation.
+	BestFitness *expvar.Float
+
+	// The total number of fitness evaluations.
+	Evaluations *expvar.Int
+
+	// The number of fitness evaluations per second during the most recent
+	// generation.
+	EvaluationsPerSecond *expvar.Float
+
+	// The mean duration in seconds of the fitness evaluations of the most
+	// recent generation.
+	MeanEvaluationSeconds *expvar.Float
+
+	// The number of times each operator has been applied keyed by operator name.
+	// Keys are "selection", "crossover" and "mutation".
+	OperatorCounts *expvar.Map
+}
+
+// MARK: Constructors
+
+// NewMetrics creates a new set of metrics and publishes them as an expvar map
+// with the given name. Like `expvar.Publish`, it panics if the name is already
+// in use.
+func NewMetrics(name string) *Metrics {
+	m := &Metrics{
+		Generations:           new(expvar.Int),
+		BestFitness:           new(expvar.Float),
+		Evaluations:           new(expvar.Int),
+		EvaluationsPerSecond:  new(expvar.Float),
+		MeanEvaluationSeconds: new(expvar.Float),
+		OperatorCounts:        new(expvar.Map).Init(),
+	}
+
+	vars := expvar.NewMap(name)
+	vars.Set("generations", m.Generations)
+	vars.Set("best_fitness", m.BestFitness)
+	vars.Set("evaluations", m.Evaluations)
+	vars.Set("evaluations_per_second", m.EvaluationsPerSecond)
+	vars.Set("mean_evaluation_seconds", m.MeanEvaluationSeconds)
+	vars.Set("operator_counts", m.OperatorCounts)
+	return m
+}
+
+// MARK: Private methods
+
+// recordEvaluations records that a number of fitness evaluations were performed
+// over the given duration, of which `evaluationTime` was spent in the fitness
+// function.
+func (m *Metrics) recordEvaluations(count int, duration time.Duration, evaluationTime time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.Evaluations.Add(int64(count))
+	if duration > 0 {
+		m.EvaluationsPerSecond.Set(float64(count) / duration.Seconds())
+	}
+	if count > 0 {
+		m.MeanEvaluationSeconds.Set(evaluationTime.Seconds() / float64(count))
+	}
+}
+
+// recordGeneration records the statistics of an evaluated generation.
+func (m *Metrics) recordGeneration(stats GenerationStats) {
+	if m == nil {
+		return
+	}
+
+	m.Generations.Set(int64(stats.Generation))
+	m.BestFitness.Set(stats.Best)
+}
+
+// recordOperator records that the named operator was applied.
+func (m *Metrics) recordOperator(name string) {
+	if m == nil {
+		return
+	}
+
+	m.OperatorCounts.Add(name, 1)
+}
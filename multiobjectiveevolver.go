@@ -0,0 +1,139 @@
+package genetics
+
+import (
+	"math"
+	"time"
+)
+
+// MultiObjectiveEvolver types evolve a population towards the Pareto front of
+// several objectives using NSGA-II, optionally subject to constraints.
+//
+// Each generation, children are bred from parents chosen by binary tournaments
+// and the parents and children are sorted in to fronts by constraint
+// domination. The best fronts survive, and the least crowded chromosomes of the
+// last surviving front break ties. A chromosome's fitness is the negated index
+// of its front plus a fraction, of at most 0.5, that grows with its crowding
+// distance, so chromosomes of the Pareto front have a fitness in (0, 0.5].
+//
+// The configuration's crossover and mutation methods, rates and bounds are
+// used to breed children. Its selection method and elitism are not used.
+type MultiObjectiveEvolver struct {
+	Configuration *EvolverConfiguration
+
+	// The function that returns the objectives of a chromosome.
+	ObjectiveFunction MultiObjectiveFunction
+
+	// An optional function that returns the violation of a chromosome's
+	// constraints. Feasible chromosomes always rank above infeasible ones.
+	ConstraintFunction ConstraintFunction
+}
+
+// MARK: Constructors
+
+// NewMultiObjectiveEvolver creates and returns a new multi-objective evolver.
+func NewMultiObjectiveEvolver(configuration *EvolverConfiguration, objectiveFunction MultiObjectiveFunction) *MultiObjectiveEvolver {
+	return &MultiObjectiveEvolver{
+		Configuration:     configuration,
+		ObjectiveFunction: objectiveFunction,
+	}
+}
+
+// MARK: Public methods
+
+// Evolve evolves a population until `shouldContinue` returns false, and
+// returns the final generation sorted in ascending order of fitness along with
+// its Pareto front.
+func (e MultiObjectiveEvolver) Evolve(population Population, shouldContinue func(state *EvolutionState) bool) (Population, Population, error) {
+	evolver := e.evolver()
+	evolver.validate(population)
+	state := evolver.newState(population)
+
+	for _, c := range population {
+		if c.ID == 0 {
+			c.ID = nextChromosomeID()
+		}
+	}
+
+	if _, err := evolver.calculateFitnesses(population, state); err != nil {
+		return population, nil, err
+	}
+	e.rank(population)
+	population = population.SortedByFitness()
+	state.Stats = append(state.Stats, newGenerationStats(population, state.Generation, time.Since(state.start)))
+
+	for !evolver.budgetSpent(state) && !evolver.stopped() && shouldContinue(state) {
+		state.Generation++
+		state.selectParent = func() *Chromosome {
+			a := population[random.Intn(len(population))]
+			b := population[random.Intn(len(population))]
+			if b.Fitness > a.Fitness {
+				return b
+			}
+			return a
+		}
+		state.selectMate = state.selectParent
+
+		children := make(Population, len(population))
+		for i := range children {
+			children[i] = evolver.breedChild(population, i, state)
+		}
+
+		if _, err := evolver.calculateFitnesses(children, state); err != nil {
+			return population, population.ParetoFront(), err
+		}
+
+		combined := append(append(make(Population, 0, 2*len(population)), population...), children...)
+		e.rank(combined)
+		population = combined.TopK(len(population))
+		population.sortByFitness()
+
+		state.Population = population
+		state.Stats = append(state.Stats, newGenerationStats(population, state.Generation, time.Since(state.start)))
+	}
+
+	return population, population.ParetoFront(), nil
+}
+
+// MARK: Private methods
+
+// evolver returns an evolver that breeds and evaluates chromosomes by
+// recording their objectives and constraint violations.
+func (e MultiObjectiveEvolver) evolver() *Evolver {
+	return newObjectiveEvolver(e.Configuration, e.ObjectiveFunction, e.ConstraintFunction)
+}
+
+// rank sets the fitness and weight of each chromosome of the population from
+// the index of its front and its crowding distance.
+func (e MultiObjectiveEvolver) rank(population Population) {
+	for r, indexes := range nondominatedFronts(population) {
+		front := make(Population, len(indexes))
+		for i, j := range indexes {
+			front[i] = population[j]
+		}
+
+		for i, d := range crowdingDistances(front) {
+			crowding := 0.5
+			if !math.IsInf(d, 1) {
+				crowding = 0.5 * d / (1.0 + d)
+			}
+
+			front[i].Fitness = -float64(r) + crowding
+			front[i].weight = front[i].Fitness
+		}
+	}
+}
+
+// MARK: Private functions
+
+// newObjectiveEvolver returns an evolver whose fitness function records the
+// objectives and constraint violation of each chromosome and returns zero.
+func newObjectiveEvolver(configuration *EvolverConfiguration, objectiveFunction MultiObjectiveFunction, constraintFunction ConstraintFunction) *Evolver {
+	return NewEvolver(configuration, func(chromosome *Chromosome, state *EvolutionState) float64 {
+		chromosome.Objectives = append(chromosome.Objectives[:0], objectiveFunction(chromosome, state)...)
+		chromosome.Violation = 0.0
+		if constraintFunction != nil {
+			chromosome.Violation = constraintFunction(chromosome, state)
+		}
+		return 0.0
+	})
+}
@@ -0,0 +1,156 @@
+package genetics
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MutationMethodType represents a type of mutation method.
+type MutationMethodType uint
+
+// Types of mutation methods.
+const (
+	MutationMethodTypeGaussian MutationMethodType = 0
+	MutationMethodTypeUniform  MutationMethodType = 1
+	MutationMethodTypeCustom   MutationMethodType = 2
+)
+
+// MutationMethodFunction takes a chromosome and returns the mutated value of
+// its gene at index `i`.
+type MutationMethodFunction func(chromosome *Chromosome, i int, state *EvolutionState) float64
+
+// MutationMethod wraps a method type and function together.
+type MutationMethod struct {
+	Type     MutationMethodType
+	Function MutationMethodFunction
+
+	// The magnitude of mutations relative to the range of a gene's bounds, or
+	// absolute for unbounded genes. Only used by the functions of built-in
+	// method types created with `NewMutationMethod`, which read it each time
+	// they mutate a gene, so it may be changed after the method is created.
+	Scale float64
+}
+
+// defaultMutationScale is the scale of `GaussianMutationFunction` and
+// `UniformMutationFunction`.
+var defaultMutationScale = 0.1
+
+// MARK: String methods
+
+func (t MutationMethodType) String() string {
+	switch t {
+	case MutationMethodTypeGaussian:
+		return "gaussian"
+	case MutationMethodTypeUniform:
+		return "uniform"
+	default:
+		return "custom"
+	}
+}
+
+// MARK: Constructors
+
+// NewMutationMethod creates a new mutation method from the given mutation
+// method type and scale. To use a custom function, use the
+// `NewCustomMutationMethod` constructor.
+func NewMutationMethod(t MutationMethodType, scale float64) *MutationMethod {
+	m := &MutationMethod{
+		Type:  t,
+		Scale: scale,
+	}
+	m.Function = mutationFunctionForType(t, &m.Scale)
+	return m
+}
+
+// NewCustomMutationMethod creates a new custom mutation method from the
+// provided mutation method function.
+func NewCustomMutationMethod(f MutationMethodFunction) *MutationMethod {
+	return &MutationMethod{
+		Type:     MutationMethodTypeCustom,
+		Function: f,
+	}
+}
+
+// ParseMutationMethod creates a new mutation method with the given scale from
+// a method name. Valid names are "gaussian", "uniform" and the names of
+// mutations registered with `RegisterMutation`.
+func ParseMutationMethod(name string, scale float64) (*MutationMethod, error) {
+	t, ok := mutationMethodTypeForName(name)
+	if !ok {
+		f, ok := LookupMutation(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown mutation method %q", name)
+		}
+		return NewCustomMutationMethod(f), nil
+	}
+
+	return NewMutationMethod(t, scale), nil
+}
+
+// MARK: Public functions
+
+// GaussianMutationFunction implements the gaussian mutation function with a
+// scale of 0.1.
+var GaussianMutationFunction MutationMethodFunction = gaussianMutationFunctionWithScale(&defaultMutationScale)
+
+// UniformMutationFunction implements the uniform mutation function with a
+// scale of 0.1.
+var UniformMutationFunction MutationMethodFunction = uniformMutationFunctionWithScale(&defaultMutationScale)
+
+// MARK: Private functions
+
+// gaussianMutationFunctionWithScale returns a mutation function that adds
+// gaussian noise to a gene with a standard deviation of `*scale` times the
+// range of the gene's bounds. Genes with log or exp scale bounds are mutated on
+// their scale.
+func gaussianMutationFunctionWithScale(scale *float64) MutationMethodFunction {
+	return func(chromosome *Chromosome, i int, state *EvolutionState) float64 {
+		sigma := *scale
+		if bounds, ok := state.Configuration.BoundsForGene(i); ok {
+			if bounds.Scale != GeneScaleLinear {
+				return bounds.Denormalize(bounds.Normalize(chromosome.Genes[i]) + state.source().NormFloat64()*sigma)
+			}
+			sigma *= bounds.Max - bounds.Min
+		}
+		return chromosome.Genes[i] + state.source().NormFloat64()*sigma
+	}
+}
+
+// uniformMutationFunctionWithScale returns a mutation function that replaces a
+// bounded gene with a value uniformly distributed on its bounds' scale, and
+// perturbs an unbounded gene by a uniformly distributed value in the range
+// [-*scale, *scale).
+func uniformMutationFunctionWithScale(scale *float64) MutationMethodFunction {
+	return func(chromosome *Chromosome, i int, state *EvolutionState) float64 {
+		if bounds, ok := state.Configuration.BoundsForGene(i); ok {
+			return bounds.randomFrom(state.source())
+		}
+		return chromosome.Genes[i] + *scale*(state.source().Float64()*2.0-1.0)
+	}
+}
+
+// mutationFunctionForType returns the mutation function for the given type that
+// reads its scale from `scale` each time it's called.
+func mutationFunctionForType(t MutationMethodType, scale *float64) MutationMethodFunction {
+	switch t {
+	case MutationMethodTypeGaussian:
+		return gaussianMutationFunctionWithScale(scale)
+	case MutationMethodTypeUniform:
+		return uniformMutationFunctionWithScale(scale)
+	default:
+		return nil
+	}
+}
+
+// mutationMethodTypeForName returns the mutation method type with the given
+// case-insensitive name.
+func mutationMethodTypeForName(name string) (MutationMethodType, bool) {
+	switch strings.ToLower(name) {
+	case "gaussian":
+		return MutationMethodTypeGaussian, true
+	case "uniform":
+		return MutationMethodTypeUniform, true
+	default:
+		return MutationMethodTypeCustom, false
+	}
+}
@@ -0,0 +1,38 @@
+package genetics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMutationMethodScaleIsReadWhenMutating(t *testing.T) {
+	SetRandomSource(NewRandomSource(1))
+	defer SetRandomSource(nil)
+
+	tests := []struct {
+		t      MutationMethodType
+		bounds []GeneBounds
+	}{
+		{MutationMethodTypeGaussian, nil},
+		{MutationMethodTypeGaussian, []GeneBounds{{Min: -10.0, Max: 10.0}}},
+		{MutationMethodTypeUniform, nil},
+	}
+
+	for _, test := range tests {
+		configuration := DefaultEvolverConfiguration()
+		configuration.Bounds = test.bounds
+		state := &EvolutionState{Configuration: configuration}
+		chromosome := &Chromosome{Genes: []float64{0.5}}
+
+		method := NewMutationMethod(test.t, 0.1)
+		method.Scale = 0.0
+		if gene := method.Function(chromosome, 0, state); gene != 0.5 {
+			t.Errorf("%s with bounds %v: a scale of zero mutated the gene to %f", test.t, test.bounds, gene)
+		}
+
+		method.Scale = 1e-3
+		if gene := method.Function(chromosome, 0, state); gene == 0.5 || math.Abs(gene-0.5) > 0.1 {
+			t.Errorf("%s with bounds %v: a scale of 0.001 mutated the gene to %f", test.t, test.bounds, gene)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+// Package neuro evolves the weights of feed-forward neural networks by mapping
+// chromosomes to networks.
+package neuro
+
+import (
+	"math"
+
+	genetics "github.com/colinc86/go-genetics"
+)
+
+// Activation types are the activation function of a layer of neurons.
+type Activation func(x float64) float64
+
+// Architecture types describe the layers of a feed-forward network. Each
+// neuron has a weight for each neuron of the previous layer and a bias, which
+// are stored in a chromosome's genes as one matrix per layer. Each row of a
+// layer's matrix holds the weights of a neuron followed by its bias.
+type Architecture struct {
+	// The number of neurons of each layer, starting with the inputs and ending
+	// with the outputs.
+	Layers []int
+
+	// The activation functions of the hidden layers and the output layer.
+	Hidden Activation
+	Output Activation
+}
+
+// Network types are feed-forward networks whose weights are a chromosome's
+// genes.
+type Network struct {
+	Architecture Architecture
+	Chromosome   *genetics.Chromosome
+
+	shapes []genetics.MatrixShape
+}
+
+// MARK: Constructors
+
+// NewArchitecture creates and returns a new architecture with the given number
+// of neurons in each layer, tanh activation of hidden layers and linear
+// outputs.
+func NewArchitecture(layers ...int) Architecture {
+	return Architecture{
+		Layers: layers,
+		Hidden: Tanh,
+		Output: Identity,
+	}
+}
+
+// MARK: Public methods
+
+// GeneCount returns the number of genes of the architecture's chromosomes.
+func (a Architecture) GeneCount() int {
+	count := 0
+	for _, s := range a.Shapes() {
+		count += s.Len()
+	}
+	return count
+}
+
+// Shapes returns the shape of the weight matrix of each layer after the
+// inputs.
+func (a Architecture) Shapes() []genetics.MatrixShape {
+	var shapes []genetics.MatrixShape
+	offset := 0
+	for i := 1; i < len(a.Layers); i++ {
+		shape := genetics.MatrixShape{
+			Rows:   a.Layers[i],
+			Cols:   a.Layers[i-1] + 1,
+			Offset: offset,
+		}
+		shapes = append(shapes, shape)
+		offset = shape.End()
+	}
+	return shapes
+}
+
+// Network returns the network whose weights are the chromosome's genes.
+func (a Architecture) Network(chromosome *genetics.Chromosome) *Network {
+	return &Network{
+		Architecture: a,
+		Chromosome:   chromosome,
+		shapes:       a.Shapes(),
+	}
+}
+
+// Population generates a population of chromosomes whose weights are drawn
+// uniformly from ±1/√n, where n is the number of inputs of the neuron, and
+// whose biases are zero.
+func (a Architecture) Population(populationSize uint) genetics.Population {
+	population := genetics.GeneratePopulation(populationSize, uint(a.GeneCount()), func(i, j int) float64 {
+		return 0.0
+	})
+
+	for _, c := range population {
+		for _, s := range a.Shapes() {
+			limit := 1.0 / math.Sqrt(float64(s.Cols-1))
+			for row := 0; row < s.Rows; row++ {
+				for col := 0; col < s.Cols-1; col++ {
+					s.Set(c, row, col, (2.0*genetics.Random().Float64()-1.0)*limit)
+				}
+			}
+		}
+	}
+	return population
+}
+
+// Configuration returns an evolver configuration suited to evolving the
+// architecture's weights: tournament selection between three chromosomes,
+// neuron crossover, gaussian mutation with a scale of 0.1 at a rate of one
+// gene per chromosome, 5% elitism and weights bounded by ±5.
+func (a Architecture) Configuration() *genetics.EvolverConfiguration {
+	rate := 1.0
+	if n := a.GeneCount(); n > 0 {
+		rate = 1.0 / float64(n)
+	}
+
+	configuration := genetics.NewEvolverConfiguration(
+		genetics.NewTournamentSelectionMethod(3),
+		genetics.NewCustomCrossoverMethod(a.NeuronCrossoverFunction(), genetics.CrossoverOptions{}),
+		genetics.NewMutationMethod(genetics.MutationMethodTypeGaussian, 0.1),
+		0,
+		0.9,
+		rate,
+	)
+	configuration.ElitismRate = 0.05
+	configuration.Bounds = []genetics.GeneBounds{{Min: -5.0, Max: 5.0}}
+	return configuration
+}
+
+// NeuronCrossoverFunction returns a crossover function that copies the
+// weights and bias of each neuron whole from a random parent, so that neurons
+// that work well aren't broken apart.
+func (a Architecture) NeuronCrossoverFunction() genetics.CrossoverMethodFunction {
+	shapes := a.Shapes()
+	return func(cA *genetics.Chromosome, cB *genetics.Chromosome, options genetics.CrossoverOptions) *genetics.Chromosome {
+		child := &genetics.Chromosome{Genes: make([]float64, len(cA.Genes))}
+		copy(child.Genes, cA.Genes)
+		for _, s := range shapes {
+			for row := 0; row < s.Rows; row++ {
+				if options.Source().Intn(2) == 1 {
+					copy(child.Genes[s.Index(row, 0):s.Index(row+1, 0)], cB.Genes[s.Index(row, 0):s.Index(row+1, 0)])
+				}
+			}
+		}
+		return child
+	}
+}
+
+// FitnessFunction returns a fitness function that evaluates the network of
+// each chromosome with the given function.
+func (a Architecture) FitnessFunction(f func(network *Network, state *genetics.EvolutionState) float64) genetics.FitnessFunction {
+	return func(chromosome *genetics.Chromosome, state *genetics.EvolutionState) float64 {
+		return f(a.Network(chromosome), state)
+	}
+}
+
+// Forward returns the network's outputs for the given inputs.
+func (n Network) Forward(inputs []float64) []float64 {
+	activations := inputs
+	for i, s := range n.shapes {
+		activation := n.Architecture.Hidden
+		if i == len(n.shapes)-1 {
+			activation = n.Architecture.Output
+		}
+
+		outputs := make([]float64, s.Rows)
+		for row := range outputs {
+			sum := s.At(n.Chromosome, row, s.Cols-1)
+			for col := 0; col < s.Cols-1 && col < len(activations); col++ {
+				sum += s.At(n.Chromosome, row, col) * activations[col]
+			}
+
+			outputs[row] = sum
+			if activation != nil {
+				outputs[row] = activation(sum)
+			}
+		}
+		activations = outputs
+	}
+	return activations
+}
+
+// MARK: Public functions
+
+// Identity is the identity activation function.
+var Identity Activation = func(x float64) float64 {
+	return x
+}
+
+// Tanh is the hyperbolic tangent activation function.
+var Tanh Activation = math.Tanh
+
+// Sigmoid is the logistic activation function.
+var Sigmoid Activation = func(x float64) float64 {
+	return 1.0 / (1.0 + math.Exp(-x))
+}
+
+// ReLU is the rectified linear activation function.
+var ReLU Activation = func(x float64) float64 {
+	return math.Max(0.0, x)
+}
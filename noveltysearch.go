@@ -0,0 +1,173 @@
+package genetics
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// BehaviorFunction defines a function that returns a descriptor of a
+// chromosome's behavior, such as its final position in a maze or the trades
+// made by a strategy.
+type BehaviorFunction func(chromosome *Chromosome, state *EvolutionState) []float64
+
+// NoveltySearch objects drive selection by the novelty of chromosomes'
+// behaviors instead of, or blended with, their fitness.
+//
+// Each generation, the novelty of each chromosome is the mean distance from its
+// behavior to the nearest behaviors of the rest of the population and the
+// archive. Each chromosome's selection weight is then replaced by a blend of
+// its normalized weight and normalized novelty. Like fitness scaling, novelty
+// affects selection methods that select by weight, such as roulette and
+// tournament selection, while rank selection and elitism still use fitness.
+type NoveltySearch struct {
+	// The function that describes the behavior of chromosomes whose `Behavior`
+	// wasn't recorded by the fitness function.
+	BehaviorFunction BehaviorFunction
+
+	// The number of nearest behaviors that novelty is measured from.
+	Neighbours int
+
+	// The fraction of each selection weight that's due to novelty. A blend of
+	// one selects by novelty alone, and a blend of zero selects by fitness
+	// alone.
+	Blend float64
+
+	// The novelty at or above which chromosomes' behaviors are added to the
+	// archive. If zero, then no behaviors are added by novelty.
+	ArchiveThreshold float64
+
+	// The probability that each chromosome's behavior is added to the archive
+	// regardless of its novelty.
+	ArchiveRate float64
+
+	// The maximum number of behaviors in the archive. The oldest behaviors are
+	// removed first. If zero, then the archive's size isn't limited.
+	MaxArchiveSize int
+
+	archive [][]float64
+}
+
+// MARK: Constructors
+
+// NewNoveltySearch creates and returns a new novelty search that selects by
+// novelty alone, measured from the `neighbours` nearest behaviors, and that
+// archives 1% of behaviors at random up to an archive of 1,000 behaviors.
+func NewNoveltySearch(behaviorFunction BehaviorFunction, neighbours int) *NoveltySearch {
+	return &NoveltySearch{
+		BehaviorFunction: behaviorFunction,
+		Neighbours:       neighbours,
+		Blend:            1.0,
+		ArchiveRate:      0.01,
+		MaxArchiveSize:   1000,
+	}
+}
+
+// MARK: Public methods
+
+// Archive returns a copy of the behaviors in the archive, oldest first.
+func (n NoveltySearch) Archive() [][]float64 {
+	archive := make([][]float64, len(n.archive))
+	for i, b := range n.archive {
+		archive[i] = copyValues(b)
+	}
+	return archive
+}
+
+// MARK: Private methods
+
+// score records the behavior and novelty of each chromosome of the
+// population, blends the chromosomes' selection weights with their novelty and
+// adds novel behaviors to the archive.
+func (n *NoveltySearch) score(population Population, state *EvolutionState) {
+	if n == nil {
+		return
+	}
+
+	for _, c := range population {
+		if len(c.Behavior) == 0 && n.BehaviorFunction != nil {
+			c.Behavior = append(c.Behavior[:0], n.BehaviorFunction(c, state)...)
+		}
+	}
+
+	weights := make([]float64, len(population))
+	novelties := make([]float64, len(population))
+	for i, c := range population {
+		c.Novelty = n.novelty(c, population)
+		weights[i] = c.weight
+		novelties[i] = c.Novelty
+	}
+
+	normalize(weights)
+	normalize(novelties)
+	for i, c := range population {
+		c.weight = (1.0-n.Blend)*weights[i] + n.Blend*novelties[i]
+	}
+
+	for _, c := range population {
+		if len(c.Behavior) == 0 {
+			continue
+		}
+
+		if (n.ArchiveThreshold > 0.0 && c.Novelty >= n.ArchiveThreshold) || random.Float64() < n.ArchiveRate {
+			n.archive = append(n.archive, copyValues(c.Behavior))
+		}
+	}
+
+	if n.MaxArchiveSize > 0 && len(n.archive) > n.MaxArchiveSize {
+		n.archive = append(n.archive[:0], n.archive[len(n.archive)-n.MaxArchiveSize:]...)
+	}
+}
+
+// novelty returns the mean distance from the chromosome's behavior to the
+// nearest behaviors of the rest of the population and the archive.
+func (n NoveltySearch) novelty(chromosome *Chromosome, population Population) float64 {
+	var distances []float64
+	for _, c := range population {
+		if c != chromosome && len(c.Behavior) == len(chromosome.Behavior) {
+			distances = append(distances, floats.Distance(chromosome.Behavior, c.Behavior, 2.0))
+		}
+	}
+	for _, b := range n.archive {
+		if len(b) == len(chromosome.Behavior) {
+			distances = append(distances, floats.Distance(chromosome.Behavior, b, 2.0))
+		}
+	}
+
+	if len(distances) == 0 {
+		return 0.0
+	}
+
+	sort.Float64s(distances)
+	k := n.Neighbours
+	if k < 1 || k > len(distances) {
+		k = len(distances)
+	}
+	return floats.Sum(distances[:k]) / float64(k)
+}
+
+// MARK: Private functions
+
+// normalize scales the values in place to [0, 1]. Values are zero if they're
+// all equal, and NaN values are zero.
+func normalize(values []float64) {
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			min = math.Min(min, v)
+			max = math.Max(max, v)
+		}
+	}
+
+	for i, v := range values {
+		switch {
+		case math.IsNaN(v) || math.IsInf(v, -1) || max <= min:
+			values[i] = 0.0
+		case math.IsInf(v, 1):
+			values[i] = 1.0
+		default:
+			values[i] = (v - min) / (max - min)
+		}
+	}
+}
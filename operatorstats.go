@@ -0,0 +1,158 @@
+package genetics
+
+import "math/rand"
+
+// Parameters of adaptive operator selection by probability matching.
+const (
+	// The minimum probability of choosing an operator, divided by the number of
+	// operators.
+	operatorMinimumProbability = 0.1
+
+	// The rate at which an operator's estimated quality adapts to its most recent
+	// success rate.
+	operatorAdaptationRate = 0.3
+)
+
+// OperatorStats objects track how often a crossover or mutation operator
+// produces children that are fitter than their parents.
+type OperatorStats struct {
+	// The name of the operator.
+	Name string
+
+	// The number of evaluated children that the operator has been applied to.
+	Applications int
+
+	// The number of evaluated children that the operator has been applied to
+	// that are fitter than their fittest parent.
+	Successes int
+
+	// The probability that the operator is chosen.
+	Probability float64
+
+	// The estimated quality of the operator used by adaptive operator selection.
+	quality float64
+
+	// The number of applications and successes in the current generation.
+	generationApplications int
+	generationSuccesses    int
+}
+
+// breedingRecord objects describe how a chromosome was bred so that the
+// operators applied to it can be credited once it has been evaluated.
+type breedingRecord struct {
+	// Whether or not the chromosome was bred and its operators haven't been
+	// credited yet.
+	pending bool
+
+	// The fitness of the chromosome's fittest parent.
+	parentFitness float64
+
+	// The index of the applied crossover operator or -1.
+	crossover int
+
+	// The index of the applied mutation operator or -1.
+	mutation int
+
+	// The lineage identifiers of the chromosome's parents. Only recorded when
+	// the evolver tracks lineage.
+	parents []uint64
+}
+
+// MARK: Public methods
+
+// SuccessRate returns the fraction of the operator's applications that produced
+// children fitter than their parents.
+func (s OperatorStats) SuccessRate() float64 {
+	if s.Applications == 0 {
+		return 0.0
+	}
+	return float64(s.Successes) / float64(s.Applications)
+}
+
+// MARK: Private functions
+
+// newOperatorStats returns the initial statistics of operators with the given
+// names and relative weights. If the weights are nil or sum to zero, then each
+// operator is equally likely to be chosen.
+func newOperatorStats(names []string, weights []float64) []OperatorStats {
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+
+	stats := make([]OperatorStats, len(names))
+	for i, name := range names {
+		stats[i] = OperatorStats{
+			Name:        name,
+			Probability: 1.0 / float64(len(names)),
+			quality:     1.0,
+		}
+
+		if total > 0.0 {
+			stats[i].Probability = weights[i] / total
+			stats[i].quality = stats[i].Probability * float64(len(names))
+		}
+	}
+	return stats
+}
+
+// chooseOperator returns the index of an operator chosen according to the
+// operators' probabilities, drawing from the given source.
+func chooseOperator(stats []OperatorStats, rng *rand.Rand) int {
+	r := rng.Float64()
+	sum := 0.0
+	for i, s := range stats {
+		sum += s.Probability
+		if r < sum {
+			return i
+		}
+	}
+	return len(stats) - 1
+}
+
+// creditOperator records an evaluated application of the operator at index `i`.
+func creditOperator(stats []OperatorStats, i int, success bool) {
+	if i < 0 || i >= len(stats) {
+		return
+	}
+
+	stats[i].Applications++
+	stats[i].generationApplications++
+	if success {
+		stats[i].Successes++
+		stats[i].generationSuccesses++
+	}
+}
+
+// finishOperatorGeneration resets the operators' generation counts and, if
+// adaptive, updates their probabilities by probability matching on their
+// success rates during the generation.
+func finishOperatorGeneration(stats []OperatorStats, adaptive bool) {
+	if adaptive {
+		for i := range stats {
+			if stats[i].generationApplications > 0 {
+				reward := float64(stats[i].generationSuccesses) / float64(stats[i].generationApplications)
+				stats[i].quality += operatorAdaptationRate * (reward - stats[i].quality)
+			}
+		}
+
+		sum := 0.0
+		for _, s := range stats {
+			sum += s.quality
+		}
+
+		minimum := operatorMinimumProbability / float64(len(stats))
+		for i := range stats {
+			if sum > 0.0 {
+				stats[i].Probability = minimum + (1.0-float64(len(stats))*minimum)*stats[i].quality/sum
+			} else {
+				stats[i].Probability = 1.0 / float64(len(stats))
+			}
+		}
+	}
+
+	for i := range stats {
+		stats[i].generationApplications = 0
+		stats[i].generationSuccesses = 0
+	}
+}
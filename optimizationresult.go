@@ -0,0 +1,80 @@
+package genetics
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// OptimizationResult objects summarize the progress of an optimizer so that
+// the robustness of the chromosomes it found can be judged.
+type OptimizationResult struct {
+	// The fittest chromosomes of the most recent generation in descending order
+	// of fitness.
+	Best Population
+
+	// The distribution of each gene's values among the best chromosomes, using
+	// the sample standard deviation. Genes with narrow distributions have
+	// converged, while wide distributions indicate genes that fitness is
+	// insensitive to.
+	Genes []GeneDistribution
+
+	// The statistics of each evolved generation.
+	History EvolutionStats
+
+	// The number of generations evolved.
+	Generations int
+
+	// The total number of fitness evaluations performed.
+	Evaluations int
+}
+
+// GeneDistribution objects describe the distribution of a gene's values in a
+// population.
+type GeneDistribution struct {
+	Mean   float64
+	StdDev float64
+	Min    float64
+	Max    float64
+}
+
+// MARK: Private functions
+
+// newOptimizationResult creates a result from the fittest chromosomes and the
+// state of an evolution.
+func newOptimizationResult(best Population, history EvolutionStats, generations int, evaluations int) *OptimizationResult {
+	result := &OptimizationResult{
+		Best:        make(Population, len(best)),
+		History:     make(EvolutionStats, len(history)),
+		Generations: generations,
+		Evaluations: evaluations,
+	}
+
+	for i, c := range best {
+		result.Best[i] = c.Clone()
+	}
+	copy(result.History, history)
+
+	if len(best) == 0 {
+		return result
+	}
+
+	result.Genes = make([]GeneDistribution, len(best[0].Genes))
+	values := make([]float64, len(best))
+	for j := range result.Genes {
+		d := GeneDistribution{Min: math.Inf(1), Max: math.Inf(-1)}
+		for i, c := range best {
+			values[i] = c.Genes[j]
+			d.Min = math.Min(d.Min, values[i])
+			d.Max = math.Max(d.Max, values[i])
+		}
+
+		if len(values) > 1 {
+			d.Mean, d.StdDev = stat.MeanStdDev(values, nil)
+		} else {
+			d.Mean = values[0]
+		}
+		result.Genes[j] = d
+	}
+	return result
+}
@@ -0,0 +1,455 @@
+package genetics
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// subscriptionBuffer is the number of events buffered for each of an
+// optimizer's subscribers.
+const subscriptionBuffer = 16
+
+// Optimizer types incrementally evolve a population in cycles of generations
+// so that evolution can be interleaved with other work.
+type Optimizer struct {
+	// The evolver used to evolve the population.
+	Evolver *Evolver
+
+	// The number of generations evolved by each call to `Optimize`.
+	GenerationsPerCycle int
+
+	// The number of fittest chromosomes included in the optimizer's result. If
+	// zero, then the number of elites is used, or one if there are no elites.
+	ResultSize int
+
+	population      Population
+	state           *EvolutionState
+	results         chan *Chromosome
+	paused          bool
+	optimizing      bool
+	best            *Chromosome
+	history         EvolutionStats
+	generations     int
+	evaluations     int
+	mutationRate    float64
+	newRate         *float64
+	checkpoint      Checkpoint
+	subscribers     map[chan OptimizerEvent]bool
+	published       int
+	record          float64
+	runMutex        sync.Mutex
+	pauseMutex      sync.Mutex
+	pauseCond       *sync.Cond
+	stateMutex      sync.RWMutex
+	generationMutex sync.RWMutex
+}
+
+// OptimizerEvent objects describe a generation evolved by an optimizer.
+type OptimizerEvent struct {
+	// The statistics of the generation.
+	Stats GenerationStats
+
+	// A copy of the best chromosome of the generation.
+	Best *Chromosome
+
+	// Whether or not the generation's best chromosome is fitter than the best
+	// chromosome of every previous generation.
+	Improved bool
+}
+
+// MARK: Constructors
+
+// NewOptimizer creates and returns a new optimizer that evolves the given
+// population.
+func NewOptimizer(evolver *Evolver, population Population, generationsPerCycle int) *Optimizer {
+	o := &Optimizer{
+		Evolver:             evolver,
+		GenerationsPerCycle: generationsPerCycle,
+		population:          population,
+		results:             make(chan *Chromosome, 1),
+	}
+	o.pauseCond = sync.NewCond(&o.pauseMutex)
+	return o
+}
+
+// MARK: Public methods
+
+// Optimize evolves the population for `GenerationsPerCycle` generations and
+// returns the best chromosome.
+func (o *Optimizer) Optimize() (*Chromosome, error) {
+	return o.Step(o.GenerationsPerCycle)
+}
+
+// Step evolves the population for `n` generations and returns the best
+// chromosome. The best chromosome is also sent on the optimizer's result
+// stream.
+//
+// Once the evolver's configuration's evaluation budget is spent, or its stop
+// channel is closed, no more generations are evolved.
+//
+// Calls to `Step` and `Optimize` are serialized, and block between
+// generations while the optimizer is paused. If evaluating a generation fails,
+// then the best chromosome of the previous generation is returned with the
+// error.
+//
+// The chromosomes of the most recently evolved generation are only copied when
+// they're read, so `CurrentBest`, `Result` and `Checkpoint` wait for a
+// generation that's being evolved to complete. They mustn't be called from the
+// evolver's fitness function or callbacks.
+func (o *Optimizer) Step(n int) (*Chromosome, error) {
+	o.runMutex.Lock()
+	defer o.runMutex.Unlock()
+
+	o.setOptimizing(true)
+	defer o.setOptimizing(false)
+
+	if o.state == nil {
+		o.Evolver.validate(o.population)
+		err := o.advance(func() (Population, error) {
+			population, state, err := o.Evolver.initialize(o.population)
+			if err == nil {
+				o.state = state
+			}
+			return population, err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := 0; i < n && !o.Evolver.budgetSpent(o.state) && !o.Evolver.stopped(); i++ {
+		o.waitWhilePaused()
+		o.applyMutationRate()
+		err := o.advance(func() (Population, error) {
+			return o.Evolver.evolveGeneration(o.population, o.state)
+		})
+		if err != nil {
+			return o.CurrentBest(), err
+		}
+	}
+
+	best := o.CurrentBest()
+	if best != nil {
+		o.publish(best)
+	}
+	return best, nil
+}
+
+// RunContinuously re-optimizes the population until the context is done, and
+// returns the context's error or the first error encountered while evolving.
+//
+// The population is optimized for `GenerationsPerCycle` generations every
+// interval, if the interval is positive, and whenever a fitness function is
+// received from `data`. Received fitness functions replace the evolver's
+// fitness function, so send a new function when new data arrives. The best
+// chromosome of each cycle is sent on the optimizer's result stream.
+func (o *Optimizer) RunContinuously(ctx context.Context, interval time.Duration, data <-chan FitnessFunction) error {
+	var ticks <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case f, ok := <-data:
+			if !ok {
+				data = nil
+				continue
+			}
+
+			if err := o.SetFitnessFunction(f); err != nil {
+				return err
+			}
+		case <-ticks:
+		}
+
+		if _, err := o.Optimize(); err != nil {
+			return err
+		}
+	}
+}
+
+// SetFitnessFunction replaces the evolver's fitness function between
+// generations. If the population has been evaluated, then it's evaluated again
+// with the new function so that the next generation is bred from up-to-date
+// fitness.
+func (o *Optimizer) SetFitnessFunction(f FitnessFunction) error {
+	o.runMutex.Lock()
+	defer o.runMutex.Unlock()
+
+	o.Evolver.FitnessFunction = f
+	if o.state == nil {
+		return nil
+	}
+
+	return o.advance(func() (Population, error) {
+		for _, c := range o.population {
+			c.evaluated = false
+		}
+
+		if _, err := o.Evolver.calculateFitnesses(o.population, o.state); err != nil {
+			return nil, err
+		}
+
+		o.population.sortWith(o.Evolver.Configuration.Comparator)
+		return o.population, nil
+	})
+}
+
+// Pause pauses the optimizer before its next generation is evolved.
+func (o *Optimizer) Pause() {
+	o.pauseMutex.Lock()
+	defer o.pauseMutex.Unlock()
+	o.paused = true
+}
+
+// Resume resumes a paused optimizer.
+func (o *Optimizer) Resume() {
+	o.pauseMutex.Lock()
+	defer o.pauseMutex.Unlock()
+	o.paused = false
+	o.pauseCond.Broadcast()
+}
+
+// IsPaused returns whether or not the optimizer is paused.
+func (o *Optimizer) IsPaused() bool {
+	o.pauseMutex.Lock()
+	defer o.pauseMutex.Unlock()
+	return o.paused
+}
+
+// SetMutationRate sets the mutation rate used to breed the optimizer's next
+// generation, and may be called while the optimizer is evolving. If the
+// evolver's configuration uses an adaptive mutation rate, then the rate
+// continues to adapt from the new rate.
+func (o *Optimizer) SetMutationRate(rate float64) {
+	o.stateMutex.Lock()
+	defer o.stateMutex.Unlock()
+	o.newRate = &rate
+	o.mutationRate = rate
+}
+
+// MutationRate returns the optimizer's current mutation rate.
+func (o *Optimizer) MutationRate() float64 {
+	o.stateMutex.RLock()
+	defer o.stateMutex.RUnlock()
+	if o.newRate == nil && o.state == nil {
+		return o.Evolver.Configuration.MutationRate
+	}
+	return o.mutationRate
+}
+
+// Checkpoint returns a checkpoint of the most recently evolved generation
+// containing copies of its chromosomes, or nil if the population hasn't been
+// evaluated yet. The checkpoint contains a copy of the package's random source
+// as it was after the generation was evolved, if one was set with
+// `SetRandomSource`.
+func (o *Optimizer) Checkpoint() *Checkpoint {
+	o.generationMutex.RLock()
+	defer o.generationMutex.RUnlock()
+	o.stateMutex.RLock()
+	defer o.stateMutex.RUnlock()
+	if o.best == nil {
+		return nil
+	}
+
+	checkpoint := o.checkpoint
+	checkpoint.Population = make(Population, len(o.population))
+	for i, c := range o.population {
+		checkpoint.Population[i] = c.Clone()
+	}
+	if o.checkpoint.Random != nil {
+		checkpoint.Random = &RandomSource{state: o.checkpoint.Random.state}
+	}
+	return &checkpoint
+}
+
+// Subscribe returns a channel that receives an event for each generation that
+// the optimizer evolves, along with a function that cancels the subscription
+// and closes the channel. Events aren't waited for: if the channel's buffer is
+// full, then the generation's event is discarded.
+func (o *Optimizer) Subscribe() (<-chan OptimizerEvent, func()) {
+	events := make(chan OptimizerEvent, subscriptionBuffer)
+
+	o.stateMutex.Lock()
+	defer o.stateMutex.Unlock()
+	if o.subscribers == nil {
+		o.subscribers = make(map[chan OptimizerEvent]bool)
+	}
+	o.subscribers[events] = true
+
+	return events, func() {
+		o.stateMutex.Lock()
+		defer o.stateMutex.Unlock()
+		if o.subscribers[events] {
+			delete(o.subscribers, events)
+			close(events)
+		}
+	}
+}
+
+// IsOptimizing returns whether or not the optimizer is currently evolving its
+// population.
+func (o *Optimizer) IsOptimizing() bool {
+	o.stateMutex.RLock()
+	defer o.stateMutex.RUnlock()
+	return o.optimizing
+}
+
+// CurrentBest returns a copy of the best chromosome of the most recently
+// evolved generation, or nil if the population hasn't been evaluated yet.
+func (o *Optimizer) CurrentBest() *Chromosome {
+	o.generationMutex.RLock()
+	defer o.generationMutex.RUnlock()
+	o.stateMutex.RLock()
+	defer o.stateMutex.RUnlock()
+	if o.best == nil {
+		return nil
+	}
+	return o.best.Clone()
+}
+
+// Generations returns the number of generations that have been evolved.
+func (o *Optimizer) Generations() int {
+	o.stateMutex.RLock()
+	defer o.stateMutex.RUnlock()
+	return o.generations
+}
+
+// Result returns a summary of the optimizer's progress containing copies of the
+// fittest chromosomes of the most recently evolved generation, or nil if the
+// population hasn't been evaluated yet.
+func (o *Optimizer) Result() *OptimizationResult {
+	o.generationMutex.RLock()
+	defer o.generationMutex.RUnlock()
+	o.stateMutex.RLock()
+	defer o.stateMutex.RUnlock()
+	if o.best == nil {
+		return nil
+	}
+	return newOptimizationResult(o.population.TopK(o.resultSize()), o.history, o.generations, o.evaluations)
+}
+
+// Results returns the optimizer's result stream which receives the best
+// chromosome after each cycle. If a result hasn't been received before the
+// next cycle completes, then it is replaced by the newer result.
+func (o *Optimizer) Results() <-chan *Chromosome {
+	return o.results
+}
+
+// MARK: Private methods
+
+// setOptimizing sets whether or not the optimizer is evolving its population.
+func (o *Optimizer) setOptimizing(optimizing bool) {
+	o.stateMutex.Lock()
+	defer o.stateMutex.Unlock()
+	o.optimizing = optimizing
+}
+
+// advance replaces the optimizer's population with the population returned by
+// the function, which may modify the chromosomes of the current population, and
+// records the new population's state. Readers of the population's chromosomes
+// wait until it's replaced.
+func (o *Optimizer) advance(next func() (Population, error)) error {
+	o.generationMutex.Lock()
+	defer o.generationMutex.Unlock()
+
+	population, err := next()
+	if err != nil {
+		return err
+	}
+
+	o.population = population
+	o.update()
+	return nil
+}
+
+// update records the state of the most recently evolved generation so that it
+// can be safely read from other goroutines. The generation's chromosomes are
+// referenced rather than copied, so the caller must hold the generation mutex.
+func (o *Optimizer) update() {
+	o.stateMutex.Lock()
+	defer o.stateMutex.Unlock()
+	o.generations = o.state.Generation
+	o.evaluations = o.state.Evaluations
+	o.history = o.state.Stats
+	if o.newRate == nil {
+		o.mutationRate = o.state.MutationRate
+	}
+
+	if len(o.population) > 0 {
+		o.best = o.population[len(o.population)-1]
+	}
+
+	o.checkpoint.Generation = o.state.Generation
+	o.checkpoint.MutationRate = o.state.MutationRate
+	o.checkpoint.Random = currentRandomSource()
+
+	for ; o.published < len(o.history); o.published++ {
+		stats := o.history[o.published]
+		event := OptimizerEvent{
+			Stats:    stats,
+			Improved: o.published == 0 || stats.Best > o.record,
+		}
+		if event.Improved {
+			o.record = stats.Best
+		}
+		if o.best != nil && len(o.subscribers) > 0 {
+			event.Best = o.best.Clone()
+		}
+
+		for events := range o.subscribers {
+			select {
+			case events <- event:
+			default:
+			}
+		}
+	}
+}
+
+// applyMutationRate applies a mutation rate set with `SetMutationRate` to the
+// state of the evolution.
+func (o *Optimizer) applyMutationRate() {
+	o.stateMutex.Lock()
+	defer o.stateMutex.Unlock()
+	if o.newRate != nil {
+		o.state.MutationRate = *o.newRate
+		o.newRate = nil
+	}
+}
+
+// resultSize returns the number of fittest chromosomes included in results.
+func (o *Optimizer) resultSize() int {
+	if o.ResultSize > 0 {
+		return o.ResultSize
+	}
+
+	if n := o.Evolver.Configuration.EliteCount(len(o.population)); n > 0 {
+		return n
+	}
+	return 1
+}
+
+// waitWhilePaused blocks until the optimizer is not paused.
+func (o *Optimizer) waitWhilePaused() {
+	o.pauseMutex.Lock()
+	defer o.pauseMutex.Unlock()
+	for o.paused {
+		o.pauseCond.Wait()
+	}
+}
+
+// publish sends the chromosome on the result stream without blocking,
+// discarding any result that hasn't been received yet.
+func (o *Optimizer) publish(chromosome *Chromosome) {
+	select {
+	case <-o.results:
+	default:
+	}
+	o.results <- chromosome
+}
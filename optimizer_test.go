@@ -0,0 +1,76 @@
+package genetics
+
+import (
+	"reflect"
+	"testing"
+)
+
+// reusingOptimizer returns an optimizer whose evolver reuses chromosomes, so
+// that the chromosomes of each generation are overwritten by the next.
+func reusingOptimizer() *Optimizer {
+	configuration := DefaultEvolverConfiguration()
+	configuration.ReuseChromosomes = true
+	return NewOptimizer(NewEvolver(configuration, dryRunFitness), dryRunPopulation(), 5)
+}
+
+func TestOptimizerCopiesAreUnaffectedByLaterGenerations(t *testing.T) {
+	optimizer := reusingOptimizer()
+	if _, err := optimizer.Optimize(); err != nil {
+		t.Fatal(err)
+	}
+
+	best := optimizer.CurrentBest()
+	result := optimizer.Result()
+	checkpoint := optimizer.Checkpoint()
+	expectedBest := best.Clone()
+	expectedResult := result.Best[0].Clone()
+	expectedCheckpoint := make(Population, len(checkpoint.Population))
+	for i, c := range checkpoint.Population {
+		expectedCheckpoint[i] = c.Clone()
+	}
+
+	if _, err := optimizer.Step(10); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(best, expectedBest) {
+		t.Error("evolving modified the current best chromosome")
+	}
+	if !reflect.DeepEqual(result.Best[0], expectedResult) {
+		t.Error("evolving modified the result")
+	}
+	if !reflect.DeepEqual(checkpoint.Population, expectedCheckpoint) {
+		t.Error("evolving modified the checkpoint")
+	}
+	if checkpoint.Generation != 5 {
+		t.Errorf("the checkpoint's generation is %d, expected 5", checkpoint.Generation)
+	}
+}
+
+func TestOptimizerReadsWhileStepping(t *testing.T) {
+	// Run with the race detector to check that chromosomes aren't read while
+	// they're overwritten.
+	optimizer := reusingOptimizer()
+	done := make(chan error)
+	go func() {
+		_, err := optimizer.Step(50)
+		done <- err
+	}()
+
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatal(err)
+			}
+			return
+		default:
+		}
+
+		if checkpoint := optimizer.Checkpoint(); checkpoint != nil && len(checkpoint.Population) != 20 {
+			t.Errorf("the checkpoint has %d chromosomes, expected 20", len(checkpoint.Population))
+		}
+		optimizer.CurrentBest()
+		optimizer.Result()
+	}
+}
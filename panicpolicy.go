@@ -0,0 +1,175 @@
+package genetics
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// PanicPolicy represents how panics in the callbacks that an evolver calls,
+// such as its fitness function and its selection, crossover, mutation, repair
+// and validation functions, are handled.
+type PanicPolicy uint
+
+// Panic policies.
+const (
+	// Panics aren't recovered, so they stop the process.
+	PanicPolicyNone PanicPolicy = 0
+
+	// Panics are recovered and evolution stops with a `*PanicError` that holds
+	// a checkpoint from which the evolution can be resumed.
+	PanicPolicyAbort PanicPolicy = 1
+
+	// Panics are recovered and logged, and evolution continues. Chromosomes
+	// whose evaluation panicked are marked, see `Chromosome.Panicked`, and are
+	// given a NaN fitness, which is handled by the configuration's invalid
+	// fitness policy. Children whose breeding panicked are replaced by copies
+	// of a selected parent.
+	PanicPolicyContinue PanicPolicy = 2
+)
+
+// PanicError types describe a panic recovered from an evolver's callback.
+type PanicError struct {
+	// The callback that panicked: "fitness", "selection", "crossover",
+	// "mutation", "repair" or "validation".
+	Callback string
+
+	// The value that the callback panicked with and the stack trace of the
+	// panic.
+	Value interface{}
+	Stack []byte
+
+	// The generation being evolved when the callback panicked.
+	Generation int
+
+	// A copy of the chromosome whose evaluation panicked, or nil if the panic
+	// occurred while breeding.
+	Chromosome *Chromosome
+
+	// A checkpoint of the evolution when the callback panicked. Evaluation
+	// panics checkpoint the generation being evaluated, whose evaluated
+	// chromosomes aren't evaluated again when it's resumed, and breeding panics
+	// checkpoint the generation being bred from.
+	Checkpoint *Checkpoint
+}
+
+// MARK: String methods
+
+func (p PanicPolicy) String() string {
+	switch p {
+	case PanicPolicyNone:
+		return "none"
+	case PanicPolicyAbort:
+		return "abort"
+	case PanicPolicyContinue:
+		return "continue"
+	default:
+		return "unknown"
+	}
+}
+
+// MARK: Public methods
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("genetics: the %s callback panicked in generation %d: %v", e.Callback, e.Generation, e.Value)
+}
+
+// MARK: Public functions
+
+// ParsePanicPolicy returns the panic policy with the given name. Valid names
+// are "none", "abort" and "continue". An empty name is the none policy.
+func ParsePanicPolicy(name string) (PanicPolicy, error) {
+	switch strings.ToLower(name) {
+	case "", "none":
+		return PanicPolicyNone, nil
+	case "abort":
+		return PanicPolicyAbort, nil
+	case "continue":
+		return PanicPolicyContinue, nil
+	default:
+		return PanicPolicyNone, fmt.Errorf("unknown panic policy %q", name)
+	}
+}
+
+// MARK: Private methods
+
+// recoverEvaluation handles the recovered value of a panic in the fitness
+// function while evaluating the chromosome, and returns the error that the
+// evaluation failed with, if any.
+func (e Evolver) recoverEvaluation(value interface{}, chromosome *Chromosome, state *EvolutionState) error {
+	chromosome.panicked = true
+	panicErr := newPanicError(value, "fitness", state)
+	panicErr.Chromosome = chromosome.Clone()
+
+	if e.Configuration.PanicPolicy == PanicPolicyContinue {
+		log.Errorf("Recovered from a panic: %s", panicErr)
+		return nil
+	}
+
+	if state.panic == nil {
+		panicErr.Checkpoint = newPanicCheckpoint(state.Population, state.Generation, state)
+		state.panic = panicErr
+	}
+	return state.panic
+}
+
+// recoverBreeding handles the recovered value of a panic while breeding a child
+// from the population, and returns a copy of a parent in place of the child.
+func (e Evolver) recoverBreeding(value interface{}, population Population, state *EvolutionState) *Chromosome {
+	panicErr := newPanicError(value, state.callback, state)
+
+	if e.Configuration.PanicPolicy == PanicPolicyContinue {
+		log.Errorf("Recovered from a panic: %s", panicErr)
+	} else if state.panic == nil {
+		panicErr.Checkpoint = newPanicCheckpoint(population, state.Generation-1, state)
+		state.panic = panicErr
+	}
+	return e.copyChromosome(population, fallbackParent(population, state))
+}
+
+// MARK: Private functions
+
+// fallbackParent returns a selected parent of the population to copy in place
+// of a child whose breeding panicked. If the panic was raised by selection, or
+// selection panics again, then a uniformly chosen chromosome is returned
+// instead so that the panic isn't raised outside of the recovery.
+func fallbackParent(population Population, state *EvolutionState) (parent *Chromosome) {
+	parent = population[state.source().Intn(len(population))]
+	if state.callback == "selection" {
+		return parent
+	}
+
+	defer func() {
+		recover()
+	}()
+	return state.selectParent()
+}
+
+// newPanicError counts the recovered panic in the state and returns an error
+// describing its value.
+func newPanicError(value interface{}, callback string, state *EvolutionState) *PanicError {
+	state.Panics++
+	return &PanicError{
+		Callback:   callback,
+		Value:      value,
+		Stack:      debug.Stack(),
+		Generation: state.Generation,
+	}
+}
+
+// newPanicCheckpoint returns a checkpoint containing copies of the chromosomes
+// of the population in the given generation.
+func newPanicCheckpoint(population Population, generation int, state *EvolutionState) *Checkpoint {
+	checkpoint := &Checkpoint{
+		Generation:   generation,
+		Population:   make(Population, len(population)),
+		Random:       currentRandomSource(),
+		MutationRate: state.MutationRate,
+	}
+	for i, c := range population {
+		checkpoint.Population[i] = c.Clone()
+	}
+	return checkpoint
+}
@@ -0,0 +1,61 @@
+package genetics
+
+import (
+	"errors"
+	"testing"
+)
+
+// panickingSelectionEvolver returns an evolver whose selection always panics.
+func panickingSelectionEvolver(policy PanicPolicy) *Evolver {
+	selection := NewCustomSelectionMethod(func(population Population, context SelectionContext) *Chromosome {
+		panic("selection failed")
+	})
+
+	configuration := NewEvolverConfiguration(selection, NewCrossoverMethod(CrossoverMethodTypeUniform, CrossoverOptions{}), NewMutationMethod(MutationMethodTypeGaussian, 0.1), 1, 0.8, 0.1)
+	configuration.PanicPolicy = policy
+
+	return NewEvolver(configuration, func(c *Chromosome, state *EvolutionState) float64 {
+		return -c.Genes[0] * c.Genes[0]
+	})
+}
+
+func TestPanicPolicyContinueRecoversSelection(t *testing.T) {
+	population := GeneratePopulation(10, 3, func(i, j int) float64 {
+		return float64(i + j)
+	})
+
+	panics := 0
+	generations := 0
+	_, best, err := panickingSelectionEvolver(PanicPolicyContinue).Evolve(population, func(state *EvolutionState) bool {
+		panics = state.Panics
+		generations++
+		return generations < 3
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if best == nil {
+		t.Fatal("no best chromosome")
+	}
+	if panics == 0 {
+		t.Error("no panics were recovered")
+	}
+}
+
+func TestPanicPolicyAbortRecoversSelection(t *testing.T) {
+	population := GeneratePopulation(10, 3, func(i, j int) float64 {
+		return float64(i + j)
+	})
+
+	_, _, err := panickingSelectionEvolver(PanicPolicyAbort).Evolve(population, func(state *EvolutionState) bool {
+		return true
+	})
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("error %v isn't a panic error", err)
+	}
+	if panicErr.Callback != "selection" {
+		t.Errorf("panic raised by %q, expected selection", panicErr.Callback)
+	}
+}
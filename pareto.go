@@ -0,0 +1,346 @@
+package genetics
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// MultiObjectiveFunction defines a fitness function that returns the value of
+// each of several objectives, all of which are maximized.
+type MultiObjectiveFunction func(chromosome *Chromosome, state *EvolutionState) []float64
+
+// ConstraintFunction defines a function that returns the total violation of a
+// chromosome's constraints, which is zero if the chromosome is feasible. It's
+// called after the chromosome's objectives are recorded.
+type ConstraintFunction func(chromosome *Chromosome, state *EvolutionState) float64
+
+// MARK: Public methods
+
+// ParetoFront returns the chromosomes of the population that aren't
+// constraint-dominated by any other chromosome, in the population's order.
+// Without constraint violations, this is the Pareto front of their objectives.
+func (p Population) ParetoFront() Population {
+	var front Population
+	for i, c := range p {
+		dominated := false
+		for j, other := range p {
+			if i != j && ConstraintDominates(other, c) {
+				dominated = true
+				break
+			}
+		}
+
+		if !dominated {
+			front = append(front, c)
+		}
+	}
+	return front
+}
+
+// MARK: Public functions
+
+// NewMultiObjectiveFitnessFunction returns a fitness function that records the
+// objectives of each chromosome in its `Objectives` and returns their weighted
+// sum as its fitness. If `weights` is shorter than the objectives, then the
+// missing weights are one.
+func NewMultiObjectiveFitnessFunction(f MultiObjectiveFunction, weights []float64) FitnessFunction {
+	return func(chromosome *Chromosome, state *EvolutionState) float64 {
+		chromosome.Objectives = append(chromosome.Objectives[:0], f(chromosome, state)...)
+
+		fitness := 0.0
+		for i, o := range chromosome.Objectives {
+			w := 1.0
+			if i < len(weights) {
+				w = weights[i]
+			}
+			fitness += w * o
+		}
+		return fitness
+	}
+}
+
+// Dominates returns whether or not the objectives `a` Pareto dominate the
+// objectives `b`. That is, no objective of `a` is less than the objective of
+// `b`, and at least one is greater.
+func Dominates(a, b []float64) bool {
+	better := false
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] < b[i] {
+			return false
+		}
+		if a[i] > b[i] {
+			better = true
+		}
+	}
+	return better
+}
+
+// ConstraintDominates returns whether or not chromosome `a` constraint-
+// dominates chromosome `b`. A feasible chromosome dominates an infeasible one,
+// an infeasible chromosome dominates another with a greater violation, and a
+// feasible chromosome dominates another if its objectives Pareto dominate the
+// other's.
+func ConstraintDominates(a, b *Chromosome) bool {
+	switch {
+	case a.Violation <= 0.0 && b.Violation <= 0.0:
+		return Dominates(a.Objectives, b.Objectives)
+	case a.Violation <= 0.0:
+		return true
+	case b.Violation <= 0.0:
+		return false
+	default:
+		return a.Violation < b.Violation
+	}
+}
+
+// NewEpsilonConstraintFitnessFunction returns a fitness function for the
+// epsilon-constraint method, which solves a multi-objective problem as a
+// single-objective problem. Fitness is the value of the objective at index
+// `objective`, while every other objective `i` is constrained to be at least
+// `epsilons[i]`. Each chromosome's violation is the total shortfall of the
+// constrained objectives plus any violation returned by the optional
+// constraint function, and is subtracted from fitness after being multiplied
+// by `penalty`. Solving for a range of epsilons traces out the Pareto front.
+func NewEpsilonConstraintFitnessFunction(f MultiObjectiveFunction, constraint ConstraintFunction, objective int, epsilons []float64, penalty float64) FitnessFunction {
+	return func(chromosome *Chromosome, state *EvolutionState) float64 {
+		chromosome.Objectives = append(chromosome.Objectives[:0], f(chromosome, state)...)
+		chromosome.Violation = epsilonViolation(chromosome.Objectives, objective, epsilons)
+		if constraint != nil {
+			chromosome.Violation += constraint(chromosome, state)
+		}
+
+		fitness := 0.0
+		if objective < len(chromosome.Objectives) {
+			fitness = chromosome.Objectives[objective]
+		}
+		return fitness - penalty*chromosome.Violation
+	}
+}
+
+// Hypervolume returns the volume of objective space dominated by the
+// objectives of the chromosomes and bounded by the reference point, which
+// should be dominated by every chromosome. Objectives that don't dominate the
+// reference point are ignored. Larger hypervolumes indicate fronts that are
+// closer to the optimum and more widely spread.
+func Hypervolume(front Population, reference []float64) float64 {
+	var points [][]float64
+	for _, c := range front {
+		if len(c.Objectives) >= len(reference) && Dominates(c.Objectives[:len(reference)], reference) {
+			points = append(points, c.Objectives[:len(reference)])
+		}
+	}
+	return hypervolume(points, reference)
+}
+
+// InvertedGenerationalDistance returns the mean distance from each point of a
+// reference front, such as samples of the true Pareto front, to the nearest
+// objectives of the chromosomes. Smaller distances indicate fronts that are
+// closer to, and cover more of, the reference front.
+func InvertedGenerationalDistance(front Population, reference [][]float64) float64 {
+	if len(reference) == 0 || len(front) == 0 {
+		return math.Inf(1)
+	}
+
+	sum := 0.0
+	for _, r := range reference {
+		nearest := math.Inf(1)
+		for _, c := range front {
+			if len(c.Objectives) == len(r) {
+				nearest = math.Min(nearest, floats.Distance(c.Objectives, r, 2.0))
+			}
+		}
+		sum += nearest
+	}
+	return sum / float64(len(reference))
+}
+
+// WriteParetoFrontCSV writes the objectives and genes of the chromosomes as
+// CSV with a header row.
+func WriteParetoFrontCSV(w io.Writer, front Population) error {
+	writer := csv.NewWriter(w)
+	if len(front) > 0 {
+		header := []string{"id"}
+		for i := range front[0].Objectives {
+			header = append(header, "objective_"+strconv.Itoa(i))
+		}
+		for i := range front[0].Genes {
+			header = append(header, "gene_"+strconv.Itoa(i))
+		}
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range front {
+		row := []string{strconv.FormatUint(c.ID, 10)}
+		for _, o := range c.Objectives {
+			row = append(row, strconv.FormatFloat(o, 'g', -1, 64))
+		}
+		for _, g := range c.Genes {
+			row = append(row, strconv.FormatFloat(g, 'g', -1, 64))
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteParetoFrontJSON writes the chromosomes as a JSON array.
+func WriteParetoFrontJSON(w io.Writer, front Population) error {
+	if front == nil {
+		front = Population{}
+	}
+
+	if err := json.NewEncoder(w).Encode(front); err != nil {
+		return fmt.Errorf("unable to encode the Pareto front: %w", err)
+	}
+	return nil
+}
+
+// MARK: Private functions
+
+// epsilonViolation returns the total amount by which the objectives, other
+// than the objective at index `objective`, fall short of their epsilons.
+func epsilonViolation(objectives []float64, objective int, epsilons []float64) float64 {
+	violation := 0.0
+	for i := 0; i < len(objectives) && i < len(epsilons); i++ {
+		if i != objective {
+			violation += math.Max(0.0, epsilons[i]-objectives[i])
+		}
+	}
+	return violation
+}
+
+// nondominatedFronts sorts the population in to fronts of chromosomes that
+// aren't constraint-dominated by each other, and returns the indexes of the
+// chromosomes of each front, best front first.
+func nondominatedFronts(population Population) [][]int {
+	dominatedBy := make([][]int, len(population))
+	counts := make([]int, len(population))
+	var fronts [][]int
+	var front []int
+	for i := range population {
+		for j := range population {
+			if i == j {
+				continue
+			}
+
+			if ConstraintDominates(population[i], population[j]) {
+				dominatedBy[i] = append(dominatedBy[i], j)
+			} else if ConstraintDominates(population[j], population[i]) {
+				counts[i]++
+			}
+		}
+
+		if counts[i] == 0 {
+			front = append(front, i)
+		}
+	}
+
+	for len(front) > 0 {
+		fronts = append(fronts, front)
+		var next []int
+		for _, i := range front {
+			for _, j := range dominatedBy[i] {
+				if counts[j]--; counts[j] == 0 {
+					next = append(next, j)
+				}
+			}
+		}
+		front = next
+	}
+	return fronts
+}
+
+// crowdingDistances returns the crowding distance of each chromosome of a
+// front, which is the sum over the objectives of the normalized distance
+// between the chromosome's neighbours. Chromosomes at the boundary of an
+// objective have an infinite distance.
+func crowdingDistances(front Population) []float64 {
+	distances := make([]float64, len(front))
+	if len(front) == 0 {
+		return distances
+	}
+
+	order := make([]int, len(front))
+	for m := range front[0].Objectives {
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			return objective(front[order[i]], m) < objective(front[order[j]], m)
+		})
+
+		min := objective(front[order[0]], m)
+		max := objective(front[order[len(order)-1]], m)
+		distances[order[0]] = math.Inf(1)
+		distances[order[len(order)-1]] = math.Inf(1)
+		if max == min {
+			continue
+		}
+
+		for i := 1; i < len(order)-1; i++ {
+			distances[order[i]] += (objective(front[order[i+1]], m) - objective(front[order[i-1]], m)) / (max - min)
+		}
+	}
+	return distances
+}
+
+// objective returns the chromosome's objective at index `m`, or zero if it
+// doesn't have one.
+func objective(c *Chromosome, m int) float64 {
+	if m < len(c.Objectives) {
+		return c.Objectives[m]
+	}
+	return 0.0
+}
+
+// hypervolume returns the hypervolume of the points, all of which dominate the
+// reference point, by slicing objective space along its last dimension.
+func hypervolume(points [][]float64, reference []float64) float64 {
+	d := len(reference)
+	if len(points) == 0 || d == 0 {
+		return 0.0
+	}
+
+	if d == 1 {
+		max := reference[0]
+		for _, p := range points {
+			max = math.Max(max, p[0])
+		}
+		return max - reference[0]
+	}
+
+	sorted := make([][]float64, len(points))
+	copy(sorted, points)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i][d-1] > sorted[j][d-1]
+	})
+
+	volume := 0.0
+	for i := range sorted {
+		next := reference[d-1]
+		if i+1 < len(sorted) {
+			next = sorted[i+1][d-1]
+		}
+
+		if depth := sorted[i][d-1] - next; depth > 0.0 {
+			slice := make([][]float64, i+1)
+			for j, p := range sorted[:i+1] {
+				slice[j] = p[:d-1]
+			}
+			volume += hypervolume(slice, reference[:d-1]) * depth
+		}
+	}
+	return volume
+}
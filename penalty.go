@@ -0,0 +1,170 @@
+package genetics
+
+import (
+	"math"
+	"sync"
+)
+
+// AdaptivePenalty objects penalize the fitness of infeasible chromosomes with
+// a coefficient that adapts to the progress of the evolution. The coefficient
+// decreases when the fittest chromosome of each of the last few generations
+// was feasible, since the penalty may be keeping the evolution from exploring
+// the boundary of the feasible region, and increases when each was infeasible.
+//
+// As with the package's other penalized fitness functions, evolvers update the
+// penalties of chromosomes that survive to later generations without being
+// evaluated again, so that elites aren't judged by out of date penalties.
+type AdaptivePenalty struct {
+	// The current coefficient that violations are multiplied by.
+	Coefficient float64
+
+	// The number of consecutive generations whose fittest chromosomes must be
+	// feasible, or infeasible, for the coefficient to change.
+	Generations int
+
+	// The factors that the coefficient is divided by, or multiplied by, when it
+	// decreases or increases.
+	Decrease float64
+	Increase float64
+
+	mutex       sync.Mutex
+	generation  int
+	started     bool
+	bestFitness float64
+	feasible    bool
+	history     []bool
+}
+
+// penalizer types return the penalized fitness of a chromosome given its
+// fitness and the violation of its constraints in the generation of the state.
+type penalizer func(fitness float64, violation float64, state *EvolutionState) float64
+
+// MARK: Constructors
+
+// NewAdaptivePenalty creates and returns a new adaptive penalty with the
+// initial coefficient that changes after `generations` consecutive generations
+// whose fittest chromosomes are all feasible, or all infeasible. The
+// coefficient halves when decreasing and doubles when increasing.
+func NewAdaptivePenalty(coefficient float64, generations int) *AdaptivePenalty {
+	return &AdaptivePenalty{
+		Coefficient: coefficient,
+		Generations: generations,
+		Decrease:    2.0,
+		Increase:    2.0,
+	}
+}
+
+// MARK: Public methods
+
+// FitnessFunction returns a fitness function that records the violation of
+// each chromosome's constraints in its `Violation`, and returns its fitness
+// less the violation multiplied by the penalty's current coefficient.
+func (p *AdaptivePenalty) FitnessFunction(f FitnessFunction, constraint ConstraintFunction) FitnessFunction {
+	return penalize(f, constraint, func(fitness float64, violation float64, state *EvolutionState) float64 {
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+
+		p.advance(state.Generation)
+		penalized := fitness - p.Coefficient*violation
+		if !p.started || penalized > p.bestFitness {
+			p.started = true
+			p.bestFitness = penalized
+			p.feasible = violation <= 0.0
+		}
+		return penalized
+	})
+}
+
+// MARK: Public functions
+
+// NewStaticPenaltyFitnessFunction returns a fitness function that records the
+// violation of each chromosome's constraints in its `Violation`, and returns
+// its fitness less the violation multiplied by the coefficient.
+func NewStaticPenaltyFitnessFunction(f FitnessFunction, constraint ConstraintFunction, coefficient float64) FitnessFunction {
+	return penalize(f, constraint, func(fitness float64, violation float64, state *EvolutionState) float64 {
+		return fitness - coefficient*violation
+	})
+}
+
+// NewDynamicPenaltyFitnessFunction returns a fitness function that records the
+// violation of each chromosome's constraints in its `Violation`, and returns
+// its fitness less the violation multiplied by `(coefficient * t)^exponent`,
+// where `t` is the generation, starting from one. The penalty grows as the
+// evolution progresses, so infeasible chromosomes are explored early and
+// eliminated late. An exponent of two is typical. The penalties of elites are
+// updated each generation that they survive.
+func NewDynamicPenaltyFitnessFunction(f FitnessFunction, constraint ConstraintFunction, coefficient float64, exponent float64) FitnessFunction {
+	return penalize(f, constraint, func(fitness float64, violation float64, state *EvolutionState) float64 {
+		if violation <= 0.0 {
+			return fitness
+		}
+		return fitness - math.Pow(coefficient*float64(state.Generation+1), exponent)*violation
+	})
+}
+
+// NewConstraintFunction returns a constraint function that sums the violations
+// of inequality constraints, `g(x) <= 0`, and equality constraints,
+// `h(x) = 0`. Inequality constraints are violated by their positive values and
+// equality constraints by the amount that their absolute values exceed the
+// tolerance.
+func NewConstraintFunction(inequalities []ConstraintFunction, equalities []ConstraintFunction, tolerance float64) ConstraintFunction {
+	return func(chromosome *Chromosome, state *EvolutionState) float64 {
+		violation := 0.0
+		for _, g := range inequalities {
+			violation += math.Max(0.0, g(chromosome, state))
+		}
+		for _, h := range equalities {
+			violation += math.Max(0.0, math.Abs(h(chromosome, state))-tolerance)
+		}
+		return violation
+	}
+}
+
+// MARK: Private methods
+
+// advance updates the coefficient once evaluations of a new generation begin.
+func (p *AdaptivePenalty) advance(generation int) {
+	if !p.started || generation == p.generation {
+		p.generation = generation
+		return
+	}
+
+	p.history = append(p.history, p.feasible)
+	p.generation = generation
+	p.started = false
+
+	if p.Generations <= 0 || len(p.history) < p.Generations {
+		return
+	}
+	p.history = p.history[len(p.history)-p.Generations:]
+
+	feasible, infeasible := true, true
+	for _, f := range p.history {
+		feasible = feasible && f
+		infeasible = infeasible && !f
+	}
+
+	switch {
+	case feasible && p.Decrease > 0.0:
+		p.Coefficient /= p.Decrease
+		p.history = p.history[:0]
+	case infeasible:
+		p.Coefficient *= p.Increase
+		p.history = p.history[:0]
+	}
+}
+
+// MARK: Private functions
+
+// penalize returns a fitness function that records the violation of each
+// chromosome's constraints in its `Violation`, along with its fitness and the
+// penalizer, and returns its penalized fitness. Negative violations are zero.
+func penalize(f FitnessFunction, constraint ConstraintFunction, p penalizer) FitnessFunction {
+	return func(chromosome *Chromosome, state *EvolutionState) float64 {
+		fitness := f(chromosome, state)
+		chromosome.Violation = math.Max(0.0, constraint(chromosome, state))
+		chromosome.unpenalized = fitness
+		chromosome.penalizer = p
+		return p(fitness, chromosome.Violation, state)
+	}
+}
@@ -0,0 +1,184 @@
+// Package plot renders charts of the statistics collected while evolving a
+// population as SVG or PNG images. PNG charts are rasterized with the standard
+// library and label their axes with a small built-in bitmap font, so the
+// package has no dependencies beyond genetics.
+package plot
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+
+	genetics "github.com/colinc86/go-genetics"
+)
+
+// Dimensions of rendered charts.
+const (
+	width   = 640.0
+	height  = 400.0
+	padding = 60.0
+	ticks   = 5
+)
+
+// series types are a named line on a chart.
+type series struct {
+	name   string
+	color  string
+	values []float64
+}
+
+// chart types are line charts of series over the generations of a population's
+// statistics, scaled to the chart's dimensions.
+type chart struct {
+	title       string
+	generations []float64
+	lines       []series
+
+	minX, maxX float64
+	minY, maxY float64
+}
+
+// MARK: Public functions
+
+// FitnessSVG writes an SVG chart of the best, mean and worst fitness of each
+// generation to the writer.
+func FitnessSVG(w io.Writer, stats genetics.EvolutionStats) error {
+	return newFitnessChart(stats).writeSVG(w)
+}
+
+// FitnessPNG writes a PNG chart of the best, mean and worst fitness of each
+// generation to the writer.
+func FitnessPNG(w io.Writer, stats genetics.EvolutionStats) error {
+	return newFitnessChart(stats).writePNG(w)
+}
+
+// DiversitySVG writes an SVG chart of the diversity of each generation to the
+// writer.
+func DiversitySVG(w io.Writer, stats genetics.EvolutionStats) error {
+	return newDiversityChart(stats).writeSVG(w)
+}
+
+// DiversityPNG writes a PNG chart of the diversity of each generation to the
+// writer.
+func DiversityPNG(w io.Writer, stats genetics.EvolutionStats) error {
+	return newDiversityChart(stats).writePNG(w)
+}
+
+// MARK: Private methods
+
+// x returns the horizontal position of the generation on the chart.
+func (c chart) x(v float64) float64 {
+	return padding + (v-c.minX)/(c.maxX-c.minX)*(width-2.0*padding)
+}
+
+// y returns the vertical position of the value on the chart.
+func (c chart) y(v float64) float64 {
+	return height - padding - (v-c.minY)/(c.maxY-c.minY)*(height-2.0*padding)
+}
+
+// tick returns the generation and value of the ith of the chart's ticks.
+func (c chart) tick(i int) (float64, float64) {
+	return c.minX + (c.maxX-c.minX)*float64(i)/ticks, c.minY + (c.maxY-c.minY)*float64(i)/ticks
+}
+
+// writeSVG writes the chart to the writer as an SVG image.
+func (c chart) writeSVG(w io.Writer) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" font-family="sans-serif" font-size="12">`+"\n", width, height)
+	fmt.Fprintf(b, `<rect width="100%%" height="100%%" fill="white"/>`+"\n")
+	fmt.Fprintf(b, `<text x="%.1f" y="%.1f" text-anchor="middle" font-size="16">%s</text>`+"\n", width/2.0, padding/2.0, c.title)
+
+	// Axes
+	fmt.Fprintf(b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="black"/>`+"\n", padding, height-padding, width-padding, height-padding)
+	fmt.Fprintf(b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="black"/>`+"\n", padding, padding, padding, height-padding)
+	fmt.Fprintf(b, `<text x="%.1f" y="%.1f" text-anchor="middle">Generation</text>`+"\n", width/2.0, height-padding/4.0)
+
+	for i := 0; i <= ticks; i++ {
+		vx, vy := c.tick(i)
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f" text-anchor="middle">%.4g</text>`+"\n", c.x(vx), height-padding+16.0, vx)
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f" text-anchor="end">%.4g</text>`+"\n", padding-6.0, c.y(vy)+4.0, vy)
+	}
+
+	// Series
+	for i, l := range c.lines {
+		fmt.Fprintf(b, `<polyline fill="none" stroke="%s" stroke-width="1.5" points="`, l.color)
+		for j, v := range l.values {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				continue
+			}
+			fmt.Fprintf(b, "%.2f,%.2f ", c.x(c.generations[j]), c.y(v))
+		}
+		fmt.Fprintf(b, `"/>`+"\n")
+
+		ly := padding + float64(i)*16.0
+		fmt.Fprintf(b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="2"/>`+"\n", width-padding-80.0, ly, width-padding-60.0, ly, l.color)
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f">%s</text>`+"\n", width-padding-55.0, ly+4.0, l.name)
+	}
+
+	fmt.Fprintf(b, "</svg>\n")
+	return b.Flush()
+}
+
+// MARK: Private functions
+
+// newFitnessChart returns a chart of the best, mean and worst fitness of each
+// generation.
+func newFitnessChart(stats genetics.EvolutionStats) chart {
+	best := series{name: "Best", color: "#2ca02c"}
+	mean := series{name: "Mean", color: "#1f77b4"}
+	worst := series{name: "Worst", color: "#d62728"}
+	for _, s := range stats {
+		best.values = append(best.values, s.Best)
+		mean.values = append(mean.values, s.Mean)
+		worst.values = append(worst.values, s.Worst)
+	}
+
+	return newChart("Fitness", stats, []series{best, mean, worst})
+}
+
+// newDiversityChart returns a chart of the diversity of each generation.
+func newDiversityChart(stats genetics.EvolutionStats) chart {
+	diversity := series{name: "Diversity", color: "#9467bd"}
+	for _, s := range stats {
+		diversity.values = append(diversity.values, s.Diversity)
+	}
+
+	return newChart("Diversity", stats, []series{diversity})
+}
+
+// newChart returns a line chart of the series over the generations of the
+// statistics.
+func newChart(title string, stats genetics.EvolutionStats, lines []series) chart {
+	c := chart{title: title, lines: lines}
+	for _, s := range stats {
+		c.generations = append(c.generations, float64(s.Generation))
+	}
+
+	c.minX, c.maxX = 0.0, 1.0
+	if len(stats) > 0 {
+		c.minX = c.generations[0]
+		c.maxX = c.generations[len(c.generations)-1]
+	}
+
+	c.minY, c.maxY = math.MaxFloat64, -math.MaxFloat64
+	for _, l := range lines {
+		for _, v := range l.values {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				continue
+			}
+			c.minY = math.Min(c.minY, v)
+			c.maxY = math.Max(c.maxY, v)
+		}
+	}
+	if c.minY > c.maxY {
+		c.minY, c.maxY = 0.0, 1.0
+	}
+	if c.minX == c.maxX {
+		c.maxX = c.minX + 1.0
+	}
+	if c.minY == c.maxY {
+		c.minY, c.maxY = c.minY-0.5, c.maxY+0.5
+	}
+	return c
+}
@@ -0,0 +1,98 @@
+package plot
+
+import (
+	"bytes"
+	"image/png"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	genetics "github.com/colinc86/go-genetics"
+)
+
+// testStats returns the statistics of a few generations, including one with a
+// NaN mean.
+func testStats() genetics.EvolutionStats {
+	stats := genetics.EvolutionStats{}
+	for i := 0; i < 10; i++ {
+		stats = append(stats, genetics.GenerationStats{
+			Generation: i + 1,
+			Best:       float64(i),
+			Mean:       float64(i) / 2.0,
+			Worst:      -float64(i),
+			Diversity:  1.0 / float64(i+1),
+			Elapsed:    time.Millisecond,
+		})
+	}
+	stats[3].Mean = math.NaN()
+	return stats
+}
+
+func TestFitnessPNG(t *testing.T) {
+	b := &bytes.Buffer{}
+	if err := FitnessPNG(b, testStats()); err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := img.Bounds().Size(); size.X != width || size.Y != height {
+		t.Fatalf("rendered a %dx%d image, expected %.0fx%.0f", size.X, size.Y, width, height)
+	}
+
+	// Each series' line and legend is drawn in its color.
+	for _, s := range newFitnessChart(testStats()).lines {
+		expected := parseColor(s.color)
+		found := false
+		for y := 0; y < height && !found; y++ {
+			for x := 0; x < width && !found; x++ {
+				r, g, b, _ := img.At(x, y).RGBA()
+				found = uint8(r>>8) == expected.R && uint8(g>>8) == expected.G && uint8(b>>8) == expected.B
+			}
+		}
+		if !found {
+			t.Errorf("the %s series isn't drawn", s.name)
+		}
+	}
+}
+
+func TestDiversityPNGEmptyStats(t *testing.T) {
+	b := &bytes.Buffer{}
+	if err := DiversityPNG(b, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := png.Decode(b); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFitnessSVG(t *testing.T) {
+	b := &bytes.Buffer{}
+	if err := FitnessSVG(b, testStats()); err != nil {
+		t.Fatal(err)
+	}
+
+	svg := b.String()
+	if !strings.HasPrefix(svg, "<svg") || !strings.HasSuffix(svg, "</svg>\n") {
+		t.Fatal("didn't write an SVG document")
+	}
+	if strings.Contains(svg, "NaN") {
+		t.Error("wrote a NaN point")
+	}
+	if n := strings.Count(svg, "<polyline"); n != 3 {
+		t.Errorf("drew %d series, expected 3", n)
+	}
+}
+
+func TestDrawTextGlyphs(t *testing.T) {
+	for _, text := range []string{"Fitness", "Diversity", "Generation", "Best", "Mean", "Worst", formatTick(-1.5e-07)} {
+		for _, r := range strings.ToUpper(text) {
+			if _, ok := glyphs[r]; !ok {
+				t.Errorf("the font doesn't have %q of %q", r, text)
+			}
+		}
+	}
+}
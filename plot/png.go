@@ -0,0 +1,188 @@
+package plot
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Dimensions of the bitmap font, in pixels before scaling.
+const (
+	glyphWidth   = 3
+	glyphHeight  = 5
+	glyphAdvance = glyphWidth + 1
+)
+
+// textAlignment represents the horizontal alignment of text relative to its
+// position.
+type textAlignment int
+
+// Text alignments.
+const (
+	textAlignmentStart  textAlignment = 0
+	textAlignmentMiddle textAlignment = 1
+	textAlignmentEnd    textAlignment = 2
+)
+
+// glyphs contains the rows of each character of the bitmap font from top to
+// bottom, with the leftmost pixel of each row in its most significant bit.
+// Lowercase letters are drawn as uppercase.
+var glyphs = map[rune][glyphHeight]uint8{
+	'0': {0b111, 0b101, 0b101, 0b101, 0b111},
+	'1': {0b010, 0b110, 0b010, 0b010, 0b111},
+	'2': {0b111, 0b001, 0b111, 0b100, 0b111},
+	'3': {0b111, 0b001, 0b111, 0b001, 0b111},
+	'4': {0b101, 0b101, 0b111, 0b001, 0b001},
+	'5': {0b111, 0b100, 0b111, 0b001, 0b111},
+	'6': {0b111, 0b100, 0b111, 0b101, 0b111},
+	'7': {0b111, 0b001, 0b001, 0b001, 0b001},
+	'8': {0b111, 0b101, 0b111, 0b101, 0b111},
+	'9': {0b111, 0b101, 0b111, 0b001, 0b111},
+	'A': {0b010, 0b101, 0b111, 0b101, 0b101},
+	'B': {0b110, 0b101, 0b110, 0b101, 0b110},
+	'C': {0b011, 0b100, 0b100, 0b100, 0b011},
+	'D': {0b110, 0b101, 0b101, 0b101, 0b110},
+	'E': {0b111, 0b100, 0b110, 0b100, 0b111},
+	'F': {0b111, 0b100, 0b110, 0b100, 0b100},
+	'G': {0b011, 0b100, 0b101, 0b101, 0b011},
+	'H': {0b101, 0b101, 0b111, 0b101, 0b101},
+	'I': {0b111, 0b010, 0b010, 0b010, 0b111},
+	'J': {0b001, 0b001, 0b001, 0b101, 0b010},
+	'K': {0b101, 0b101, 0b110, 0b101, 0b101},
+	'L': {0b100, 0b100, 0b100, 0b100, 0b111},
+	'M': {0b101, 0b111, 0b111, 0b101, 0b101},
+	'N': {0b110, 0b101, 0b101, 0b101, 0b101},
+	'O': {0b010, 0b101, 0b101, 0b101, 0b010},
+	'P': {0b110, 0b101, 0b110, 0b100, 0b100},
+	'Q': {0b010, 0b101, 0b101, 0b110, 0b011},
+	'R': {0b110, 0b101, 0b110, 0b101, 0b101},
+	'S': {0b011, 0b100, 0b010, 0b001, 0b110},
+	'T': {0b111, 0b010, 0b010, 0b010, 0b010},
+	'U': {0b101, 0b101, 0b101, 0b101, 0b111},
+	'V': {0b101, 0b101, 0b101, 0b101, 0b010},
+	'W': {0b101, 0b101, 0b111, 0b111, 0b101},
+	'X': {0b101, 0b101, 0b010, 0b101, 0b101},
+	'Y': {0b101, 0b101, 0b010, 0b010, 0b010},
+	'Z': {0b111, 0b001, 0b010, 0b100, 0b111},
+	'.': {0b000, 0b000, 0b000, 0b000, 0b010},
+	'-': {0b000, 0b000, 0b111, 0b000, 0b000},
+	'+': {0b000, 0b010, 0b111, 0b010, 0b000},
+}
+
+// MARK: Private methods
+
+// writePNG writes the chart to the writer as a PNG image.
+func (c chart) writePNG(w io.Writer) error {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+
+	black := color.RGBA{A: 0xff}
+	drawText(img, width/2.0, padding/2.0, c.title, textAlignmentMiddle, 3, black)
+
+	// Axes
+	drawLine(img, padding, height-padding, width-padding, height-padding, 1, black)
+	drawLine(img, padding, padding, padding, height-padding, 1, black)
+	drawText(img, width/2.0, height-padding/4.0, "Generation", textAlignmentMiddle, 2, black)
+
+	for i := 0; i <= ticks; i++ {
+		vx, vy := c.tick(i)
+		drawText(img, c.x(vx), height-padding+16.0, formatTick(vx), textAlignmentMiddle, 2, black)
+		drawText(img, padding-6.0, c.y(vy)+4.0, formatTick(vy), textAlignmentEnd, 2, black)
+	}
+
+	// Series
+	for i, l := range c.lines {
+		stroke := parseColor(l.color)
+		previous := -1
+		for j, v := range l.values {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				continue
+			}
+			if previous >= 0 {
+				drawLine(img, c.x(c.generations[previous]), c.y(l.values[previous]), c.x(c.generations[j]), c.y(v), 2, stroke)
+			}
+			previous = j
+		}
+
+		ly := padding + float64(i)*16.0
+		drawLine(img, width-padding-80.0, ly, width-padding-60.0, ly, 2, stroke)
+		drawText(img, width-padding-55.0, ly+4.0, l.name, textAlignmentStart, 2, black)
+	}
+
+	return png.Encode(w, img)
+}
+
+// MARK: Private functions
+
+// drawLine draws a line between the points with the given thickness in pixels.
+func drawLine(img *image.RGBA, x0, y0, x1, y1 float64, thickness int, c color.RGBA) {
+	steps := int(math.Ceil(math.Max(math.Abs(x1-x0), math.Abs(y1-y0))))
+	for i := 0; i <= steps; i++ {
+		t := 0.0
+		if steps > 0 {
+			t = float64(i) / float64(steps)
+		}
+
+		x := int(math.Round(x0 + (x1-x0)*t))
+		y := int(math.Round(y0 + (y1-y0)*t))
+		fillRect(img, x-thickness/2, y-thickness/2, thickness, thickness, c)
+	}
+}
+
+// drawText draws the text with its baseline at y using the bitmap font scaled
+// by the given factor. Characters that the font doesn't have are drawn as
+// spaces.
+func drawText(img *image.RGBA, x, y float64, text string, alignment textAlignment, scale int, c color.RGBA) {
+	text = strings.ToUpper(text)
+	textWidth := float64((len(text)*glyphAdvance - 1) * scale)
+	switch alignment {
+	case textAlignmentMiddle:
+		x -= textWidth / 2.0
+	case textAlignmentEnd:
+		x -= textWidth
+	}
+
+	left := int(math.Round(x))
+	top := int(math.Round(y)) - glyphHeight*scale
+	for i, r := range text {
+		glyph := glyphs[r]
+		for row, bits := range glyph {
+			for column := 0; column < glyphWidth; column++ {
+				if bits&(1<<uint(glyphWidth-1-column)) != 0 {
+					fillRect(img, left+(i*glyphAdvance+column)*scale, top+row*scale, scale, scale, c)
+				}
+			}
+		}
+	}
+}
+
+// fillRect fills the rectangle with the color, clipped to the image's bounds.
+func fillRect(img *image.RGBA, x, y, w, h int, c color.RGBA) {
+	r := image.Rect(x, y, x+w, y+h).Intersect(img.Bounds())
+	for py := r.Min.Y; py < r.Max.Y; py++ {
+		for px := r.Min.X; px < r.Max.X; px++ {
+			img.SetRGBA(px, py, c)
+		}
+	}
+}
+
+// formatTick returns the label of a tick, formatted as it is on SVG charts.
+func formatTick(v float64) string {
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
+
+// parseColor returns the color of a "#rrggbb" hexadecimal string, or black if
+// the string is invalid.
+func parseColor(s string) color.RGBA {
+	value, err := strconv.ParseUint(strings.TrimPrefix(s, "#"), 16, 32)
+	if err != nil {
+		return color.RGBA{A: 0xff}
+	}
+	return color.RGBA{R: uint8(value >> 16), G: uint8(value >> 8), B: uint8(value), A: 0xff}
+}
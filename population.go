@@ -2,19 +2,37 @@ package genetics
 
 import (
 	"math"
-	"math/rand"
+	"sort"
+	"sync"
+
+	"gonum.org/v1/gonum/floats"
 )
 
 // Population types are an array of chromosomes.
 type Population []*Chromosome
 
+// populationSorter types sort a population in ascending order of a comparator,
+// or of fitness if the comparator is nil.
+type populationSorter struct {
+	population Population
+	comparator Comparator
+}
+
+// sorterPool contains population sorters so that populations can be sorted
+// without allocating.
+var sorterPool = sync.Pool{
+	New: func() interface{} {
+		return &populationSorter{}
+	},
+}
+
 // MARK: Global methods
 
 // GeneratePopulation generates a new population of chromosomes.
 func GeneratePopulation(populationSize uint, chromosomeLength uint, generatingFunction func(i, j int) float64) Population {
 	var population Population
 	for i := 0; i < int(populationSize); i++ {
-		chromosome := &Chromosome{}
+		chromosome := &Chromosome{ID: nextChromosomeID()}
 		for j := 0; j < int(chromosomeLength); j++ {
 			chromosome.Genes = append(chromosome.Genes, generatingFunction(i, j))
 		}
@@ -23,8 +41,228 @@ func GeneratePopulation(populationSize uint, chromosomeLength uint, generatingFu
 	return population
 }
 
+// GenerateBoundedPopulation generates a new population of chromosomes with a
+// gene for each of the given bounds. Genes are uniformly distributed on the
+// scale of their bounds.
+func GenerateBoundedPopulation(populationSize uint, bounds []GeneBounds) Population {
+	return GeneratePopulation(populationSize, uint(len(bounds)), func(i, j int) float64 {
+		return bounds[j].Random()
+	})
+}
+
 // MARK: Public methods
 
+// Seed replaces the chromosomes at the start of the population with copies of
+// the given chromosomes. Seeds beyond the size of the population are ignored.
+//
+// Seeds keep their identifiers. Seeds that have already been evaluated by an
+// evolver keep their fitness and are not evaluated again.
+func (p Population) Seed(chromosomes ...*Chromosome) {
+	for i := 0; i < len(chromosomes) && i < len(p); i++ {
+		seed := &Chromosome{
+			ID:                 chromosomes[i].ID,
+			Fitness:            chromosomes[i].Fitness,
+			Age:                chromosomes[i].Age,
+			Components:         copyComponents(chromosomes[i].Components),
+			EvaluationDuration: chromosomes[i].EvaluationDuration,
+			Objectives:         copyValues(chromosomes[i].Objectives),
+			Violation:          chromosomes[i].Violation,
+			CaseErrors:         copyValues(chromosomes[i].CaseErrors),
+			Behavior:           copyValues(chromosomes[i].Behavior),
+			Novelty:            chromosomes[i].Novelty,
+			Metadata:           copyMetadata(chromosomes[i].Metadata),
+			evaluated:          chromosomes[i].evaluated,
+		}
+		seed.Genes = make([]float64, len(chromosomes[i].Genes))
+		copy(seed.Genes, chromosomes[i].Genes)
+		p[i] = seed
+	}
+}
+
+// SortedByFitness returns a new population containing the population's
+// chromosomes in ascending order of fitness, which is the order of the
+// populations returned by evolvers. Chromosomes with equal fitness are ordered
+// deterministically by their genes. The receiver's order is not modified.
+func (p Population) SortedByFitness() Population {
+	sorted := make(Population, len(p))
+	copy(sorted, p)
+	sorted.sortByFitness()
+	return sorted
+}
+
+// TopK returns a new population containing the `k` chromosomes with the
+// highest fitness in descending order of fitness. Chromosomes with equal
+// fitness are ordered deterministically by their genes. `k` is clamped to the
+// population's size. The receiver's order is not modified.
+func (p Population) TopK(k int) Population {
+	sorted := make(Population, len(p))
+	copy(sorted, p)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].fitterThan(sorted[j])
+	})
+
+	return sorted.first(k)
+}
+
+// Quantile returns the `q` quantile, for `q` in [0, 1], of the fitness of the
+// population's chromosomes, interpolating linearly between fitnesses. NaN
+// fitness is ignored. Returns NaN if no chromosome has a fitness.
+func (p Population) Quantile(q float64) float64 {
+	fitnesses := p.validFitnesses()
+	if len(fitnesses) == 0 {
+		return math.NaN()
+	}
+
+	sort.Float64s(fitnesses)
+	q = math.Max(0.0, math.Min(1.0, q))
+	position := q * float64(len(fitnesses)-1)
+	i := int(math.Floor(position))
+	if i+1 >= len(fitnesses) {
+		return fitnesses[len(fitnesses)-1]
+	}
+	return fitnesses[i] + (position-float64(i))*(fitnesses[i+1]-fitnesses[i])
+}
+
+// MeanFitness returns the mean fitness of the population's chromosomes. NaN
+// fitness is ignored. Returns NaN if no chromosome has a fitness.
+func (p Population) MeanFitness() float64 {
+	fitnesses := p.validFitnesses()
+	if len(fitnesses) == 0 {
+		return math.NaN()
+	}
+	return floats.Sum(fitnesses) / float64(len(fitnesses))
+}
+
+// StdDev returns the standard deviation of the fitness of the population's
+// chromosomes. NaN fitness is ignored. Returns NaN if no chromosome has a
+// fitness.
+func (p Population) StdDev() float64 {
+	fitnesses := p.validFitnesses()
+	if len(fitnesses) == 0 {
+		return math.NaN()
+	}
+
+	mean := floats.Sum(fitnesses) / float64(len(fitnesses))
+	variance := 0.0
+	for _, f := range fitnesses {
+		variance += (f - mean) * (f - mean)
+	}
+	return math.Sqrt(variance / float64(len(fitnesses)))
+}
+
+// Merge returns a new population containing the population's chromosomes
+// followed by the chromosomes of the other populations. Chromosomes that are in
+// more than one of the populations are included once.
+func (p Population) Merge(others ...Population) Population {
+	included := make(map[*Chromosome]bool, len(p))
+	merged := make(Population, 0, len(p))
+	for _, population := range append([]Population{p}, others...) {
+		for _, c := range population {
+			if !included[c] {
+				included[c] = true
+				merged = append(merged, c)
+			}
+		}
+	}
+	return merged
+}
+
+// Dedup returns a new population without near-duplicate chromosomes. A
+// chromosome is removed if the euclidean distance between its genes and those
+// of an earlier chromosome is at most `epsilon`. With an epsilon of zero, only
+// chromosomes with identical genes are removed.
+func (p Population) Dedup(epsilon float64) Population {
+	unique := make(Population, 0, len(p))
+	if epsilon <= 0.0 {
+		hashes := make(map[uint64]Population, len(p))
+		for _, c := range p {
+			hash := c.Hash()
+			duplicate := false
+			for _, other := range hashes[hash] {
+				if floats.Equal(c.Genes, other.Genes) {
+					duplicate = true
+					break
+				}
+			}
+
+			if !duplicate {
+				hashes[hash] = append(hashes[hash], c)
+				unique = append(unique, c)
+			}
+		}
+		return unique
+	}
+
+	for _, c := range p {
+		duplicate := false
+		for _, other := range unique {
+			if len(c.Genes) == len(other.Genes) && c.Distance(other) <= epsilon {
+				duplicate = true
+				break
+			}
+		}
+
+		if !duplicate {
+			unique = append(unique, c)
+		}
+	}
+	return unique
+}
+
+// Filter returns a new population containing the chromosomes for which the
+// predicate returns true, in the population's order.
+func (p Population) Filter(predicate func(chromosome *Chromosome) bool) Population {
+	var filtered Population
+	for _, c := range p {
+		if predicate(c) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+// Sample returns a new population of `n` distinct chromosomes chosen uniformly
+// from the population, or every chromosome in a random order if `n` is at
+// least the size of the population.
+func (p Population) Sample(n int) Population {
+	if n > len(p) {
+		n = len(p)
+	} else if n < 0 {
+		n = 0
+	}
+
+	sample := make(Population, 0, n)
+	for _, i := range random.Perm(len(p))[:n] {
+		sample = append(sample, p[i])
+	}
+	return sample
+}
+
+// SampleWeighted returns a new population of `n` chromosomes chosen, with
+// replacement, with probabilities proportional to the given weights. Weights
+// that aren't positive are treated as zero, and chromosomes are chosen
+// uniformly if every weight is zero. Each chromosome is chosen in constant time
+// using the alias method, so sampling is fast even when repeated.
+func (p Population) SampleWeighted(n int, weights func(chromosome *Chromosome) float64) Population {
+	if len(p) == 0 || n <= 0 {
+		return Population{}
+	}
+
+	values := make([]float64, len(p))
+	for i, c := range p {
+		values[i] = weights(c)
+	}
+
+	table := weightTable{}
+	table.reset(p, values)
+
+	sample := make(Population, 0, n)
+	for i := 0; i < n; i++ {
+		sample = append(sample, table.sample(random))
+	}
+	return sample
+}
+
 // SumWeights returns the sum of the weights of the chromosomes in the population.
 func (p Population) SumWeights() float64 {
 	sum := 0.0
@@ -43,6 +281,31 @@ func (p Population) SumFitnesses() float64 {
 	return sum
 }
 
+// Diversity returns the genetic diversity of the population measured as the
+// mean standard deviation of the chromosomes' genes at each locus.
+func (p Population) Diversity() float64 {
+	if len(p) == 0 || len(p[0].Genes) == 0 {
+		return 0.0
+	}
+
+	sum := 0.0
+	for i := 0; i < len(p[0].Genes); i++ {
+		mean := 0.0
+		for _, c := range p {
+			mean += c.Genes[i]
+		}
+		mean /= float64(len(p))
+
+		variance := 0.0
+		for _, c := range p {
+			variance += (c.Genes[i] - mean) * (c.Genes[i] - mean)
+		}
+		sum += math.Sqrt(variance / float64(len(p)))
+	}
+
+	return sum / float64(len(p[0].Genes))
+}
+
 // CountNegativeWeights returns the number of chromosomes with negative weights
 // in the population.
 func (p Population) CountNegativeWeights() int {
@@ -74,9 +337,23 @@ func (p Population) ShiftWeights(value float64) {
 	}
 }
 
+// SlowestToEvaluate returns a new population containing the `k` chromosomes
+// whose fitness evaluations took the longest in descending order of
+// evaluation duration. `k` is clamped to the population's size. The receiver's
+// order is not modified.
+func (p Population) SlowestToEvaluate(k int) Population {
+	sorted := make(Population, len(p))
+	copy(sorted, p)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].EvaluationDuration > sorted[j].EvaluationDuration
+	})
+
+	return sorted.first(k)
+}
+
 // ShuffleChromosomes shuffles the chromosomes of the population.
 func (p Population) ShuffleChromosomes() {
-	rand.Shuffle(len(p), func(i, j int) {
+	random.Shuffle(len(p), func(i, j int) {
 		p[i], p[j] = p[j], p[i]
 	})
 }
@@ -93,3 +370,64 @@ func (p Population) ChromosomeWithMaxWeight() *Chromosome {
 	}
 	return p[maxIndex]
 }
+
+// MARK: Private methods
+
+// validFitnesses returns the fitness of each chromosome that doesn't have a
+// NaN fitness.
+func (p Population) validFitnesses() []float64 {
+	fitnesses := make([]float64, 0, len(p))
+	for _, c := range p {
+		if !math.IsNaN(c.Fitness) {
+			fitnesses = append(fitnesses, c.Fitness)
+		}
+	}
+	return fitnesses
+}
+
+// first returns the first `k` chromosomes of the population, clamping `k` to
+// [0, len(p)].
+func (p Population) first(k int) Population {
+	if k < 0 {
+		k = 0
+	} else if k > len(p) {
+		k = len(p)
+	}
+	return p[:k]
+}
+
+// sortByFitness sorts the population in ascending order of fitness. The order
+// is deterministic: ties are broken by the chromosomes' genes rather than their
+// positions in the population.
+func (p Population) sortByFitness() {
+	p.sortWith(nil)
+}
+
+// sortWith sorts the population in ascending order of the comparator, or of
+// fitness if the comparator is nil.
+func (p Population) sortWith(comparator Comparator) {
+	s := sorterPool.Get().(*populationSorter)
+	s.population = p
+	s.comparator = comparator
+	sort.Stable(s)
+
+	s.population = nil
+	s.comparator = nil
+	sorterPool.Put(s)
+}
+
+// Len returns the length of the sorter's population.
+func (s *populationSorter) Len() int {
+	return len(s.population)
+}
+
+// Less returns whether or not the chromosome at index `i` is less fit than the
+// chromosome at index `j`.
+func (s *populationSorter) Less(i, j int) bool {
+	return fitter(s.population[j], s.population[i], s.comparator)
+}
+
+// Swap swaps the chromosomes at indexes `i` and `j`.
+func (s *populationSorter) Swap(i, j int) {
+	s.population[i], s.population[j] = s.population[j], s.population[i]
+}
@@ -0,0 +1,115 @@
+package genetics
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTopK(t *testing.T) {
+	population := fitnessPopulation(2.0, math.NaN(), 5.0, 1.0)
+
+	tests := []struct {
+		k         int
+		fitnesses []float64
+	}{
+		{-1, []float64{}},
+		{0, []float64{}},
+		{2, []float64{5.0, 2.0}},
+		{4, []float64{5.0, 2.0, 1.0, math.NaN()}},
+		{10, []float64{5.0, 2.0, 1.0, math.NaN()}},
+	}
+
+	for _, test := range tests {
+		top := population.TopK(test.k)
+		if len(top) != len(test.fitnesses) {
+			t.Errorf("TopK(%d) has %d chromosomes, expected %d", test.k, len(top), len(test.fitnesses))
+			continue
+		}
+		for i, c := range top {
+			if !sameFloat(c.Fitness, test.fitnesses[i]) {
+				t.Errorf("TopK(%d)[%d] has fitness %f, expected %f", test.k, i, c.Fitness, test.fitnesses[i])
+			}
+		}
+	}
+}
+
+func TestSlowestToEvaluate(t *testing.T) {
+	population := Population{
+		&Chromosome{EvaluationDuration: time.Millisecond},
+		&Chromosome{EvaluationDuration: 3 * time.Millisecond},
+		&Chromosome{EvaluationDuration: 2 * time.Millisecond},
+	}
+
+	tests := []struct {
+		k         int
+		durations []time.Duration
+	}{
+		{-2, []time.Duration{}},
+		{1, []time.Duration{3 * time.Millisecond}},
+		{5, []time.Duration{3 * time.Millisecond, 2 * time.Millisecond, time.Millisecond}},
+	}
+
+	for _, test := range tests {
+		slowest := population.SlowestToEvaluate(test.k)
+		if len(slowest) != len(test.durations) {
+			t.Errorf("SlowestToEvaluate(%d) has %d chromosomes, expected %d", test.k, len(slowest), len(test.durations))
+			continue
+		}
+		for i, c := range slowest {
+			if c.EvaluationDuration != test.durations[i] {
+				t.Errorf("SlowestToEvaluate(%d)[%d] took %s, expected %s", test.k, i, c.EvaluationDuration, test.durations[i])
+			}
+		}
+	}
+}
+
+func TestQuantile(t *testing.T) {
+	population := fitnessPopulation(4.0, math.NaN(), 1.0, 3.0, 2.0)
+
+	tests := []struct {
+		q        float64
+		expected float64
+	}{
+		{-1.0, 1.0},
+		{0.0, 1.0},
+		{0.25, 1.75},
+		{0.5, 2.5},
+		{1.0, 4.0},
+		{2.0, 4.0},
+	}
+
+	for _, test := range tests {
+		if q := population.Quantile(test.q); math.Abs(q-test.expected) > 1e-12 {
+			t.Errorf("Quantile(%f) is %f, expected %f", test.q, q, test.expected)
+		}
+	}
+
+	if q := fitnessPopulation(math.NaN()).Quantile(0.5); !math.IsNaN(q) {
+		t.Errorf("Quantile of a population without fitness is %f, expected NaN", q)
+	}
+}
+
+func TestMeanFitnessAndStdDev(t *testing.T) {
+	tests := []struct {
+		fitnesses []float64
+		mean      float64
+		stdDev    float64
+	}{
+		{[]float64{}, math.NaN(), math.NaN()},
+		{[]float64{math.NaN()}, math.NaN(), math.NaN()},
+		{[]float64{3.0}, 3.0, 0.0},
+		{[]float64{2.0, 4.0, 4.0, 4.0, 5.0, 5.0, 7.0, 9.0}, 5.0, 2.0},
+		{[]float64{1.0, math.NaN(), 3.0}, 2.0, 1.0},
+	}
+
+	for _, test := range tests {
+		population := fitnessPopulation(test.fitnesses...)
+		if mean := population.MeanFitness(); !sameFloat(mean, test.mean) {
+			t.Errorf("%v: mean is %f, expected %f", test.fitnesses, mean, test.mean)
+		}
+		if stdDev := population.StdDev(); !sameFloat(stdDev, test.stdDev) {
+			t.Errorf("%v: standard deviation is %f, expected %f", test.fitnesses, stdDev, test.stdDev)
+		}
+	}
+}
@@ -0,0 +1,632 @@
+package genetics
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+// ErrInvalidProto is returned when data can't be decoded as a protobuf
+// message of the schema in `proto/genetics.proto`.
+var ErrInvalidProto = errors.New("genetics: invalid protobuf message")
+
+// Protobuf wire types.
+const (
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+	protoFixed32 = 5
+)
+
+// protoDecoder types read the fields of an encoded protobuf message. Once
+// decoding fails, the decoder's error is set and zero values are returned.
+type protoDecoder struct {
+	data []byte
+	err  error
+}
+
+// MARK: Public methods
+
+// MarshalProto encodes the chromosome as a `Chromosome` protobuf message.
+func (c Chromosome) MarshalProto() ([]byte, error) {
+	return c.appendProto(nil)
+}
+
+// UnmarshalProto decodes the chromosome from a `Chromosome` protobuf message.
+func (c *Chromosome) UnmarshalProto(data []byte) error {
+	*c = Chromosome{}
+	d := &protoDecoder{data: data}
+	for d.err == nil && len(d.data) > 0 {
+		field, wire := d.tag()
+		switch field {
+		case 1:
+			c.ID = d.varint(wire)
+		case 2:
+			c.Genes = d.doubles(wire, c.Genes)
+		case 3:
+			c.Fitness = d.double(wire)
+		case 4:
+			c.Age = int(d.varint(wire))
+		case 5:
+			c.Objectives = d.doubles(wire, c.Objectives)
+		case 6:
+			c.Violation = d.double(wire)
+		case 7:
+			c.CaseErrors = d.doubles(wire, c.CaseErrors)
+		case 8:
+			c.Behavior = d.doubles(wire, c.Behavior)
+		case 9:
+			c.Novelty = d.double(wire)
+		case 10:
+			if metadata := d.bytes(wire); len(metadata) > 0 && d.err == nil {
+				if err := json.Unmarshal(metadata, &c.Metadata); err != nil {
+					return err
+				}
+			}
+		case 11:
+			name, value := d.component(wire)
+			if d.err == nil {
+				if c.Components == nil {
+					c.Components = make(map[string]float64)
+				}
+				c.Components[name] = value
+			}
+		case 12:
+			c.EvaluationDuration = time.Duration(d.varint(wire))
+		default:
+			d.skip(wire)
+		}
+	}
+	return d.err
+}
+
+// MarshalProto encodes the population as a `Population` protobuf message.
+func (p Population) MarshalProto() ([]byte, error) {
+	return p.appendProto(nil)
+}
+
+// UnmarshalProto decodes the population from a `Population` protobuf message.
+func (p *Population) UnmarshalProto(data []byte) error {
+	*p = nil
+	d := &protoDecoder{data: data}
+	for d.err == nil && len(d.data) > 0 {
+		field, wire := d.tag()
+		switch field {
+		case 1:
+			message := d.bytes(wire)
+			if d.err != nil {
+				break
+			}
+
+			c := &Chromosome{}
+			if err := c.UnmarshalProto(message); err != nil {
+				return err
+			}
+			*p = append(*p, c)
+		default:
+			d.skip(wire)
+		}
+	}
+	return d.err
+}
+
+// UnmarshalProto decodes the configuration from an `EvolverConfiguration`
+// protobuf message. Methods are given by name as in the configuration's JSON
+// and YAML representations, and the resulting configuration is validated.
+func (c *EvolverConfiguration) UnmarshalProto(data []byte) error {
+	spec := evolverConfigurationSpec{}
+	d := &protoDecoder{data: data}
+	for d.err == nil && len(d.data) > 0 {
+		field, wire := d.tag()
+		switch field {
+		case 1:
+			spec.Selection = d.string(wire)
+		case 2:
+			spec.Crossover = d.crossoverSpec(wire)
+		case 3:
+			spec.Mutation = d.mutationSpec(wire)
+		case 4:
+			spec.MateChoice = d.mateChoiceSpec(wire)
+		case 5:
+			spec.FitnessScaling = d.scalingSpec(wire)
+		case 6:
+			spec.Crossovers = append(spec.Crossovers, d.crossoverSpec(wire))
+		case 7:
+			spec.Mutations = append(spec.Mutations, d.mutationSpec(wire))
+		case 8:
+			spec.Elitism = uint(d.varint(wire))
+		case 9:
+			spec.CrossoverRate = d.double(wire)
+		case 10:
+			spec.MutationRate = d.double(wire)
+		case 11:
+			spec.Bounds = append(spec.Bounds, d.geneBounds(wire))
+		case 12:
+			spec.ElitismRate = d.double(wire)
+		case 13:
+			spec.AdaptiveMutationRate = d.varint(wire) != 0
+		case 14:
+			spec.AdaptiveOperatorSelection = d.varint(wire) != 0
+		case 15:
+			spec.InvalidFitnessPolicy = d.string(wire)
+		case 16:
+			spec.ReuseChromosomes = d.varint(wire) != 0
+		case 17:
+			spec.MaxEvaluations = int(d.varint(wire))
+		case 18:
+			spec.GenerationTimeout = d.string(wire)
+		case 19:
+			spec.SkippedFitnessPolicy = d.string(wire)
+		case 20:
+			spec.ReplacementStrategy = d.string(wire)
+		case 21:
+			for _, i := range d.varints(wire, nil) {
+				spec.FrozenGenes = append(spec.FrozenGenes, int(i))
+			}
+		case 22:
+			spec.Regularization = d.regularizationSpec(wire)
+		case 23:
+			spec.NormalizeGenes = d.varint(wire) != 0
+		case 24:
+			spec.Conditions = append(spec.Conditions, d.geneCondition(wire))
+		case 25:
+			spec.GeneNames = append(spec.GeneNames, d.string(wire))
+		case 26:
+			spec.PanicPolicy = d.string(wire)
+		case 27:
+			spec.ReevaluateElites = int(d.varint(wire))
+		case 28:
+			spec.GenerationGap = d.double(wire)
+		case 29:
+			spec.GapReplacement = d.string(wire)
+		default:
+			d.skip(wire)
+		}
+	}
+
+	if d.err != nil {
+		return d.err
+	}
+	return c.apply(spec)
+}
+
+// MARK: Private methods
+
+// appendProto appends the chromosome's `Chromosome` protobuf message to the
+// buffer.
+func (c Chromosome) appendProto(buffer []byte) ([]byte, error) {
+	buffer = appendProtoVarint(buffer, 1, c.ID)
+	buffer = appendProtoDoubles(buffer, 2, c.Genes)
+	buffer = appendProtoDouble(buffer, 3, c.Fitness)
+	buffer = appendProtoVarint(buffer, 4, uint64(c.Age))
+	buffer = appendProtoDoubles(buffer, 5, c.Objectives)
+	buffer = appendProtoDouble(buffer, 6, c.Violation)
+	buffer = appendProtoDoubles(buffer, 7, c.CaseErrors)
+	buffer = appendProtoDoubles(buffer, 8, c.Behavior)
+	buffer = appendProtoDouble(buffer, 9, c.Novelty)
+
+	if len(c.Metadata) > 0 {
+		metadata, err := json.Marshal(c.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		buffer = appendProtoBytes(buffer, 10, metadata)
+	}
+
+	names := make([]string, 0, len(c.Components))
+	for name := range c.Components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		entry := appendProtoBytes(nil, 1, []byte(name))
+		entry = appendProtoDouble(entry, 2, c.Components[name])
+		buffer = appendProtoMessage(buffer, 11, entry)
+	}
+
+	buffer = appendProtoVarint(buffer, 12, uint64(c.EvaluationDuration))
+	return buffer, nil
+}
+
+// appendProto appends the population's `Population` protobuf message to the
+// buffer.
+func (p Population) appendProto(buffer []byte) ([]byte, error) {
+	for _, c := range p {
+		message, err := c.appendProto(nil)
+		if err != nil {
+			return nil, err
+		}
+		buffer = appendProtoMessage(buffer, 1, message)
+	}
+	return buffer, nil
+}
+
+// tag reads the field number and wire type of the next field.
+func (d *protoDecoder) tag() (int, int) {
+	key := d.uvarint()
+	if d.err == nil && key>>3 == 0 {
+		d.err = ErrInvalidProto
+	}
+	return int(key >> 3), int(key & 7)
+}
+
+// uvarint reads a variable length unsigned integer.
+func (d *protoDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+
+	value, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = ErrInvalidProto
+		return 0
+	}
+	d.data = d.data[n:]
+	return value
+}
+
+// fixed64 reads a fixed length 64-bit value.
+func (d *protoDecoder) fixed64() uint64 {
+	if d.err != nil {
+		return 0
+	}
+
+	if len(d.data) < 8 {
+		d.err = ErrInvalidProto
+		return 0
+	}
+	value := binary.LittleEndian.Uint64(d.data)
+	d.data = d.data[8:]
+	return value
+}
+
+// expect sets the decoder's error if the wire type isn't the expected type.
+func (d *protoDecoder) expect(wire int, expected int) bool {
+	if d.err == nil && wire != expected {
+		d.err = ErrInvalidProto
+	}
+	return d.err == nil
+}
+
+// varint reads a varint field.
+func (d *protoDecoder) varint(wire int) uint64 {
+	if !d.expect(wire, protoVarint) {
+		return 0
+	}
+	return d.uvarint()
+}
+
+// double reads a double field.
+func (d *protoDecoder) double(wire int) float64 {
+	if !d.expect(wire, protoFixed64) {
+		return 0.0
+	}
+	return math.Float64frombits(d.fixed64())
+}
+
+// bytes reads a length-delimited field.
+func (d *protoDecoder) bytes(wire int) []byte {
+	if !d.expect(wire, protoBytes) {
+		return nil
+	}
+
+	n := d.uvarint()
+	if d.err == nil && n > uint64(len(d.data)) {
+		d.err = ErrInvalidProto
+	}
+	if d.err != nil {
+		return nil
+	}
+
+	value := d.data[:n]
+	d.data = d.data[n:]
+	return value
+}
+
+// string reads a string field.
+func (d *protoDecoder) string(wire int) string {
+	return string(d.bytes(wire))
+}
+
+// doubles reads a packed or unpacked repeated double field and appends its
+// values to the given values.
+func (d *protoDecoder) doubles(wire int, values []float64) []float64 {
+	if wire != protoBytes {
+		return append(values, d.double(wire))
+	}
+
+	packed := &protoDecoder{data: d.bytes(wire)}
+	for d.err == nil && packed.err == nil && len(packed.data) > 0 {
+		values = append(values, packed.double(protoFixed64))
+	}
+
+	if d.err == nil {
+		d.err = packed.err
+	}
+	return values
+}
+
+// varints reads a packed or unpacked repeated varint field and appends its
+// values to the given values.
+func (d *protoDecoder) varints(wire int, values []uint64) []uint64 {
+	if wire != protoBytes {
+		return append(values, d.varint(wire))
+	}
+
+	packed := &protoDecoder{data: d.bytes(wire)}
+	for d.err == nil && packed.err == nil && len(packed.data) > 0 {
+		values = append(values, packed.uvarint())
+	}
+
+	if d.err == nil {
+		d.err = packed.err
+	}
+	return values
+}
+
+// skip skips a field that isn't part of the schema.
+func (d *protoDecoder) skip(wire int) {
+	switch wire {
+	case protoVarint:
+		d.uvarint()
+	case protoFixed64:
+		d.fixed64()
+	case protoBytes:
+		d.bytes(wire)
+	case protoFixed32:
+		if len(d.data) < 4 {
+			d.err = ErrInvalidProto
+		} else {
+			d.data = d.data[4:]
+		}
+	default:
+		d.err = ErrInvalidProto
+	}
+}
+
+// message returns a decoder of an embedded message field.
+func (d *protoDecoder) message(wire int) *protoDecoder {
+	return &protoDecoder{data: d.bytes(wire), err: d.err}
+}
+
+// finish sets the decoder's error from the decoder of an embedded message.
+func (d *protoDecoder) finish(embedded *protoDecoder) {
+	if d.err == nil {
+		d.err = embedded.err
+	}
+}
+
+// crossoverSpec reads a `CrossoverMethod` message field.
+func (d *protoDecoder) crossoverSpec(wire int) crossoverSpec {
+	spec := crossoverSpec{}
+	m := d.message(wire)
+	for m.err == nil && len(m.data) > 0 {
+		field, wire := m.tag()
+		switch field {
+		case 1:
+			spec.Method = m.string(wire)
+		case 2:
+			spec.Points = int(m.varint(wire))
+		case 3:
+			spec.Alpha = m.double(wire)
+		case 4:
+			spec.Eta = m.double(wire)
+		case 5:
+			spec.Parents = int(m.varint(wire))
+		case 6:
+			spec.Weight = m.double(wire)
+		case 7:
+			spec.Count = int(m.varint(wire))
+		case 8:
+			spec.Bias = m.double(wire)
+		case 9:
+			spec.BlockLength = int(m.varint(wire))
+		default:
+			m.skip(wire)
+		}
+	}
+	d.finish(m)
+	return spec
+}
+
+// mutationSpec reads a `MutationMethod` message field.
+func (d *protoDecoder) mutationSpec(wire int) mutationSpec {
+	spec := mutationSpec{}
+	m := d.message(wire)
+	for m.err == nil && len(m.data) > 0 {
+		field, wire := m.tag()
+		switch field {
+		case 1:
+			spec.Method = m.string(wire)
+		case 2:
+			spec.Scale = m.double(wire)
+		default:
+			m.skip(wire)
+		}
+	}
+	d.finish(m)
+	return spec
+}
+
+// mateChoiceSpec reads a `MateChoice` message field.
+func (d *protoDecoder) mateChoiceSpec(wire int) *mateChoiceSpec {
+	spec := &mateChoiceSpec{}
+	m := d.message(wire)
+	for m.err == nil && len(m.data) > 0 {
+		field, wire := m.tag()
+		switch field {
+		case 1:
+			spec.Selection = m.string(wire)
+		case 2:
+			spec.Candidates = int(m.varint(wire))
+		case 3:
+			spec.MinimumDistance = m.double(wire)
+		default:
+			m.skip(wire)
+		}
+	}
+	d.finish(m)
+	return spec
+}
+
+// scalingSpec reads a `FitnessScaling` message field.
+func (d *protoDecoder) scalingSpec(wire int) *scalingSpec {
+	spec := &scalingSpec{}
+	m := d.message(wire)
+	for m.err == nil && len(m.data) > 0 {
+		field, wire := m.tag()
+		switch field {
+		case 1:
+			spec.Method = m.string(wire)
+		case 2:
+			spec.Parameter = m.double(wire)
+		default:
+			m.skip(wire)
+		}
+	}
+	d.finish(m)
+	return spec
+}
+
+// regularizationSpec reads a `Regularization` message field.
+func (d *protoDecoder) regularizationSpec(wire int) *regularizationSpec {
+	spec := &regularizationSpec{}
+	m := d.message(wire)
+	for m.err == nil && len(m.data) > 0 {
+		field, wire := m.tag()
+		switch field {
+		case 1:
+			spec.Method = m.string(wire)
+		case 2:
+			spec.Strength = m.double(wire)
+		case 3:
+			spec.Prior = m.doubles(wire, spec.Prior)
+		default:
+			m.skip(wire)
+		}
+	}
+	d.finish(m)
+	return spec
+}
+
+// geneBounds reads a `GeneBounds` message field.
+func (d *protoDecoder) geneBounds(wire int) GeneBounds {
+	bounds := GeneBounds{}
+	m := d.message(wire)
+	for m.err == nil && len(m.data) > 0 {
+		field, wire := m.tag()
+		switch field {
+		case 1:
+			bounds.Min = m.double(wire)
+		case 2:
+			bounds.Max = m.double(wire)
+		case 3:
+			if scale := m.bytes(wire); m.err == nil {
+				m.err = bounds.Scale.UnmarshalText(scale)
+			}
+		case 4:
+			bounds.Step = m.double(wire)
+		default:
+			m.skip(wire)
+		}
+	}
+	d.finish(m)
+	return bounds
+}
+
+// geneCondition reads an embedded `GeneCondition` message.
+func (d *protoDecoder) geneCondition(wire int) GeneCondition {
+	condition := GeneCondition{}
+	m := d.message(wire)
+	for m.err == nil && len(m.data) > 0 {
+		field, wire := m.tag()
+		switch field {
+		case 1:
+			condition.Gene = int(m.varint(wire))
+		case 2:
+			condition.Parent = int(m.varint(wire))
+		case 3:
+			condition.Values = m.doubles(wire, condition.Values)
+		default:
+			m.skip(wire)
+		}
+	}
+	d.finish(m)
+	return condition
+}
+
+// component reads an entry of the `Chromosome` message's components map.
+func (d *protoDecoder) component(wire int) (string, float64) {
+	name, value := "", 0.0
+	m := d.message(wire)
+	for m.err == nil && len(m.data) > 0 {
+		field, wire := m.tag()
+		switch field {
+		case 1:
+			name = m.string(wire)
+		case 2:
+			value = m.double(wire)
+		default:
+			m.skip(wire)
+		}
+	}
+	d.finish(m)
+	return name, value
+}
+
+// MARK: Private functions
+
+// appendProtoTag appends the key of a field with the given number and wire
+// type.
+func appendProtoTag(buffer []byte, field int, wire int) []byte {
+	return appendUvarint(buffer, uint64(field)<<3|uint64(wire))
+}
+
+// appendProtoVarint appends a varint field unless its value is zero.
+func appendProtoVarint(buffer []byte, field int, value uint64) []byte {
+	if value == 0 {
+		return buffer
+	}
+	return appendUvarint(appendProtoTag(buffer, field, protoVarint), value)
+}
+
+// appendProtoDouble appends a double field unless its value is zero.
+func appendProtoDouble(buffer []byte, field int, value float64) []byte {
+	if math.Float64bits(value) == 0 {
+		return buffer
+	}
+	return appendFloat(appendProtoTag(buffer, field, protoFixed64), value)
+}
+
+// appendProtoDoubles appends a packed repeated double field unless it's empty.
+func appendProtoDoubles(buffer []byte, field int, values []float64) []byte {
+	if len(values) == 0 {
+		return buffer
+	}
+
+	buffer = appendProtoTag(buffer, field, protoBytes)
+	buffer = appendUvarint(buffer, uint64(8*len(values)))
+	for _, v := range values {
+		buffer = appendFloat(buffer, v)
+	}
+	return buffer
+}
+
+// appendProtoBytes appends a length-delimited field unless it's empty.
+func appendProtoBytes(buffer []byte, field int, value []byte) []byte {
+	if len(value) == 0 {
+		return buffer
+	}
+	return appendProtoMessage(buffer, field, value)
+}
+
+// appendProtoMessage appends an embedded message field, even if the message
+// is empty.
+func appendProtoMessage(buffer []byte, field int, message []byte) []byte {
+	buffer = appendProtoTag(buffer, field, protoBytes)
+	buffer = appendUvarint(buffer, uint64(len(message)))
+	return append(buffer, message...)
+}
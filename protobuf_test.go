@@ -0,0 +1,413 @@
+package genetics
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// wireMessage types build protobuf messages field by field as code generated
+// by protoc-gen-go encodes them: fields in order of field number, zero values
+// omitted, repeated scalars packed and map entries ordered by key. They're
+// written independently of the package's encoder so that the encoder and
+// decoder can be checked against the wire format.
+type wireMessage []byte
+
+func (m wireMessage) key(field int, wire int) wireMessage {
+	return m.uvarint(uint64(field)<<3 | uint64(wire))
+}
+
+func (m wireMessage) uvarint(value uint64) wireMessage {
+	var buffer [binary.MaxVarintLen64]byte
+	return append(m, buffer[:binary.PutUvarint(buffer[:], value)]...)
+}
+
+func (m wireMessage) fixed64(value float64) wireMessage {
+	var buffer [8]byte
+	binary.LittleEndian.PutUint64(buffer[:], math.Float64bits(value))
+	return append(m, buffer[:]...)
+}
+
+func (m wireMessage) varint(field int, value uint64) wireMessage {
+	return m.key(field, protoVarint).uvarint(value)
+}
+
+func (m wireMessage) double(field int, value float64) wireMessage {
+	return m.key(field, protoFixed64).fixed64(value)
+}
+
+func (m wireMessage) bytes(field int, value []byte) wireMessage {
+	return append(m.key(field, protoBytes).uvarint(uint64(len(value))), value...)
+}
+
+func (m wireMessage) string(field int, value string) wireMessage {
+	return m.bytes(field, []byte(value))
+}
+
+func (m wireMessage) doubles(field int, values ...float64) wireMessage {
+	var packed wireMessage
+	for _, v := range values {
+		packed = packed.fixed64(v)
+	}
+	return m.bytes(field, packed)
+}
+
+func (m wireMessage) varints(field int, values ...uint64) wireMessage {
+	var packed wireMessage
+	for _, v := range values {
+		packed = packed.uvarint(v)
+	}
+	return m.bytes(field, packed)
+}
+
+// goldenChromosome returns a chromosome with every field of the `Chromosome`
+// message set, and the message's encoding.
+func goldenChromosome() (*Chromosome, wireMessage) {
+	c := &Chromosome{
+		ID:                 7,
+		Genes:              []float64{1.0, -2.0},
+		Fitness:            0.5,
+		Age:                3,
+		Objectives:         []float64{1.0},
+		Violation:          0.25,
+		CaseErrors:         []float64{2.0},
+		Behavior:           []float64{4.0},
+		Novelty:            1.5,
+		Metadata:           map[string]interface{}{"a": 1.0},
+		Components:         map[string]float64{"x": 2.0, "y": 3.0},
+		EvaluationDuration: time.Microsecond,
+	}
+
+	message := wireMessage(nil).
+		varint(1, 7).
+		doubles(2, 1.0, -2.0).
+		double(3, 0.5).
+		varint(4, 3).
+		doubles(5, 1.0).
+		double(6, 0.25).
+		doubles(7, 2.0).
+		doubles(8, 4.0).
+		double(9, 1.5).
+		string(10, `{"a":1}`).
+		bytes(11, wireMessage(nil).string(1, "x").double(2, 2.0)).
+		bytes(11, wireMessage(nil).string(1, "y").double(2, 3.0)).
+		varint(12, 1000)
+	return c, message
+}
+
+// goldenCheckpoint returns a checkpoint with every field of the `Checkpoint`
+// message set, and the message's encoding.
+func goldenCheckpoint() (*Checkpoint, wireMessage) {
+	c, chromosome := goldenChromosome()
+	checkpoint := &Checkpoint{
+		Generation:   12,
+		Population:   Population{c, {ID: 8, Genes: []float64{3.0}}},
+		Random:       NewRandomSource(99),
+		MutationRate: 0.125,
+	}
+
+	population := wireMessage(nil).
+		bytes(1, chromosome).
+		bytes(1, wireMessage(nil).varint(1, 8).doubles(2, 3.0))
+
+	message := wireMessage(nil).
+		varint(1, 12).
+		bytes(2, population).
+		bytes(3, wireMessage(nil).varint(1, 99)).
+		double(4, 0.125)
+	return checkpoint, message
+}
+
+// goldenConfiguration returns the encoding of an `EvolverConfiguration`
+// message with every field set.
+func goldenConfiguration() wireMessage {
+	return wireMessage(nil).
+		string(1, "tournament").
+		bytes(2, wireMessage(nil).string(1, "point").varint(2, 2).double(3, 0.1).double(4, 15.0).double(6, 2.0).double(8, 0.75).varint(9, 3)).
+		bytes(3, wireMessage(nil).string(1, "gaussian").double(2, 0.2)).
+		bytes(4, wireMessage(nil).string(1, "tournament").varint(2, 4).double(3, 0.3)).
+		bytes(5, wireMessage(nil).string(1, "linear").double(2, 1.5)).
+		bytes(6, wireMessage(nil).string(1, "point").varint(7, 2)).
+		bytes(6, wireMessage(nil).string(1, "majority").varint(5, 5)).
+		bytes(7, wireMessage(nil).string(1, "uniform").double(2, 0.4)).
+		varint(8, 2).
+		double(9, 0.8).
+		double(10, 0.05).
+		bytes(11, wireMessage(nil).double(1, 1.0).double(2, 100.0).string(3, "log").double(4, 1.0)).
+		double(12, 0.1).
+		varint(13, 1).
+		varint(14, 1).
+		string(15, "clamp").
+		varint(16, 1).
+		varint(17, 500).
+		string(18, "2s").
+		string(19, "inherit").
+		string(20, "generational").
+		varints(21, 0, 2).
+		bytes(22, wireMessage(nil).string(1, "l2").double(2, 0.01).doubles(3, 1.0, 2.0)).
+		varint(23, 1).
+		bytes(24, wireMessage(nil).varint(1, 2).varint(2, 1).doubles(3, 1.0)).
+		string(25, "a").
+		string(25, "b").
+		string(25, "c").
+		string(26, "continue").
+		varint(27, 3).
+		double(28, 0.5).
+		string(29, "random")
+}
+
+func TestChromosomeProtoGolden(t *testing.T) {
+	c, golden := goldenChromosome()
+
+	data, err := c.MarshalProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, golden) {
+		t.Errorf("encoded %x, expected %x", data, []byte(golden))
+	}
+
+	decoded := &Chromosome{}
+	if err := decoded.UnmarshalProto(golden); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, c) {
+		t.Errorf("decoded %+v, expected %+v", decoded, c)
+	}
+}
+
+func TestChromosomeProtoUnpacked(t *testing.T) {
+	message := wireMessage(nil).double(2, 1.0).double(2, 2.0).double(5, 3.0)
+
+	c := &Chromosome{}
+	if err := c.UnmarshalProto(message); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c.Genes, []float64{1.0, 2.0}) || !reflect.DeepEqual(c.Objectives, []float64{3.0}) {
+		t.Errorf("decoded genes %v and objectives %v from unpacked fields", c.Genes, c.Objectives)
+	}
+}
+
+func TestChromosomeProtoUnknownFields(t *testing.T) {
+	message := wireMessage(nil).varint(1, 5).varint(100, 1).double(101, 1.0).string(102, "x").doubles(2, 1.0)
+
+	c := &Chromosome{}
+	if err := c.UnmarshalProto(message); err != nil {
+		t.Fatal(err)
+	}
+	if c.ID != 5 || !reflect.DeepEqual(c.Genes, []float64{1.0}) {
+		t.Errorf("decoded %+v around unknown fields", c)
+	}
+}
+
+func TestChromosomeProtoInvalid(t *testing.T) {
+	_, golden := goldenChromosome()
+	for _, data := range [][]byte{golden[:len(golden)-1], {0x12, 0x10, 0x00}, {0x00}} {
+		if err := (&Chromosome{}).UnmarshalProto(data); err != ErrInvalidProto {
+			t.Errorf("decoding %x returned %v, expected ErrInvalidProto", data, err)
+		}
+	}
+}
+
+func TestPopulationProtoRoundTrip(t *testing.T) {
+	c, _ := goldenChromosome()
+	population := Population{c, {ID: 2, Genes: []float64{}}, {ID: 3, Genes: []float64{math.Inf(1), math.MaxFloat64}}}
+
+	data, err := population.MarshalProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded Population
+	if err := decoded.UnmarshalProto(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded) != len(population) {
+		t.Fatalf("decoded %d chromosomes, expected %d", len(decoded), len(population))
+	}
+	for i := range population {
+		if decoded[i].ID != population[i].ID || len(decoded[i].Genes) != len(population[i].Genes) {
+			t.Errorf("decoded chromosome %d as %+v, expected %+v", i, decoded[i], population[i])
+		}
+		for j := range population[i].Genes {
+			if decoded[i].Genes[j] != population[i].Genes[j] {
+				t.Errorf("decoded gene %d of chromosome %d as %f, expected %f", j, i, decoded[i].Genes[j], population[i].Genes[j])
+			}
+		}
+	}
+}
+
+func TestCheckpointProtoGolden(t *testing.T) {
+	checkpoint, golden := goldenCheckpoint()
+
+	data, err := checkpoint.MarshalProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, golden) {
+		t.Errorf("encoded %x, expected %x", data, []byte(golden))
+	}
+
+	decoded := &Checkpoint{}
+	if err := decoded.UnmarshalProto(golden); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Generation != checkpoint.Generation || decoded.MutationRate != checkpoint.MutationRate {
+		t.Errorf("decoded generation %d and mutation rate %f", decoded.Generation, decoded.MutationRate)
+	}
+	if decoded.Random == nil || decoded.Random.state != checkpoint.Random.state {
+		t.Errorf("decoded random source %+v, expected state %d", decoded.Random, checkpoint.Random.state)
+	}
+	if !reflect.DeepEqual(decoded.Population, checkpoint.Population) {
+		t.Errorf("decoded population %v, expected %v", decoded.Population, checkpoint.Population)
+	}
+}
+
+func TestEvolverConfigurationProtoGolden(t *testing.T) {
+	c := &EvolverConfiguration{}
+	if err := c.UnmarshalProto(goldenConfiguration()); err != nil {
+		t.Fatal(err)
+	}
+
+	checks := []struct {
+		name     string
+		got      interface{}
+		expected interface{}
+	}{
+		{"selection", c.SelectionMethod.Type, SelectionMethodTypeTournament},
+		{"crossover", c.CrossoverMethod.Type, CrossoverMethodTypePoint},
+		{"crossover points", c.CrossoverMethod.Options.Points, 2},
+		{"crossover alpha", c.CrossoverMethod.Options.Alpha, 0.1},
+		{"crossover eta", c.CrossoverMethod.Options.Eta, 15.0},
+		{"crossover weight", c.CrossoverMethod.Weight, 2.0},
+		{"crossover bias", c.CrossoverMethod.Options.Bias, 0.75},
+		{"crossover block length", c.CrossoverMethod.Options.BlockLength, 3},
+		{"mutation", c.MutationMethod.Type, MutationMethodTypeGaussian},
+		{"mutation scale", c.MutationMethod.Scale, 0.2},
+		{"mate choice candidates", c.MateChoice.Candidates, 4},
+		{"mate choice minimum distance", c.MateChoice.MinimumDistance, 0.3},
+		{"fitness scaling parameter", c.FitnessScaling.Parameter, 1.5},
+		{"crossovers", len(c.CrossoverMethods), 2},
+		{"deprecated crossover count", c.CrossoverMethods[0].Options.Points, 2},
+		{"crossover parents", c.CrossoverMethods[1].Parents, 5},
+		{"mutations", len(c.MutationMethods), 1},
+		{"elitism", c.Elitism, uint(2)},
+		{"crossover rate", c.CrossoverRate, 0.8},
+		{"mutation rate", c.MutationRate, 0.05},
+		{"bounds", c.Bounds, []GeneBounds{{Min: 1.0, Max: 100.0, Scale: GeneScaleLog, Step: 1.0}}},
+		{"elitism rate", c.ElitismRate, 0.1},
+		{"adaptive mutation rate", c.AdaptiveMutationRate, true},
+		{"adaptive operator selection", c.AdaptiveOperatorSelection, true},
+		{"invalid fitness policy", c.InvalidFitnessPolicy, InvalidFitnessPolicyClamp},
+		{"reuse chromosomes", c.ReuseChromosomes, true},
+		{"max evaluations", c.MaxEvaluations, 500},
+		{"generation timeout", c.GenerationTimeout, 2 * time.Second},
+		{"skipped fitness policy", c.SkippedFitnessPolicy, SkippedFitnessPolicyInherit},
+		{"replacement strategy", c.ReplacementStrategy, ReplacementStrategyGenerational},
+		{"frozen genes", c.FrozenGenes, []int{0, 2}},
+		{"regularization prior", c.Regularization.Prior, []float64{1.0, 2.0}},
+		{"normalize genes", c.NormalizeGenes, true},
+		{"conditions", c.Conditions, []GeneCondition{{Gene: 2, Parent: 1, Values: []float64{1.0}}}},
+		{"gene names", c.Schema.Names(), []string{"a", "b", "c"}},
+		{"panic policy", c.PanicPolicy, PanicPolicyContinue},
+		{"reevaluate elites", c.ReevaluateElites, 3},
+		{"generation gap", c.GenerationGap, 0.5},
+		{"gap replacement", c.GapReplacement, GapReplacementRandom},
+	}
+	for _, check := range checks {
+		if !reflect.DeepEqual(check.got, check.expected) {
+			t.Errorf("decoded %s as %v, expected %v", check.name, check.got, check.expected)
+		}
+	}
+}
+
+// protoField objects describe a field of a message in the schema.
+type protoField struct {
+	name     string
+	typeName string
+}
+
+// protoSchema returns the fields of each message of `proto/genetics.proto` by
+// field number.
+func protoSchema(t *testing.T) map[string]map[int]protoField {
+	data, err := ioutil.ReadFile("proto/genetics.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messagePattern := regexp.MustCompile(`^message (\w+) \{`)
+	fieldPattern := regexp.MustCompile(`^\s*(?:repeated )?(map<[^>]+>|[\w.]+) (\w+) = (\d+)`)
+
+	schema := make(map[string]map[int]protoField)
+	var message string
+	for _, line := range strings.Split(string(data), "\n") {
+		if match := messagePattern.FindStringSubmatch(line); match != nil {
+			message = match[1]
+			schema[message] = make(map[int]protoField)
+		} else if strings.HasPrefix(line, "}") {
+			message = ""
+		} else if match := fieldPattern.FindStringSubmatch(line); match != nil && message != "" {
+			number, _ := strconv.Atoi(match[3])
+			schema[message][number] = protoField{name: match[2], typeName: match[1]}
+		}
+	}
+	return schema
+}
+
+// coverFields records the fields of each message of the schema that are set in
+// the encoded message, including the fields of its embedded messages.
+func coverFields(t *testing.T, schema map[string]map[int]protoField, message string, data []byte, covered map[string]map[int]bool) {
+	if covered[message] == nil {
+		covered[message] = make(map[int]bool)
+	}
+
+	d := &protoDecoder{data: data}
+	for d.err == nil && len(d.data) > 0 {
+		field, wire := d.tag()
+		covered[message][field] = true
+
+		if _, ok := schema[schema[message][field].typeName]; ok && wire == protoBytes {
+			coverFields(t, schema, schema[message][field].typeName, d.bytes(wire), covered)
+		} else {
+			d.skip(wire)
+		}
+	}
+	if d.err != nil {
+		t.Fatalf("invalid %s message: %s", message, d.err)
+	}
+}
+
+// TestProtoSchemaCoverage fails when a field is added to the schema without
+// being covered by the golden messages, which must then be extended, along with
+// the encoder and decoders.
+func TestProtoSchemaCoverage(t *testing.T) {
+	schema := protoSchema(t)
+	covered := make(map[string]map[int]bool)
+
+	_, checkpoint := goldenCheckpoint()
+	coverFields(t, schema, "Checkpoint", checkpoint, covered)
+	coverFields(t, schema, "EvolverConfiguration", goldenConfiguration(), covered)
+
+	for message, fields := range schema {
+		for number, field := range fields {
+			if !covered[message][number] {
+				t.Errorf("field %s = %d of message %s isn't covered by a golden message", field.name, number, message)
+			}
+		}
+	}
+	for message, fields := range covered {
+		for number := range fields {
+			if _, ok := schema[message][number]; !ok {
+				t.Errorf("golden message %s sets field %d, which isn't in the schema", message, number)
+			}
+		}
+	}
+}
@@ -0,0 +1,8 @@
+//go:build race
+// +build race
+
+package genetics
+
+// raceEnabled is true when the race detector is enabled, which makes pools drop
+// items and so defeats allocation counting.
+const raceEnabled = true
@@ -0,0 +1,174 @@
+package genetics
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+)
+
+// RandomSource objects are sources of pseudo-random numbers whose state can be
+// saved and restored, so that an evolution resumed from a checkpoint, or
+// replayed, draws the same random numbers as an uninterrupted evolution.
+//
+// Random sources are safe for concurrent use. They implement `rand.Source64`
+// using the SplitMix64 generator, and serialize their state as text.
+type RandomSource struct {
+	mutex sync.Mutex
+	state uint64
+}
+
+// packageSource is the source that the package draws random numbers from. It
+// uses math/rand's global source unless a random source is set.
+type packageSource struct {
+	mutex  sync.RWMutex
+	source *RandomSource
+}
+
+// The source and generator of the random numbers drawn by the package.
+var (
+	source = &packageSource{}
+	random = rand.New(source)
+)
+
+// MARK: Constructors
+
+// NewRandomSource creates and returns a new random source with the given seed.
+func NewRandomSource(seed int64) *RandomSource {
+	return &RandomSource{state: uint64(seed)}
+}
+
+// MARK: Public methods
+
+// Seed sets the state of the source from the seed.
+func (s *RandomSource) Seed(seed int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.state = uint64(seed)
+}
+
+// Int63 returns a non-negative pseudo-random 63-bit integer.
+func (s *RandomSource) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+// Uint64 returns a pseudo-random 64-bit integer.
+func (s *RandomSource) Uint64() uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.state += 0x9e3779b97f4a7c15
+	z := s.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// MarshalText encodes the state of the source as hexadecimal text.
+func (s *RandomSource) MarshalText() ([]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return []byte(strconv.FormatUint(s.state, 16)), nil
+}
+
+// UnmarshalText restores the state of the source from hexadecimal text.
+func (s *RandomSource) UnmarshalText(text []byte) error {
+	state, err := strconv.ParseUint(string(text), 16, 64)
+	if err != nil {
+		return fmt.Errorf("invalid random source state %q", text)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.state = state
+	return nil
+}
+
+// MARK: Public functions
+
+// SetRandomSource sets the source of every random number drawn by the package,
+// including by its selection, crossover and mutation methods. If the source is
+// nil, then math/rand's global source is used, which is the default.
+//
+// Because the source's state advances as numbers are drawn, saving it with a
+// population lets an evolution be resumed from where it left off.
+func SetRandomSource(s *RandomSource) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	source.source = s
+}
+
+// Random returns the random number generator that the package draws from.
+// Custom operators should draw from it so that their evolutions can be resumed
+// and replayed.
+func Random() *rand.Rand {
+	return random
+}
+
+// MARK: Private methods
+
+func (s *packageSource) Seed(seed int64) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.source != nil {
+		s.source.Seed(seed)
+	} else {
+		rand.Seed(seed)
+	}
+}
+
+func (s *packageSource) Int63() int64 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.source != nil {
+		return s.source.Int63()
+	}
+	return rand.Int63()
+}
+
+func (s *packageSource) Uint64() uint64 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.source != nil {
+		return s.source.Uint64()
+	}
+	return rand.Uint64()
+}
+
+// MARK: Private functions
+
+// currentRandomSource returns a copy of the package's random source, or nil if
+// one isn't set.
+func currentRandomSource() *RandomSource {
+	source.mutex.RLock()
+	defer source.mutex.RUnlock()
+	if source.source == nil {
+		return nil
+	}
+
+	source.source.mutex.Lock()
+	defer source.source.mutex.Unlock()
+	return &RandomSource{state: source.source.state}
+}
+
+// restoreRandomSource restores the state of the package's random source to the
+// state of the given source, setting a random source if one isn't set. Does
+// nothing if the given source is nil.
+func restoreRandomSource(s *RandomSource) {
+	if s == nil {
+		return
+	}
+
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	if source.source == nil {
+		source.source = &RandomSource{}
+	}
+
+	source.source.mutex.Lock()
+	defer source.source.mutex.Unlock()
+	source.source.state = s.state
+}
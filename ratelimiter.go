@@ -0,0 +1,109 @@
+package genetics
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter objects limit the rate of fitness evaluations with a token
+// bucket, for fitness functions that call rate limited services. The bucket
+// holds up to `Burst` tokens and refills at `Rate` tokens per second, and each
+// evaluation waits for, and spends, a token. Rate limiters are safe for
+// concurrent use, so a single limiter may be shared by several evolvers, or by
+// fitness functions evaluated in parallel, that call the same service.
+type RateLimiter struct {
+	// The number of evaluations allowed per second. If zero or negative, then
+	// evaluations aren't limited.
+	Rate float64
+
+	// The maximum number of evaluations that may be made at once after the
+	// limiter has been idle. Values less than one are treated as one.
+	Burst int
+
+	mutex  sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// MARK: Constructors
+
+// NewRateLimiter creates and returns a new rate limiter that allows `rate`
+// evaluations per second in bursts of at most `burst` evaluations.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		Rate:  rate,
+		Burst: burst,
+	}
+}
+
+// MARK: Public methods
+
+// Wait blocks until an evaluation is allowed and returns true, or returns false
+// without spending a token if the stop channel is closed first. The stop
+// channel may be nil.
+func (l *RateLimiter) Wait(stop <-chan struct{}) bool {
+	delay := l.reserve()
+	if delay <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-stop:
+		l.mutex.Lock()
+		l.tokens++
+		l.mutex.Unlock()
+		return false
+	}
+}
+
+// FitnessFunction returns a fitness function that waits for the limiter before
+// each evaluation of the given fitness function.
+func (l *RateLimiter) FitnessFunction(f FitnessFunction) FitnessFunction {
+	return func(chromosome *Chromosome, state *EvolutionState) float64 {
+		l.Wait(nil)
+		return f(chromosome, state)
+	}
+}
+
+// MARK: Private methods
+
+// wait waits for the limiter, if it isn't nil, as described by `Wait`.
+func (l *RateLimiter) wait(stop <-chan struct{}) bool {
+	if l == nil {
+		return true
+	}
+	return l.Wait(stop)
+}
+
+// reserve spends a token and returns how long to wait until it's available.
+// Tokens may be spent before they're available, so concurrent callers wait in
+// turn.
+func (l *RateLimiter) reserve() time.Duration {
+	if l.Rate <= 0.0 {
+		return 0
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	burst := math.Max(1.0, float64(l.Burst))
+	now := time.Now()
+	if l.last.IsZero() {
+		l.tokens = burst
+	} else {
+		l.tokens = math.Min(burst, l.tokens+now.Sub(l.last).Seconds()*l.Rate)
+	}
+	l.last = now
+
+	l.tokens--
+	if l.tokens >= 0.0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.Rate * float64(time.Second))
+}
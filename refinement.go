@@ -0,0 +1,255 @@
+package genetics
+
+import "math"
+
+// RefinementMethodType represents how a refinement estimates the gradient of
+// the fitness function.
+type RefinementMethodType uint
+
+// Types of refinement methods.
+const (
+	// Simultaneous perturbation stochastic approximation. The gradient is
+	// estimated from two evaluations of randomly perturbed genes, whatever the
+	// number of genes.
+	RefinementMethodTypeSPSA RefinementMethodType = 0
+
+	// Forward finite differences. The gradient is estimated from one evaluation
+	// for each gene.
+	RefinementMethodTypeFiniteDifference RefinementMethodType = 1
+)
+
+// Refinement objects refine the fittest chromosomes of each generation with
+// gradient steps estimated from evaluations of the fitness function, blending
+// the global search of evolution with cheap local search. Steps are made within
+// the genes' bounds and are only taken when they improve fitness.
+//
+// Evaluations made by a refinement count towards the evolution's evaluation
+// budget. Frozen and inactive genes aren't refined.
+type Refinement struct {
+	// How the gradient is estimated.
+	Method RefinementMethodType
+
+	// The number of the fittest chromosomes that are refined each generation.
+	Elites int
+
+	// The maximum number of gradient steps taken by each refined chromosome
+	// each generation.
+	Steps int
+
+	// The size of gradient steps relative to the range of a gene's bounds, or
+	// absolute for unbounded genes. The size is halved after each step that
+	// doesn't improve fitness.
+	StepSize float64
+
+	// The size of the perturbations used to estimate the gradient relative to
+	// the range of a gene's bounds, or absolute for unbounded genes.
+	Perturbation float64
+
+	improvements int
+}
+
+// MARK: Constructors
+
+// NewRefinement creates and returns a new refinement that takes `steps`
+// gradient steps of the fittest `elites` chromosomes each generation.
+func NewRefinement(method RefinementMethodType, elites int, steps int) *Refinement {
+	return &Refinement{
+		Method:       method,
+		Elites:       elites,
+		Steps:        steps,
+		StepSize:     0.01,
+		Perturbation: 0.001,
+	}
+}
+
+// MARK: String methods
+
+func (t RefinementMethodType) String() string {
+	switch t {
+	case RefinementMethodTypeSPSA:
+		return "spsa"
+	case RefinementMethodTypeFiniteDifference:
+		return "finite-difference"
+	default:
+		return "unknown"
+	}
+}
+
+// MARK: Public methods
+
+// Improvements returns the number of gradient steps that improved the fitness
+// of a chromosome.
+func (r Refinement) Improvements() int {
+	return r.improvements
+}
+
+// MARK: Private methods
+
+// refine refines the fittest chromosomes of the population with the evolver's
+// refinement.
+func (e Evolver) refine(population Population, state *EvolutionState) {
+	r := e.Refinement
+	if r == nil || r.Elites <= 0 || r.Steps <= 0 {
+		return
+	}
+
+	for _, c := range population.TopK(r.Elites) {
+		if !c.evaluated || c.estimated || isInvalidFitness(c.Fitness) {
+			continue
+		}
+
+		movable := e.Configuration.ActiveGenes(c.Genes)
+		if movable == nil {
+			movable = make([]bool, len(c.Genes))
+			for i := range movable {
+				movable[i] = true
+			}
+		}
+		for i, f := range e.Configuration.frozenGenes(len(c.Genes)) {
+			movable[i] = movable[i] && !f
+		}
+
+		scales := make([]float64, len(c.Genes))
+		for i := range scales {
+			scales[i] = 1.0
+			if bounds, ok := e.Configuration.BoundsForGene(i); ok {
+				scales[i] = bounds.Max - bounds.Min
+			}
+		}
+
+		size := r.StepSize
+		for step := 0; step < r.Steps; step++ {
+			gradient, ok := e.estimateGradient(c, movable, scales, state)
+			if !ok {
+				break
+			}
+
+			norm := 0.0
+			for _, g := range gradient {
+				norm = math.Max(norm, math.Abs(g))
+			}
+			if norm == 0.0 {
+				break
+			}
+
+			genes := copyValues(c.Genes)
+			for i, g := range gradient {
+				genes[i] += size * scales[i] * g / norm
+			}
+
+			fitness, ok := e.refinementFitness(genes, c, state)
+			if !ok {
+				break
+			}
+
+			if fitness > c.Fitness {
+				copy(c.Genes, genes)
+				c.Fitness = fitness
+				c.weight = fitness
+				r.improvements++
+			} else {
+				size /= 2.0
+			}
+		}
+	}
+}
+
+// estimateGradient returns the estimated gradient of the fitness function at
+// the chromosome's genes, measured in units of the genes' scales, and whether
+// or not the estimate's evaluations were successful.
+func (e Evolver) estimateGradient(c *Chromosome, movable []bool, scales []float64, state *EvolutionState) ([]float64, bool) {
+	r := e.Refinement
+	gradient := make([]float64, len(c.Genes))
+
+	if r.Method == RefinementMethodTypeFiniteDifference {
+		for i := range c.Genes {
+			if !movable[i] {
+				continue
+			}
+
+			genes := copyValues(c.Genes)
+			genes[i] += r.Perturbation * scales[i]
+			e.Configuration.clampGenes(genes)
+			if genes[i] == c.Genes[i] {
+				genes[i] -= r.Perturbation * scales[i]
+				e.Configuration.clampGenes(genes)
+			}
+
+			displacement := (genes[i] - c.Genes[i]) / scales[i]
+			if displacement == 0.0 {
+				continue
+			}
+
+			fitness, ok := e.refinementFitness(genes, c, state)
+			if !ok {
+				return nil, false
+			}
+			gradient[i] = (fitness - c.Fitness) / displacement
+		}
+		return gradient, true
+	}
+
+	delta := make([]float64, len(c.Genes))
+	plus := copyValues(c.Genes)
+	minus := copyValues(c.Genes)
+	for i := range c.Genes {
+		if !movable[i] {
+			continue
+		}
+
+		delta[i] = 1.0
+		if random.Float64() < 0.5 {
+			delta[i] = -1.0
+		}
+		plus[i] += r.Perturbation * scales[i] * delta[i]
+		minus[i] -= r.Perturbation * scales[i] * delta[i]
+	}
+
+	fitnessPlus, ok := e.refinementFitness(plus, c, state)
+	if !ok {
+		return nil, false
+	}
+	fitnessMinus, ok := e.refinementFitness(minus, c, state)
+	if !ok {
+		return nil, false
+	}
+
+	for i := range gradient {
+		if delta[i] != 0.0 {
+			gradient[i] = (fitnessPlus - fitnessMinus) / (2.0 * r.Perturbation * delta[i])
+		}
+	}
+	return gradient, true
+}
+
+// refinementFitness clamps the genes and returns the regularized fitness of a
+// chromosome with them, and whether or not the genes are legal, the budget
+// wasn't spent and the fitness is valid. The genes of the original chromosome
+// are unchanged.
+func (e Evolver) refinementFitness(genes []float64, original *Chromosome, state *EvolutionState) (float64, bool) {
+	if e.budgetSpent(state) || !e.RateLimiter.wait(e.Stop) {
+		return 0.0, false
+	}
+
+	e.Configuration.clampGenes(genes)
+	trial := original.Clone()
+	copy(trial.Genes, genes)
+	if e.Repair != nil {
+		e.Repair(trial)
+		copy(genes, trial.Genes)
+	}
+	if e.Validate != nil && e.Validate(trial) != nil {
+		return 0.0, false
+	}
+
+	fitness, attempts, err := e.evaluate(trial, state)
+	state.Evaluations += attempts
+	if err != nil || isInvalidFitness(fitness) {
+		return 0.0, false
+	}
+
+	if r := e.Configuration.Regularization; r != nil {
+		fitness -= r.Penalty(trial)
+	}
+	return fitness, true
+}
@@ -0,0 +1,156 @@
+package genetics
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// registry contains the custom operators registered by name.
+var registry = struct {
+	sync.RWMutex
+	selections            map[string]SelectionMethodFunction
+	crossovers            map[string]CrossoverMethodFunction
+	multiParentCrossovers map[string]MultiParentCrossoverFunction
+	mutations             map[string]MutationMethodFunction
+}{
+	selections:            make(map[string]SelectionMethodFunction),
+	crossovers:            make(map[string]CrossoverMethodFunction),
+	multiParentCrossovers: make(map[string]MultiParentCrossoverFunction),
+	mutations:             make(map[string]MutationMethodFunction),
+}
+
+// MARK: Global methods
+
+// RegisterSelection registers a custom selection function by name so that it
+// can be resolved by `ParseSelectionMethod`. Names are case-insensitive. It
+// panics if the function is nil or if the name is already in use.
+func RegisterSelection(name string, f SelectionMethodFunction) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	name = registryName(name, f == nil)
+	if _, ok := selectionMethodTypeForName(name); ok {
+		panic(fmt.Sprintf("genetics: selection %q is a built-in selection method", name))
+	}
+	if _, ok := registry.selections[name]; ok {
+		panic(fmt.Sprintf("genetics: selection %q is already registered", name))
+	}
+
+	registry.selections[name] = f
+}
+
+// RegisterCrossover registers a custom crossover function by name so that it
+// can be resolved by `ParseCrossoverMethod`. The optional spec parameter of a
+// registered crossover is its number of points. Names are case-insensitive. It
+// panics if the function is nil or if the name is already in use.
+func RegisterCrossover(name string, f CrossoverMethodFunction) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	name = registerableCrossoverName(name, f == nil)
+	registry.crossovers[name] = f
+}
+
+// RegisterMultiParentCrossover registers a custom multi-parent crossover
+// function by name so that it can be resolved by `ParseCrossoverMethod`. The
+// optional spec parameter of a registered multi-parent crossover is its number
+// of parents, which defaults to two. Names are case-insensitive. It panics if
+// the function is nil or if the name is already in use.
+func RegisterMultiParentCrossover(name string, f MultiParentCrossoverFunction) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	name = registerableCrossoverName(name, f == nil)
+	registry.multiParentCrossovers[name] = f
+}
+
+// RegisterMutation registers a custom mutation function by name so that it can
+// be resolved by `ParseMutationMethod` and `LookupMutation`. Names are
+// case-insensitive. It panics if the function is nil or if the name is already
+// in use.
+func RegisterMutation(name string, f MutationMethodFunction) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	name = registryName(name, f == nil)
+	if _, ok := mutationMethodTypeForName(name); ok {
+		panic(fmt.Sprintf("genetics: mutation %q is a built-in mutation method", name))
+	}
+	if _, ok := registry.mutations[name]; ok {
+		panic(fmt.Sprintf("genetics: mutation %q is already registered", name))
+	}
+
+	registry.mutations[name] = f
+}
+
+// LookupMutation returns the mutation function registered with the given name.
+func LookupMutation(name string) (MutationMethodFunction, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	f, ok := registry.mutations[strings.ToLower(name)]
+	return f, ok
+}
+
+// MARK: Private functions
+
+// registryName returns the normalized name of an operator being registered. It
+// panics if the name is empty or if the operator is nil.
+func registryName(name string, isNil bool) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || strings.Contains(name, ":") {
+		panic(fmt.Sprintf("genetics: invalid operator name %q", name))
+	}
+	if isNil {
+		panic(fmt.Sprintf("genetics: operator %q is nil", name))
+	}
+	return name
+}
+
+// registerableCrossoverName returns the normalized name of a crossover being
+// registered. It panics if the name is already in use.
+func registerableCrossoverName(name string, isNil bool) string {
+	name = registryName(name, isNil)
+	if _, ok := crossoverMethodTypeForName(name); ok || strings.HasSuffix(name, "-point") {
+		panic(fmt.Sprintf("genetics: crossover %q is a built-in crossover method", name))
+	}
+
+	_, crossover := registry.crossovers[name]
+	_, multiParentCrossover := registry.multiParentCrossovers[name]
+	if crossover || multiParentCrossover {
+		panic(fmt.Sprintf("genetics: crossover %q is already registered", name))
+	}
+
+	return name
+}
+
+// registeredSelection returns the selection function registered with the given
+// name.
+func registeredSelection(name string) (SelectionMethodFunction, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	f, ok := registry.selections[name]
+	return f, ok
+}
+
+// registeredCrossover returns a crossover method for the crossover registered
+// with the given name and spec parameter.
+func registeredCrossover(name string, parameter int) (*CrossoverMethod, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	if f, ok := registry.crossovers[name]; ok {
+		return NewCustomCrossoverMethod(f, CrossoverOptions{Points: parameter}), true
+	}
+
+	if f, ok := registry.multiParentCrossovers[name]; ok {
+		if parameter == 0 {
+			parameter = 2
+		}
+		return NewCustomMultiParentCrossoverMethod(f, parameter, CrossoverOptions{}), true
+	}
+
+	return nil, false
+}
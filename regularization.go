@@ -0,0 +1,145 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// RegularizationType represents a type of fitness regularization.
+type RegularizationType uint
+
+// Types of fitness regularization.
+const (
+	// The penalty is the sum of the absolute differences between the genes and
+	// the prior, which favours changing few genes.
+	RegularizationTypeL1 RegularizationType = 0
+
+	// The penalty is the sum of the squared differences between the genes and
+	// the prior, which favours small changes to every gene.
+	RegularizationTypeL2 RegularizationType = 1
+
+	RegularizationTypeCustom RegularizationType = 2
+)
+
+// RegularizationFunction takes a chromosome's genes and the prior genes and
+// returns the chromosome's unscaled penalty. The prior may be shorter than the
+// genes, in which case missing prior genes are zero.
+type RegularizationFunction func(genes []float64, prior []float64) float64
+
+// Regularization objects penalize the fitness of chromosomes based on their
+// complexity or their distance from prior genes, such as default parameters,
+// to bias evolution towards simpler and more robust chromosomes. The penalty
+// is scaled by the regularization's strength and subtracted from valid fitness
+// values.
+type Regularization struct {
+	Type     RegularizationType
+	Function RegularizationFunction
+	Strength float64
+
+	// Optional prior genes that penalties are measured from. If empty, then
+	// penalties measure the magnitude of the genes.
+	Prior []float64
+}
+
+// MARK: String methods
+
+func (t RegularizationType) String() string {
+	switch t {
+	case RegularizationTypeL1:
+		return "l1"
+	case RegularizationTypeL2:
+		return "l2"
+	default:
+		return "custom"
+	}
+}
+
+// MARK: Constructors
+
+// NewRegularization creates a new regularization from the given type,
+// strength and prior. To use a custom function, use the
+// `NewCustomRegularization` constructor.
+func NewRegularization(t RegularizationType, strength float64, prior []float64) *Regularization {
+	return &Regularization{
+		Type:     t,
+		Function: regularizationFunctionForType(t),
+		Strength: strength,
+		Prior:    prior,
+	}
+}
+
+// NewCustomRegularization creates a new custom regularization from the
+// provided function, strength and prior.
+func NewCustomRegularization(f RegularizationFunction, strength float64, prior []float64) *Regularization {
+	return &Regularization{
+		Type:     RegularizationTypeCustom,
+		Function: f,
+		Strength: strength,
+		Prior:    prior,
+	}
+}
+
+// ParseRegularization creates a new regularization with the given strength
+// and prior from a regularization name. Valid names are "l1" and "l2".
+func ParseRegularization(name string, strength float64, prior []float64) (*Regularization, error) {
+	switch strings.ToLower(name) {
+	case "l1":
+		return NewRegularization(RegularizationTypeL1, strength, prior), nil
+	case "l2":
+		return NewRegularization(RegularizationTypeL2, strength, prior), nil
+	default:
+		return nil, fmt.Errorf("unknown regularization %q", name)
+	}
+}
+
+// MARK: Public methods
+
+// Penalty returns the scaled penalty of the chromosome.
+func (r Regularization) Penalty(chromosome *Chromosome) float64 {
+	return r.Strength * r.Function(chromosome.Genes, r.Prior)
+}
+
+// MARK: Public functions
+
+// L1RegularizationFunction implements L1 regularization.
+var L1RegularizationFunction RegularizationFunction = func(genes []float64, prior []float64) float64 {
+	penalty := 0.0
+	for i, g := range genes {
+		penalty += math.Abs(g - priorGene(prior, i))
+	}
+	return penalty
+}
+
+// L2RegularizationFunction implements L2 regularization.
+var L2RegularizationFunction RegularizationFunction = func(genes []float64, prior []float64) float64 {
+	penalty := 0.0
+	for i, g := range genes {
+		d := g - priorGene(prior, i)
+		penalty += d * d
+	}
+	return penalty
+}
+
+// MARK: Private functions
+
+// priorGene returns the prior gene at index `i`, or zero if there isn't one.
+func priorGene(prior []float64, i int) float64 {
+	if i < len(prior) {
+		return prior[i]
+	}
+	return 0.0
+}
+
+// regularizationFunctionForType returns the regularization function for the
+// given type.
+func regularizationFunctionForType(t RegularizationType) RegularizationFunction {
+	switch t {
+	case RegularizationTypeL1:
+		return L1RegularizationFunction
+	case RegularizationTypeL2:
+		return L2RegularizationFunction
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,39 @@
+package genetics
+
+import "math"
+
+// RepairFunction defines a function that fixes a bred chromosome's genes in
+// place, such as removing duplicates from a permutation or items from an
+// overfilled knapsack.
+type RepairFunction func(chromosome *Chromosome)
+
+// MARK: Public functions
+
+// PermutationRepairFunction repairs chromosomes whose genes encode a
+// permutation of the integers [0, n), where n is the number of genes. Genes are
+// rounded and clamped to the range, and each gene that repeats an earlier
+// gene is replaced by the smallest missing integer.
+var PermutationRepairFunction RepairFunction = func(chromosome *Chromosome) {
+	n := len(chromosome.Genes)
+	used := make([]bool, n)
+	var duplicates []int
+	for i, g := range chromosome.Genes {
+		j := int(math.Max(0.0, math.Min(float64(n-1), math.Round(g))))
+		if math.IsNaN(g) || used[j] {
+			duplicates = append(duplicates, i)
+			continue
+		}
+
+		chromosome.Genes[i] = float64(j)
+		used[j] = true
+	}
+
+	missing := 0
+	for _, i := range duplicates {
+		for used[missing] {
+			missing++
+		}
+		chromosome.Genes[i] = float64(missing)
+		used[missing] = true
+	}
+}
@@ -0,0 +1,209 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ReplacementStrategy represents how each generation of a population is
+// replaced by the next.
+type ReplacementStrategy uint
+
+// Replacement strategies.
+const (
+	// Each generation is replaced by its elites and the children bred from it.
+	ReplacementStrategyGenerational ReplacementStrategy = 0
+
+	// Age-fitness Pareto optimization. Each generation, children are bred from
+	// the population and a random newborn with an age of zero is added. The
+	// population is then replaced by the chromosomes of the parents, children
+	// and newborn that are best on the Pareto front of fitness and youth, so
+	// that young chromosomes survive long enough to be optimized before they
+	// compete with old, fit chromosomes. Elitism isn't used because the fittest
+	// chromosome is never dominated. Genes of the newborn without bounds are
+	// drawn from [-1, 1].
+	ReplacementStrategyAgeFitnessPareto ReplacementStrategy = 1
+)
+
+// GapReplacement represents which chromosomes of a generation are replaced by
+// children when the generation gap replaces only part of the population.
+type GapReplacement uint
+
+// Gap replacements.
+const (
+	// Children replace the least fit chromosomes.
+	GapReplacementWorst GapReplacement = 0
+
+	// Children replace randomly chosen chromosomes other than the elites.
+	GapReplacementRandom GapReplacement = 1
+)
+
+// MARK: String methods
+
+func (s ReplacementStrategy) String() string {
+	switch s {
+	case ReplacementStrategyGenerational:
+		return "generational"
+	case ReplacementStrategyAgeFitnessPareto:
+		return "afpo"
+	default:
+		return "unknown"
+	}
+}
+
+func (r GapReplacement) String() string {
+	switch r {
+	case GapReplacementWorst:
+		return "worst"
+	case GapReplacementRandom:
+		return "random"
+	default:
+		return "unknown"
+	}
+}
+
+// MARK: Public functions
+
+// ParseReplacementStrategy returns the replacement strategy with the given
+// name. Valid names are "generational" and "afpo". An empty name is the
+// generational strategy.
+func ParseReplacementStrategy(name string) (ReplacementStrategy, error) {
+	switch strings.ToLower(name) {
+	case "", "generational":
+		return ReplacementStrategyGenerational, nil
+	case "afpo":
+		return ReplacementStrategyAgeFitnessPareto, nil
+	default:
+		return ReplacementStrategyGenerational, fmt.Errorf("unknown replacement strategy %q", name)
+	}
+}
+
+// ParseGapReplacement returns the gap replacement with the given name. Valid
+// names are "worst" and "random". An empty name is the worst replacement.
+func ParseGapReplacement(name string) (GapReplacement, error) {
+	switch strings.ToLower(name) {
+	case "", "worst":
+		return GapReplacementWorst, nil
+	case "random":
+		return GapReplacementRandom, nil
+	default:
+		return GapReplacementWorst, fmt.Errorf("unknown gap replacement %q", name)
+	}
+}
+
+// MARK: Private methods
+
+// applyGenerationGap places the chromosomes of the sorted population, other
+// than its elites, that survive the configuration's generation gap in to the
+// destination population, which contains the elites.
+func (e Evolver) applyGenerationGap(population Population, destination Population) Population {
+	gap := e.Configuration.GenerationGap
+	if gap <= 0.0 || gap >= 1.0 {
+		return destination
+	}
+
+	elites := len(destination)
+	replaced := int(math.Ceil(gap * float64(len(population))))
+	survivors := len(population) - replaced - elites
+	if survivors <= 0 {
+		return destination
+	}
+
+	candidates := population[:len(population)-elites]
+	if e.Configuration.GapReplacement == GapReplacementRandom {
+		for _, i := range e.source().Perm(len(candidates))[:survivors] {
+			destination = append(destination, candidates[i])
+		}
+		return destination
+	}
+	return append(destination, candidates[len(candidates)-survivors:]...)
+}
+
+// breedAgeFitnessGeneration breeds children and a newborn from the population,
+// evaluates them, and returns the survivors of age-fitness Pareto replacement
+// along with the number of invalid fitness values.
+func (e Evolver) breedAgeFitnessGeneration(population Population, state *EvolutionState) (Population, int, error) {
+	e.prepareSelection(population, state)
+
+	combined := make(Population, 0, 2*len(population))
+	combined = append(combined, population...)
+	for i := 1; i < len(population); i++ {
+		combined = append(combined, e.breedChild(population, i, state))
+		if state.panic != nil {
+			return population, 0, state.panic
+		}
+	}
+	combined = append(combined, e.Configuration.randomChromosome(len(population[0].Genes)))
+
+	state.Population = combined
+	invalid, err := e.calculateFitnesses(combined, state)
+	if err != nil {
+		return population, invalid, err
+	}
+
+	survivors := ageFitnessSurvivors(combined, len(population))
+	if e.Configuration.ReuseChromosomes {
+		surviving := make(map[*Chromosome]bool, len(survivors))
+		for _, c := range survivors {
+			surviving[c] = true
+		}
+		for _, c := range combined {
+			if !surviving[c] {
+				releaseChromosome(c)
+			}
+		}
+	}
+
+	state.Population = survivors
+	return survivors, invalid, nil
+}
+
+// MARK: Private functions
+
+// ageFitnessSurvivors returns `n` chromosomes chosen front by front from the
+// Pareto fronts of fitness and youth. The fittest chromosomes of the last front
+// that only partially survives are chosen.
+func ageFitnessSurvivors(population Population, n int) Population {
+	objectives := make([][]float64, len(population))
+	for i, c := range population {
+		fitness := c.Fitness
+		if math.IsNaN(fitness) {
+			fitness = math.Inf(-1)
+		}
+		objectives[i] = []float64{fitness, -float64(c.Age)}
+	}
+
+	survivors := make(Population, 0, n)
+	remaining := make([]int, len(population))
+	for i := range remaining {
+		remaining[i] = i
+	}
+
+	for len(survivors) < n && len(remaining) > 0 {
+		var front Population
+		var dominated []int
+		for _, i := range remaining {
+			isDominated := false
+			for _, j := range remaining {
+				if Dominates(objectives[j], objectives[i]) {
+					isDominated = true
+					break
+				}
+			}
+
+			if isDominated {
+				dominated = append(dominated, i)
+			} else {
+				front = append(front, population[i])
+			}
+		}
+
+		if len(survivors)+len(front) > n {
+			front = front.TopK(n - len(survivors))
+		}
+		survivors = append(survivors, front...)
+		remaining = dominated
+	}
+	return survivors
+}
@@ -0,0 +1,422 @@
+// Package results records the runs of experiments to a SQL database so that
+// experiments can be compared with those run in the past.
+//
+// Each run's configuration, seed, parameter values, generation statistics and
+// best chromosome are recorded. A `DB` is a `genetics.RunRecorder`, so it can
+// be assigned to an experiment's `Recorder`:
+//
+//	db, err := results.Open("results.db")
+//	...
+//	experiment.Name = "mutation-rate"
+//	experiment.Recorder = db
+//
+// `Open` opens a SQLite database, but no driver is imported by this package,
+// so import one registered as "sqlite3", such as github.com/mattn/go-sqlite3.
+// Otherwise use `New` with a database opened with another driver, such as a
+// PostgreSQL driver, and set the database's `Placeholder` if the driver doesn't
+// use "?" placeholders.
+package results
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	genetics "github.com/colinc86/go-genetics"
+)
+
+// schema contains the statements that create the database's tables. They only
+// use types and syntax common to SQLite and PostgreSQL, so run identifiers are
+// assigned by `RecordRun` rather than by the database.
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS runs (
+		id BIGINT PRIMARY KEY,
+		experiment TEXT NOT NULL,
+		parameters TEXT NOT NULL,
+		configuration TEXT NOT NULL,
+		run INTEGER NOT NULL,
+		seed BIGINT NOT NULL,
+		generations INTEGER NOT NULL,
+		evaluations BIGINT NOT NULL,
+		best_fitness DOUBLE PRECISION,
+		best_genes TEXT,
+		recorded TIMESTAMP NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS generations (
+		run_id BIGINT NOT NULL REFERENCES runs(id),
+		generation INTEGER NOT NULL,
+		best DOUBLE PRECISION,
+		mean DOUBLE PRECISION,
+		worst DOUBLE PRECISION,
+		diversity DOUBLE PRECISION,
+		elapsed DOUBLE PRECISION NOT NULL,
+		invalid_fitnesses INTEGER NOT NULL,
+		evaluations BIGINT NOT NULL,
+		PRIMARY KEY (run_id, generation)
+	)`,
+	`CREATE INDEX IF NOT EXISTS runs_experiment ON runs (experiment)`,
+}
+
+// DB types record the runs of experiments to a database.
+type DB struct {
+	// An optional function that returns the driver's placeholder for the n-th
+	// parameter of a statement, starting from one. For example, PostgreSQL
+	// drivers use "$1". If nil, then "?" is used.
+	Placeholder func(n int) string
+
+	db *sql.DB
+}
+
+// Run objects describe a recorded run.
+type Run struct {
+	ID            int64
+	Experiment    string
+	Parameters    map[string]float64
+	Configuration map[string]interface{}
+	Run           int
+	Seed          int64
+	Generations   int
+	Evaluations   int
+	BestFitness   float64
+	BestGenes     []float64
+	Recorded      time.Time
+}
+
+// Summary objects summarize the runs of an experiment with the same parameter
+// values.
+type Summary struct {
+	Experiment string
+	Parameters map[string]float64
+
+	// The number of runs and their mean, standard deviation and maximum best
+	// fitness.
+	Runs              int
+	MeanBestFitness   float64
+	BestFitnessStdDev float64
+	MaxBestFitness    float64
+
+	// The mean number of generations evolved by the runs.
+	MeanGenerations float64
+}
+
+// MARK: Constructors
+
+// Open opens the SQLite database at the given path, creating it and its tables
+// if they don't exist. A SQLite driver registered as "sqlite3" must be
+// imported, otherwise an error is returned.
+func Open(path string) (*DB, error) {
+	registered := false
+	for _, driver := range sql.Drivers() {
+		registered = registered || driver == "sqlite3"
+	}
+	if !registered {
+		return nil, fmt.Errorf(`results: no SQLite driver is registered as "sqlite3", import one such as github.com/mattn/go-sqlite3`)
+	}
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := New(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return results, nil
+}
+
+// New creates and returns a new results database that records to the given
+// database, creating its tables if they don't exist. SQLite and PostgreSQL
+// databases are supported.
+func New(db *sql.DB) (*DB, error) {
+	for _, statement := range schema {
+		if _, err := db.Exec(statement); err != nil {
+			return nil, fmt.Errorf("results: unable to create tables: %s", err)
+		}
+	}
+	return &DB{db: db}, nil
+}
+
+// MARK: Public methods
+
+// Close closes the database.
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// RecordRun records the run.
+func (d *DB) RecordRun(record genetics.RunRecord) error {
+	parameters, err := json.Marshal(record.Parameters)
+	if err != nil {
+		return err
+	}
+
+	summary := map[string]interface{}{}
+	if record.Configuration != nil {
+		summary = record.Configuration.Summary()
+	}
+
+	configuration, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+
+	var bestFitness interface{}
+	var bestGenes interface{}
+	if record.Best != nil {
+		bestFitness = nullableFloat(record.Best.Fitness)
+		genes, err := json.Marshal(record.Best.Genes)
+		if err != nil {
+			return err
+		}
+		bestGenes = string(genes)
+	}
+
+	generations := 0
+	if len(record.Stats) > 0 {
+		generations = record.Stats[len(record.Stats)-1].Generation
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	// Concurrent recordings may be assigned the same identifier, in which case
+	// all but one fail to insert their run.
+	var id int64
+	if err = tx.QueryRow(`SELECT COALESCE(MAX(id), 0) + 1 FROM runs`).Scan(&id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err = tx.Exec(fmt.Sprintf(`INSERT INTO runs (id, experiment, parameters, configuration, run, seed, generations, evaluations, best_fitness, best_genes, recorded)
+		VALUES (%s)`, d.placeholders(11)),
+		id, record.Experiment, string(parameters), string(configuration), record.Run, record.Seed, generations, record.Evaluations, bestFitness, bestGenes, time.Now().UTC()); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	statement, err := tx.Prepare(fmt.Sprintf(`INSERT INTO generations (run_id, generation, best, mean, worst, diversity, elapsed, invalid_fitnesses, evaluations)
+		VALUES (%s)`, d.placeholders(9)))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer statement.Close()
+
+	for _, s := range record.Stats {
+		if _, err = statement.Exec(id, s.Generation, nullableFloat(s.Best), nullableFloat(s.Mean), nullableFloat(s.Worst), nullableFloat(s.Diversity), s.Elapsed.Seconds(), s.InvalidFitnesses, s.Evaluations); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Experiments returns the names of the recorded experiments in alphabetical
+// order.
+func (d *DB) Experiments() ([]string, error) {
+	rows, err := d.db.Query(`SELECT DISTINCT experiment FROM runs ORDER BY experiment`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// Runs returns the recorded runs of the named experiment in the order that
+// they were recorded.
+func (d *DB) Runs(experiment string) ([]Run, error) {
+	rows, err := d.db.Query(fmt.Sprintf(`SELECT id, experiment, parameters, configuration, run, seed, generations, evaluations, best_fitness, best_genes, recorded
+		FROM runs WHERE experiment = %s ORDER BY id`, d.placeholder(1)), experiment)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []Run
+	for rows.Next() {
+		run := Run{}
+		var parameters, configuration string
+		var bestFitness sql.NullFloat64
+		var bestGenes sql.NullString
+		if err := rows.Scan(&run.ID, &run.Experiment, &parameters, &configuration, &run.Run, &run.Seed, &run.Generations, &run.Evaluations, &bestFitness, &bestGenes, &run.Recorded); err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal([]byte(parameters), &run.Parameters); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(configuration), &run.Configuration); err != nil {
+			return nil, err
+		}
+		if bestGenes.Valid {
+			if err := json.Unmarshal([]byte(bestGenes.String), &run.BestGenes); err != nil {
+				return nil, err
+			}
+		}
+
+		run.BestFitness = math.NaN()
+		if bestFitness.Valid {
+			run.BestFitness = bestFitness.Float64
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// Stats returns the statistics of each generation of the run with the given
+// identifier.
+func (d *DB) Stats(runID int64) (genetics.EvolutionStats, error) {
+	rows, err := d.db.Query(fmt.Sprintf(`SELECT generation, best, mean, worst, diversity, elapsed, invalid_fitnesses, evaluations
+		FROM generations WHERE run_id = %s ORDER BY generation`, d.placeholder(1)), runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats genetics.EvolutionStats
+	for rows.Next() {
+		s := genetics.GenerationStats{}
+		var best, mean, worst, diversity sql.NullFloat64
+		var elapsed float64
+		if err := rows.Scan(&s.Generation, &best, &mean, &worst, &diversity, &elapsed, &s.InvalidFitnesses, &s.Evaluations); err != nil {
+			return nil, err
+		}
+
+		s.Best = floatOrNaN(best)
+		s.Mean = floatOrNaN(mean)
+		s.Worst = floatOrNaN(worst)
+		s.Diversity = floatOrNaN(diversity)
+		s.Elapsed = time.Duration(elapsed * float64(time.Second))
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
+
+// Compare summarizes the runs of the named experiments, grouping runs with the
+// same parameter values. Summaries are ordered by experiment, in the order
+// given, and then in descending order of mean best fitness.
+func (d *DB) Compare(experiments ...string) ([]Summary, error) {
+	var summaries []Summary
+	for _, experiment := range experiments {
+		runs, err := d.Runs(experiment)
+		if err != nil {
+			return nil, err
+		}
+
+		var groups []Summary
+		var fitnesses [][]float64
+		indexes := make(map[string]int)
+		for _, run := range runs {
+			key, err := json.Marshal(run.Parameters)
+			if err != nil {
+				return nil, err
+			}
+
+			i, ok := indexes[string(key)]
+			if !ok {
+				i = len(groups)
+				indexes[string(key)] = i
+				groups = append(groups, Summary{Experiment: experiment, Parameters: run.Parameters})
+				fitnesses = append(fitnesses, nil)
+			}
+
+			groups[i].Runs++
+			groups[i].MeanGenerations += float64(run.Generations)
+			if !math.IsNaN(run.BestFitness) {
+				fitnesses[i] = append(fitnesses[i], run.BestFitness)
+			}
+		}
+
+		for i := range groups {
+			groups[i].MeanGenerations /= float64(groups[i].Runs)
+			groups[i].MeanBestFitness, groups[i].BestFitnessStdDev, groups[i].MaxBestFitness = summarize(fitnesses[i])
+		}
+
+		sort.SliceStable(groups, func(i, j int) bool {
+			return groups[i].MeanBestFitness > groups[j].MeanBestFitness
+		})
+		summaries = append(summaries, groups...)
+	}
+	return summaries, nil
+}
+
+// MARK: Private methods
+
+// placeholder returns the placeholder of the n-th parameter of a statement.
+func (d *DB) placeholder(n int) string {
+	if d.Placeholder == nil {
+		return "?"
+	}
+	return d.Placeholder(n)
+}
+
+// placeholders returns the comma separated placeholders of the first n
+// parameters of a statement.
+func (d *DB) placeholders(n int) string {
+	placeholders := make([]string, n)
+	for i := range placeholders {
+		placeholders[i] = d.placeholder(i + 1)
+	}
+	return strings.Join(placeholders, ", ")
+}
+
+// MARK: Private functions
+
+// summarize returns the mean, sample standard deviation and maximum of the
+// values, or NaN if there are none.
+func summarize(values []float64) (float64, float64, float64) {
+	if len(values) == 0 {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+
+	mean, max := 0.0, math.Inf(-1)
+	for _, v := range values {
+		mean += v
+		max = math.Max(max, v)
+	}
+	mean /= float64(len(values))
+
+	if len(values) == 1 {
+		return mean, 0.0, max
+	}
+
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(values)-1)), max
+}
+
+// nullableFloat returns the value, or nil if it's NaN or infinite, since
+// databases can't store them consistently.
+func nullableFloat(value float64) interface{} {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil
+	}
+	return value
+}
+
+// floatOrNaN returns the value, or NaN if it's null.
+func floatOrNaN(value sql.NullFloat64) float64 {
+	if !value.Valid {
+		return math.NaN()
+	}
+	return value.Float64
+}
@@ -0,0 +1,231 @@
+package genetics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// FallibleFitnessFunction defines a fitness function that may fail, such as
+// one that calls an external service. Errors are transient, and the evaluation
+// is retried, unless they're wrapped with `PermanentError`.
+type FallibleFitnessFunction func(chromosome *Chromosome, state *EvolutionState) (float64, error)
+
+// FitnessFailurePolicy represents how chromosomes whose evaluation failed,
+// after any retries, are handled.
+type FitnessFailurePolicy uint
+
+// Fitness failure policies.
+const (
+	// Evolution stops with an error that wraps the evaluation's error.
+	FitnessFailurePolicyAbort FitnessFailurePolicy = 0
+
+	// Chromosomes are given the retry policy's penalty fitness.
+	FitnessFailurePolicyPenalty FitnessFailurePolicy = 1
+
+	// Chromosomes are skipped, as if the evaluation budget were spent, and are
+	// assigned a fitness by the configuration's skipped fitness policy.
+	FitnessFailurePolicyDrop FitnessFailurePolicy = 2
+)
+
+// RetryPolicy objects describe how evaluations of a fallible fitness function
+// that fail with transient errors are retried, and how chromosomes whose
+// evaluations fail permanently are handled. Retries wait for an exponentially
+// increasing backoff.
+type RetryPolicy struct {
+	// The maximum number of times a failed evaluation is retried.
+	MaxRetries int
+
+	// The time waited before the first retry.
+	Backoff time.Duration
+
+	// The factor that the backoff is multiplied by after each retry.
+	Multiplier float64
+
+	// The maximum time waited before a retry, or zero for no limit.
+	MaxBackoff time.Duration
+
+	// How chromosomes whose evaluations failed are handled.
+	Failure FitnessFailurePolicy
+
+	// The fitness of chromosomes whose evaluations failed when using the penalty
+	// policy.
+	PenaltyFitness float64
+}
+
+// permanentError types wrap errors that shouldn't be retried.
+type permanentError struct {
+	err error
+}
+
+// MARK: Constructors
+
+// NewRetryPolicy creates and returns a new retry policy that retries failed
+// evaluations up to `maxRetries` times, doubling the backoff after each retry,
+// and that gives chromosomes whose evaluations failed a penalty fitness of
+// -`math.MaxFloat64`.
+func NewRetryPolicy(maxRetries int, backoff time.Duration) *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries:     maxRetries,
+		Backoff:        backoff,
+		Multiplier:     2.0,
+		Failure:        FitnessFailurePolicyPenalty,
+		PenaltyFitness: -math.MaxFloat64,
+	}
+}
+
+// NewFallibleEvolver creates and returns a new evolver that evaluates
+// chromosomes with a fallible fitness function, retrying and handling failures
+// with the retry policy. If the policy is nil, then failures aren't retried
+// and stop evolution.
+func NewFallibleEvolver(configuration *EvolverConfiguration, fitnessFunction FallibleFitnessFunction, policy *RetryPolicy) *Evolver {
+	return &Evolver{
+		Configuration:           configuration,
+		FitnessFunction:         policy.FitnessFunction(fitnessFunction),
+		FallibleFitnessFunction: fitnessFunction,
+		RetryPolicy:             policy,
+	}
+}
+
+// MARK: String methods
+
+func (p FitnessFailurePolicy) String() string {
+	switch p {
+	case FitnessFailurePolicyAbort:
+		return "abort"
+	case FitnessFailurePolicyPenalty:
+		return "penalty"
+	case FitnessFailurePolicyDrop:
+		return "drop"
+	default:
+		return "unknown"
+	}
+}
+
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+// MARK: Public methods
+
+// FitnessFunction returns a fitness function that retries failed evaluations
+// of the fallible fitness function as described by the policy, for uses that
+// can't handle errors. Chromosomes whose evaluations failed are given the
+// policy's penalty fitness when using the penalty policy, and NaN otherwise. If
+// the policy is nil, then failures aren't retried.
+func (p *RetryPolicy) FitnessFunction(f FallibleFitnessFunction) FitnessFunction {
+	return func(chromosome *Chromosome, state *EvolutionState) float64 {
+		fitness, _, err := p.evaluate(f, chromosome, state, nil, nil)
+		if err == nil {
+			return fitness
+		}
+		if p != nil && p.Failure == FitnessFailurePolicyPenalty {
+			return p.PenaltyFitness
+		}
+		return math.NaN()
+	}
+}
+
+// Unwrap returns the wrapped error.
+func (e permanentError) Unwrap() error {
+	return e.err
+}
+
+// MARK: Public functions
+
+// PermanentError wraps an error returned by a fallible fitness function so
+// that the evaluation isn't retried.
+func PermanentError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err: err}
+}
+
+// IsPermanentError returns whether or not the error, or an error that it wraps,
+// was wrapped with `PermanentError`.
+func IsPermanentError(err error) bool {
+	var permanent permanentError
+	return errors.As(err, &permanent)
+}
+
+// ParseFitnessFailurePolicy returns the fitness failure policy with the given
+// name. Valid names are "abort", "penalty" and "drop". An empty name is the
+// abort policy.
+func ParseFitnessFailurePolicy(name string) (FitnessFailurePolicy, error) {
+	switch strings.ToLower(name) {
+	case "", "abort":
+		return FitnessFailurePolicyAbort, nil
+	case "penalty":
+		return FitnessFailurePolicyPenalty, nil
+	case "drop":
+		return FitnessFailurePolicyDrop, nil
+	default:
+		return FitnessFailurePolicyAbort, fmt.Errorf("unknown fitness failure policy %q", name)
+	}
+}
+
+// MARK: Private methods
+
+// evaluate evaluates the chromosome with the fallible fitness function,
+// retrying transient failures, and returns its fitness, the number of
+// evaluations made and the error of the last evaluation if every evaluation
+// failed. Retries wait for the rate limiter, and stop early if the stop channel
+// is closed.
+func (p *RetryPolicy) evaluate(f FallibleFitnessFunction, chromosome *Chromosome, state *EvolutionState, limiter *RateLimiter, stop <-chan struct{}) (float64, int, error) {
+	fitness, err := f(chromosome, state)
+	attempts := 1
+	if err == nil || p == nil {
+		return fitness, attempts, err
+	}
+
+	backoff := p.Backoff
+	for retries := 0; retries < p.MaxRetries && !IsPermanentError(err); retries++ {
+		if !wait(backoff, stop) || !limiter.wait(stop) {
+			break
+		}
+
+		fitness, err = f(chromosome, state)
+		attempts++
+		if err == nil {
+			return fitness, attempts, nil
+		}
+
+		backoff = time.Duration(float64(backoff) * math.Max(1.0, p.Multiplier))
+		if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+			backoff = p.MaxBackoff
+		}
+	}
+	return fitness, attempts, err
+}
+
+// failurePolicy returns the policy's failure policy, or the abort policy if the
+// policy is nil.
+func (p *RetryPolicy) failurePolicy() FitnessFailurePolicy {
+	if p == nil {
+		return FitnessFailurePolicyAbort
+	}
+	return p.Failure
+}
+
+// MARK: Private functions
+
+// wait waits for the duration and returns true, or returns false if the stop
+// channel is closed first.
+func wait(duration time.Duration, stop <-chan struct{}) bool {
+	if duration <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-stop:
+		return false
+	}
+}
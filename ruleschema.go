@@ -0,0 +1,267 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// The number of genes that encode each rule of a rule schema: whether the rule
+// is active, its indicator, operator and threshold, and the connective that
+// joins it to the previous rules.
+const genesPerRule = 5
+
+// RuleOperator represents how a rule compares an indicator to its threshold.
+type RuleOperator uint
+
+// Rule operators.
+const (
+	RuleOperatorLessThan     RuleOperator = 0
+	RuleOperatorGreaterThan  RuleOperator = 1
+	RuleOperatorCrossesAbove RuleOperator = 2
+	RuleOperatorCrossesBelow RuleOperator = 3
+)
+
+// RuleConnective represents how a rule is joined to the rules before it.
+type RuleConnective uint
+
+// Rule connectives.
+const (
+	RuleConnectiveAnd RuleConnective = 0
+	RuleConnectiveOr  RuleConnective = 1
+)
+
+// RuleFitnessFunction defines a fitness function that evaluates the strategy
+// decoded from a chromosome, such as by backtesting it.
+type RuleFitnessFunction func(strategy *RuleStrategy, state *EvolutionState) float64
+
+// RuleIndicator objects describe an indicator that rules may compare, such as
+// a moving average or an oscillator, and the range of its thresholds.
+type RuleIndicator struct {
+	Name string
+	Min  float64
+	Max  float64
+}
+
+// RuleSchema objects describe the structure of strategies whose rules are
+// evolved rather than only tuned. Each chromosome encodes a fixed number of
+// rules, each of which may be inactive, and a rule's genes choose its
+// indicator, operator and threshold and how it's joined to the previous rules.
+// Choices are encoded as genes in [0, 1), so configure evolvers with the
+// schema's `Bounds`.
+type RuleSchema struct {
+	// The indicators that rules may compare.
+	Indicators []RuleIndicator
+
+	// The operators that rules may use. If empty, then every operator may be
+	// used.
+	Operators []RuleOperator
+
+	// The maximum number of rules of a strategy.
+	Rules int
+}
+
+// Rule objects compare the value of an indicator to a threshold.
+type Rule struct {
+	// The index of the rule's indicator in the schema.
+	Indicator int
+
+	Operator  RuleOperator
+	Threshold float64
+
+	// How the rule is joined to the rules before it. Ignored by the first rule.
+	Connective RuleConnective
+}
+
+// RuleStrategy objects are executable strategies decoded from chromosomes.
+type RuleStrategy struct {
+	Schema     *RuleSchema
+	Chromosome *Chromosome
+
+	// The strategy's active rules.
+	Rules []Rule
+}
+
+// MARK: Constructors
+
+// NewRuleSchema creates and returns a new rule schema with up to `rules` rules
+// comparing the given indicators with every operator.
+func NewRuleSchema(rules int, indicators ...RuleIndicator) *RuleSchema {
+	return &RuleSchema{
+		Indicators: indicators,
+		Rules:      rules,
+	}
+}
+
+// MARK: Public methods
+
+// GeneCount returns the number of genes of the schema's chromosomes.
+func (s RuleSchema) GeneCount() int {
+	return s.Rules * genesPerRule
+}
+
+// Bounds returns the bounds of the genes of the schema's chromosomes.
+func (s RuleSchema) Bounds() []GeneBounds {
+	return []GeneBounds{{Min: 0.0, Max: 1.0}}
+}
+
+// Population generates a population of uniformly distributed chromosomes.
+func (s RuleSchema) Population(populationSize uint) Population {
+	return GeneratePopulation(populationSize, uint(s.GeneCount()), func(i, j int) float64 {
+		return random.Float64()
+	})
+}
+
+// Decode returns the strategy encoded by the chromosome.
+func (s *RuleSchema) Decode(chromosome *Chromosome) *RuleStrategy {
+	strategy := &RuleStrategy{
+		Schema:     s,
+		Chromosome: chromosome,
+	}
+	if len(s.Indicators) == 0 {
+		return strategy
+	}
+
+	operators := s.operators()
+	for i := 0; i < s.Rules && (i+1)*genesPerRule <= len(chromosome.Genes); i++ {
+		genes := chromosome.Genes[i*genesPerRule : (i+1)*genesPerRule]
+		if genes[0] < 0.5 {
+			continue
+		}
+
+		indicator := choice(genes[1], len(s.Indicators))
+		bounds := s.Indicators[indicator]
+		strategy.Rules = append(strategy.Rules, Rule{
+			Indicator:  indicator,
+			Operator:   operators[choice(genes[2], len(operators))],
+			Threshold:  bounds.Min + math.Max(0.0, math.Min(1.0, genes[3]))*(bounds.Max-bounds.Min),
+			Connective: RuleConnective(choice(genes[4], 2)),
+		})
+	}
+	return strategy
+}
+
+// FitnessFunction returns a fitness function that evaluates the strategy
+// decoded from each chromosome with the given function.
+func (s *RuleSchema) FitnessFunction(f RuleFitnessFunction) FitnessFunction {
+	return func(chromosome *Chromosome, state *EvolutionState) float64 {
+		return f(s.Decode(chromosome), state)
+	}
+}
+
+// Optimizer returns an optimizer that evolves a population of strategies with
+// a copy of the configuration bounded by the schema.
+func (s *RuleSchema) Optimizer(configuration *EvolverConfiguration, f RuleFitnessFunction, populationSize uint, generationsPerCycle int) *Optimizer {
+	bounded := *configuration
+	bounded.Bounds = s.Bounds()
+	return NewOptimizer(NewEvolver(&bounded, s.FitnessFunction(f)), s.Population(populationSize), generationsPerCycle)
+}
+
+// Evaluate returns whether or not the strategy's rules are satisfied by the
+// current value of each of the schema's indicators. The previous values are
+// used by crossing operators, which are never satisfied if `previous` is nil.
+// Rules are joined from first to last, so "a OR b AND c" is "(a OR b) AND c",
+// and a strategy without rules is never satisfied.
+func (s RuleStrategy) Evaluate(current []float64, previous []float64) bool {
+	satisfied := false
+	for i, r := range s.Rules {
+		value := r.Evaluate(current, previous)
+		switch {
+		case i == 0:
+			satisfied = value
+		case r.Connective == RuleConnectiveOr:
+			satisfied = satisfied || value
+		default:
+			satisfied = satisfied && value
+		}
+	}
+	return satisfied
+}
+
+// Evaluate returns whether or not the rule is satisfied by the current and
+// previous values of the schema's indicators.
+func (r Rule) Evaluate(current []float64, previous []float64) bool {
+	if r.Indicator >= len(current) {
+		return false
+	}
+
+	value := current[r.Indicator]
+	switch r.Operator {
+	case RuleOperatorLessThan:
+		return value < r.Threshold
+	case RuleOperatorGreaterThan:
+		return value > r.Threshold
+	case RuleOperatorCrossesAbove:
+		return r.Indicator < len(previous) && previous[r.Indicator] <= r.Threshold && value > r.Threshold
+	case RuleOperatorCrossesBelow:
+		return r.Indicator < len(previous) && previous[r.Indicator] >= r.Threshold && value < r.Threshold
+	default:
+		return false
+	}
+}
+
+// MARK: String methods
+
+func (o RuleOperator) String() string {
+	switch o {
+	case RuleOperatorLessThan:
+		return "<"
+	case RuleOperatorGreaterThan:
+		return ">"
+	case RuleOperatorCrossesAbove:
+		return "crosses above"
+	case RuleOperatorCrossesBelow:
+		return "crosses below"
+	default:
+		return "unknown"
+	}
+}
+
+func (c RuleConnective) String() string {
+	switch c {
+	case RuleConnectiveAnd:
+		return "AND"
+	case RuleConnectiveOr:
+		return "OR"
+	default:
+		return "unknown"
+	}
+}
+
+func (s RuleStrategy) String() string {
+	if len(s.Rules) == 0 {
+		return "never"
+	}
+
+	var b strings.Builder
+	for i, r := range s.Rules {
+		if i > 0 {
+			fmt.Fprintf(&b, " %s ", r.Connective)
+		}
+
+		name := fmt.Sprintf("indicator %d", r.Indicator)
+		if s.Schema != nil && r.Indicator < len(s.Schema.Indicators) {
+			name = s.Schema.Indicators[r.Indicator].Name
+		}
+		fmt.Fprintf(&b, "%s %s %g", name, r.Operator, r.Threshold)
+	}
+	return b.String()
+}
+
+// MARK: Private methods
+
+// operators returns the operators that the schema's rules may use.
+func (s RuleSchema) operators() []RuleOperator {
+	if len(s.Operators) > 0 {
+		return s.Operators
+	}
+	return []RuleOperator{RuleOperatorLessThan, RuleOperatorGreaterThan, RuleOperatorCrossesAbove, RuleOperatorCrossesBelow}
+}
+
+// MARK: Private functions
+
+// choice returns the index of the choice, of `n` choices, encoded by a gene in
+// [0, 1).
+func choice(gene float64, n int) int {
+	return int(math.Max(0.0, math.Min(float64(n-1), math.Floor(gene*float64(n)))))
+}
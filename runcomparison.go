@@ -0,0 +1,208 @@
+package genetics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// ErrInsufficientSamples is returned when a statistical test is given too few
+// samples.
+var ErrInsufficientSamples = errors.New("genetics: insufficient samples")
+
+// RunComparison objects describe a statistical comparison of the results, such
+// as the best fitness, of two sets of independent runs.
+type RunComparison struct {
+	// The Mann-Whitney U statistic of the first set of runs.
+	U float64
+
+	// The two-sided p-value of the Mann-Whitney U test. Small values indicate
+	// that the results of the two sets of runs are unlikely to come from the
+	// same distribution.
+	P float64
+
+	// The Vargha-Delaney A effect size. It's the probability that a run of the
+	// first set has a greater result than a run of the second set, where 0.5
+	// indicates no difference.
+	A float64
+
+	// Cohen's d effect size. It's the difference between the means of the sets
+	// in units of their pooled standard deviation.
+	D float64
+}
+
+// MARK: Public functions
+
+// CompareRuns compares the results of two sets of independent runs with the
+// Mann-Whitney U test and returns the test's result along with effect sizes.
+func CompareRuns(a, b []float64) (RunComparison, error) {
+	u, p, err := MannWhitneyU(a, b)
+	if err != nil {
+		return RunComparison{}, err
+	}
+
+	return RunComparison{
+		U: u,
+		P: p,
+		A: VarghaDelaneyA(a, b),
+		D: CohensD(a, b),
+	}, nil
+}
+
+// MannWhitneyU performs the Mann-Whitney U test on two independent samples and
+// returns the U statistic of the first sample along with the test's two-sided
+// p-value. The p-value is computed from the normal approximation with tie and
+// continuity corrections, so it's approximate for small samples.
+func MannWhitneyU(a, b []float64) (float64, float64, error) {
+	if len(a) == 0 || len(b) == 0 {
+		return 0.0, 1.0, fmt.Errorf("%w: both samples must be non-empty", ErrInsufficientSamples)
+	}
+
+	values := make([]float64, 0, len(a)+len(b))
+	values = append(values, a...)
+	values = append(values, b...)
+	ranks, ties := rank(values)
+
+	n1 := float64(len(a))
+	n2 := float64(len(b))
+	n := n1 + n2
+
+	r1 := 0.0
+	for _, r := range ranks[:len(a)] {
+		r1 += r
+	}
+	u := r1 - n1*(n1+1.0)/2.0
+
+	mean := n1 * n2 / 2.0
+	variance := n1 * n2 / 12.0 * ((n + 1.0) - ties/(n*(n-1.0)))
+	return u, twoSidedPValue(u-mean, variance), nil
+}
+
+// WilcoxonSignedRank performs the Wilcoxon signed-rank test on two paired
+// samples, such as runs of two configurations that share seeds, and returns
+// the sum of the ranks of the positive differences along with the test's
+// two-sided p-value. Zero differences are discarded. The p-value is computed
+// from the normal approximation with tie and continuity corrections, so it's
+// approximate for small samples.
+func WilcoxonSignedRank(a, b []float64) (float64, float64, error) {
+	if len(a) != len(b) {
+		return 0.0, 1.0, fmt.Errorf("paired samples must have the same length, got %d and %d", len(a), len(b))
+	}
+
+	var differences []float64
+	for i := range a {
+		if d := a[i] - b[i]; d != 0.0 {
+			differences = append(differences, d)
+		}
+	}
+	if len(differences) == 0 {
+		return 0.0, 1.0, fmt.Errorf("%w: every paired difference is zero", ErrInsufficientSamples)
+	}
+
+	magnitudes := make([]float64, len(differences))
+	for i, d := range differences {
+		magnitudes[i] = math.Abs(d)
+	}
+	ranks, ties := rank(magnitudes)
+
+	w := 0.0
+	for i, d := range differences {
+		if d > 0.0 {
+			w += ranks[i]
+		}
+	}
+
+	n := float64(len(differences))
+	mean := n * (n + 1.0) / 4.0
+	variance := n*(n+1.0)*(2.0*n+1.0)/24.0 - ties/48.0
+	return w, twoSidedPValue(w-mean, variance), nil
+}
+
+// VarghaDelaneyA returns the Vargha-Delaney A effect size of two samples. It's
+// the probability that a value of the first sample is greater than a value of
+// the second sample, counting ties as half.
+func VarghaDelaneyA(a, b []float64) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0.5
+	}
+
+	wins := 0.0
+	for _, x := range a {
+		for _, y := range b {
+			if x > y {
+				wins++
+			} else if x == y {
+				wins += 0.5
+			}
+		}
+	}
+	return wins / float64(len(a)*len(b))
+}
+
+// CohensD returns Cohen's d effect size of two samples using their pooled
+// standard deviation.
+func CohensD(a, b []float64) float64 {
+	if len(a) < 2 || len(b) < 2 {
+		return 0.0
+	}
+
+	meanA, varianceA := stat.MeanVariance(a, nil)
+	meanB, varianceB := stat.MeanVariance(b, nil)
+	n1 := float64(len(a))
+	n2 := float64(len(b))
+
+	pooled := math.Sqrt(((n1-1.0)*varianceA + (n2-1.0)*varianceB) / (n1 + n2 - 2.0))
+	if pooled == 0.0 {
+		return 0.0
+	}
+	return (meanA - meanB) / pooled
+}
+
+// MARK: Private functions
+
+// rank returns the rank of each value, starting at 1, with tied values given
+// their average rank. It also returns the sum of t³ - t over every group of t
+// tied values, which is used to correct the variance of rank statistics.
+func rank(values []float64) ([]float64, float64) {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return values[order[i]] < values[order[j]]
+	})
+
+	ranks := make([]float64, len(values))
+	ties := 0.0
+	for i := 0; i < len(order); {
+		j := i + 1
+		for j < len(order) && values[order[j]] == values[order[i]] {
+			j++
+		}
+
+		r := float64(i+j+1) / 2.0
+		for _, k := range order[i:j] {
+			ranks[k] = r
+		}
+
+		t := float64(j - i)
+		ties += t*t*t - t
+		i = j
+	}
+	return ranks, ties
+}
+
+// twoSidedPValue returns the two-sided p-value of a statistic's deviation from
+// its mean under the normal approximation with a continuity correction.
+func twoSidedPValue(deviation float64, variance float64) float64 {
+	if variance <= 0.0 {
+		return 1.0
+	}
+
+	z := math.Max(math.Abs(deviation)-0.5, 0.0) / math.Sqrt(variance)
+	return math.Min(2.0*(1.0-distuv.UnitNormal.CDF(z)), 1.0)
+}
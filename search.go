@@ -0,0 +1,81 @@
+package genetics
+
+import "fmt"
+
+// MARK: Public functions
+
+// GridSearch evaluates the chromosomes at every point of a grid with `steps`
+// evenly spaced values of each gene between its bounds, and returns the
+// fittest chromosome along with the number of evaluations. It's a baseline for
+// judging whether evolution improves on an exhaustive search, and requires
+// every gene to be bounded. The number of evaluations is `steps` raised to the
+// power of the chromosome length.
+func GridSearch(configuration *EvolverConfiguration, fitnessFunction FitnessFunction, chromosomeLength int, steps int) (*Chromosome, int, error) {
+	if steps < 1 {
+		return nil, 0, fmt.Errorf("the number of steps %d must be positive", steps)
+	}
+
+	values := make([][]float64, chromosomeLength)
+	for j := range values {
+		bounds, ok := configuration.BoundsForGene(j)
+		if !ok {
+			return nil, 0, fmt.Errorf("gene %d must be bounded", j)
+		}
+
+		values[j] = make([]float64, steps)
+		for k := range values[j] {
+			if steps == 1 {
+				values[j][k] = bounds.Denormalize(0.5)
+			} else {
+				values[j][k] = bounds.Denormalize(float64(k) / float64(steps-1))
+			}
+		}
+	}
+
+	state := &EvolutionState{Configuration: configuration}
+	indexes := make([]int, chromosomeLength)
+	var best *Chromosome
+	evaluations := 0
+	for {
+		c := newChromosome(chromosomeLength, false, nil)
+		for j, k := range indexes {
+			c.Genes[j] = values[j][k]
+		}
+
+		c.Fitness = fitnessFunction(c, state)
+		c.evaluated = true
+		evaluations++
+		if best == nil || c.fitterThan(best) {
+			best = c
+		}
+
+		j := 0
+		for ; j < len(indexes); j++ {
+			if indexes[j]++; indexes[j] < steps {
+				break
+			}
+			indexes[j] = 0
+		}
+		if j == len(indexes) {
+			return best, evaluations, nil
+		}
+	}
+}
+
+// RandomSearch evaluates the given number of chromosomes with genes uniformly
+// distributed within their bounds, and returns the fittest. It's a baseline
+// for judging whether evolution improves on a naive search with the same
+// number of evaluations. Unbounded genes are distributed in [-1, 1].
+func RandomSearch(configuration *EvolverConfiguration, fitnessFunction FitnessFunction, chromosomeLength int, evaluations int) *Chromosome {
+	state := &EvolutionState{Configuration: configuration}
+	var best *Chromosome
+	for i := 0; i < evaluations; i++ {
+		c := configuration.randomChromosome(chromosomeLength)
+		c.Fitness = fitnessFunction(c, state)
+		c.evaluated = true
+		if best == nil || c.fitterThan(best) {
+			best = c
+		}
+	}
+	return best
+}
@@ -1,8 +1,12 @@
 package genetics
 
 import (
+	"fmt"
+	"math"
 	"math/rand"
 	"sort"
+	"strings"
+	"sync"
 )
 
 // SelectionMethodType represents a type of selection method.
@@ -14,16 +18,82 @@ const (
 	SelectionMethodTypeRoulette   SelectionMethodType = 1
 	SelectionMethodTypeTournament SelectionMethodType = 2
 	SelectionMethodTypeCustom     SelectionMethodType = 3
+	SelectionMethodTypeLexicase   SelectionMethodType = 4
 )
 
 // SelectionMethodFunction takes a population of chromosomes and chooses one for
 // breeding.
-type SelectionMethodFunction func(population Population) *Chromosome
+type SelectionMethodFunction func(population Population, context SelectionContext) *Chromosome
+
+// SelectionContext objects describe the evolution that a selection function is
+// choosing chromosomes for, so that custom selections can implement scaling or
+// annealing schedules.
+type SelectionContext struct {
+	// The generation being bred.
+	Generation int
+
+	// The statistics of the population being selected from.
+	Stats GenerationStats
+
+	// An optional source of random numbers. If nil, then the package's source
+	// is used. See `Source`.
+	Random *rand.Rand
+
+	// Whether or not greater fitness is better. Evolvers always maximize
+	// fitness, so this is true for every context they create.
+	Maximize bool
+
+	// The comparator that orders chromosomes for rank and tournament
+	// selection. If nil, then chromosomes are compared by fitness.
+	Comparator Comparator
+}
+
+// tournament contains the chromosomes of a tournament. Tournaments are pooled
+// so that they can be held without allocating.
+type tournament struct {
+	// Whether or not each chromosome in the population is in the tournament.
+	entered []bool
+
+	// The indexes of the chromosomes in the tournament.
+	indexes []int
+}
+
+// tournamentPool contains tournaments that can be reused.
+var tournamentPool = sync.Pool{
+	New: func() interface{} {
+		return &tournament{}
+	},
+}
+
+// selectionWeightsFunction returns the weight of each chromosome in a
+// population for selection methods that select in proportion to weight.
+type selectionWeightsFunction func(population Population, context SelectionContext) []float64
 
 // SelectionMethod wraps a method type and function together.
 type SelectionMethod struct {
 	Type     SelectionMethodType
 	Function SelectionMethodFunction
+
+	// The weights that `Function` selects in proportion to, if any. Internal use
+	// only.
+	weights selectionWeightsFunction
+}
+
+// MARK: String methods
+
+func (t SelectionMethodType) String() string {
+	switch t {
+	case SelectionMethodTypeRank:
+		return "rank"
+	case SelectionMethodTypeRoulette:
+		return "roulette"
+	case SelectionMethodTypeTournament:
+		return "tournament"
+	case SelectionMethodTypeLexicase:
+		return "lexicase"
+	default:
+		return "custom"
+	}
 }
 
 // MARK: Constructors
@@ -35,6 +105,7 @@ func NewSelectionMethod(t SelectionMethodType) *SelectionMethod {
 	return &SelectionMethod{
 		Type:     t,
 		Function: selectionFunctionForType(t),
+		weights:  selectionWeightsFunctionForType(t),
 	}
 }
 
@@ -47,69 +118,306 @@ func NewCustomSelectionMethod(f SelectionMethodFunction) *SelectionMethod {
 	}
 }
 
-// MARK: Public functions
+// NewTournamentSelectionMethod creates a new tournament selection method that
+// selects the fittest of `size` randomly chosen chromosomes.
+func NewTournamentSelectionMethod(size int) *SelectionMethod {
+	return &SelectionMethod{
+		Type:     SelectionMethodTypeTournament,
+		Function: tournamentFunctionWithSize(size),
+	}
+}
 
-// RankFunction implements the rank selection function.
-var RankFunction SelectionMethodFunction = func(population Population) *Chromosome {
-	for i := 0; i < len(population); i++ {
-		population[i].weight = float64(i) + 1.0
+// ParseSelectionMethod creates a new selection method from a spec of the form
+// "name" or "name:parameter". Valid specs are "rank", "roulette", "tournament",
+// "tournament:size", "lexicase" and the names of selections registered with
+// `RegisterSelection`.
+func ParseSelectionMethod(spec string) (*SelectionMethod, error) {
+	name, parameter, err := parseMethodSpec(spec)
+	if err != nil {
+		return nil, err
 	}
 
-	total := population.SumWeights()
-	rand := float64(rand.Intn(int(total)))
-	sum := 0.0
+	t, ok := selectionMethodTypeForName(name)
+	if !ok {
+		f, ok := registeredSelection(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown selection method %q", spec)
+		}
+		if parameter > 0 {
+			return nil, fmt.Errorf("selection method %q doesn't take a parameter", name)
+		}
+		return NewCustomSelectionMethod(f), nil
+	}
 
-	for _, c := range population {
-		sum += c.weight
-		if rand < sum {
-			return c
+	if parameter > 0 {
+		if t != SelectionMethodTypeTournament {
+			return nil, fmt.Errorf("selection method %q doesn't take a parameter", name)
 		}
+		return NewTournamentSelectionMethod(parameter), nil
 	}
 
-	return &Chromosome{}
+	return NewSelectionMethod(t), nil
 }
 
-// RouletteFunction implements the roulette selection function.
-var RouletteFunction SelectionMethodFunction = func(population Population) *Chromosome {
-	sort.Slice(population[:], func(i, j int) bool {
-		return population[i].Fitness > population[j].Fitness
-	})
+// MARK: Public functions
 
-	total := population.SumWeights()
-	for i := 0; i < len(population); i++ {
-		population[i].weight = population[i].weight / total
+// RankFunction implements the rank selection function. Chromosomes are ranked
+// by fitness and selected with a probability proportional to their rank, where
+// the least fit chromosome has a rank of one. NaN fitness ranks below every
+// other fitness, and chromosomes with equal fitness are ranked by their genes
+// so that ranks don't depend on the order of the population. If the context has
+// a comparator, then chromosomes are ranked by it instead. The population's
+// order is not modified.
+var RankFunction SelectionMethodFunction = func(population Population, context SelectionContext) *Chromosome {
+	return selectWeighted(population, rankWeights(population, context), context.Source())
+}
+
+// RouletteFunction implements the roulette selection function. Chromosomes are
+// selected with a probability proportional to their weight, which is their
+// fitness unless fitness scaling is used. Weights are normalized as follows:
+//
+//   - Chromosomes with a NaN or -Inf weight are never selected.
+//   - If any weight is +Inf, then one of those chromosomes is selected
+//     uniformly.
+//   - If any weight is negative, then every weight is shifted so that the
+//     minimum weight is zero.
+//   - If every weight is zero after normalization, then a chromosome is
+//     selected uniformly.
+//
+// The population's order and weights are not modified.
+var RouletteFunction SelectionMethodFunction = func(population Population, context SelectionContext) *Chromosome {
+	return selectWeighted(population, rouletteWeights(population, context), context.Source())
+}
+
+// TournamentFunction implements the tournament selection function. A
+// tournament of a random number of distinct chromosomes, fewer than the size
+// of the population, is held and the chromosome with the greatest weight is
+// selected. If the context has a comparator, then the fittest chromosome by the
+// comparator is selected instead. A population of one chromosome always
+// selects it. The population's order is not modified.
+var TournamentFunction SelectionMethodFunction = func(population Population, context SelectionContext) *Chromosome {
+	if len(population) < 2 {
+		return population[0]
 	}
 
-	count := population.CountNegativeWeights()
-	if count > 0 {
-		// log.Warnf("Population contains %d chromosomes with fitness < 0.0 which may result in bad roulette selection.", count)
-		min := population.MinWeight()
-		population.ShiftWeights(min)
+	rng := context.Source()
+	size := rng.Intn(len(population)-1) + 1
+
+	t := tournamentPool.Get().(*tournament)
+	t.enter(len(population), size, rng)
+
+	best := population[t.indexes[0]]
+	for _, i := range t.indexes[1:] {
+		c := population[i]
+		if context.Comparator != nil {
+			if context.Comparator.Compare(c, best) > 0 {
+				best = c
+			}
+		} else if c.weight > best.weight {
+			best = c
+		}
 	}
 
-	rand := rand.Float64()
-	sum := 0.0
+	t.clear()
+	tournamentPool.Put(t)
+	return best
+}
+
+// LexicaseFunction implements the lexicase selection function for chromosomes
+// evaluated by a fitness function from `NewCaseFitnessFunction`. The fitness
+// cases are shuffled, and in turn each case removes every candidate whose
+// error on the case is greater than the lowest error of the remaining
+// candidates. A remaining candidate is selected uniformly once the cases are
+// exhausted or only one candidate remains. Missing case errors are treated as
+// infinite. The population's order is not modified.
+var LexicaseFunction SelectionMethodFunction = func(population Population, context SelectionContext) *Chromosome {
+	rng := context.Source()
+	cases := 0
+	candidates := make([]*Chromosome, len(population))
+	for i, c := range population {
+		candidates[i] = c
+		if len(c.CaseErrors) > cases {
+			cases = len(c.CaseErrors)
+		}
+	}
+
+	for _, i := range rng.Perm(cases) {
+		if len(candidates) <= 1 {
+			break
+		}
 
-	for _, c := range population {
-		sum += c.weight
-		if rand < sum {
-			return c
+		best := math.Inf(1)
+		for _, c := range candidates {
+			best = math.Min(best, caseError(c, i))
 		}
+
+		remaining := candidates[:0]
+		for _, c := range candidates {
+			if caseError(c, i) <= best {
+				remaining = append(remaining, c)
+			}
+		}
+		candidates = remaining
 	}
 
-	return population[0]
+	if len(candidates) == 0 {
+		return nil
+	}
+	return candidates[rng.Intn(len(candidates))]
 }
 
-// TournamentFunction implements the tournament selection function.
-var TournamentFunction SelectionMethodFunction = func(population Population) *Chromosome {
-	population.ShuffleChromosomes()
-	rand := rand.Intn(len(population)-1) + 1
-	tournamentGroup := population[0:rand]
-	return tournamentGroup.ChromosomeWithMaxWeight()
+// Source returns the context's source of random numbers, or the package's
+// source if the context doesn't have one. Selection functions should draw
+// random numbers from it.
+func (c SelectionContext) Source() *rand.Rand {
+	if c.Random != nil {
+		return c.Random
+	}
+	return random
+}
+
+// MARK: Private methods
+
+// selector returns a function that selects chromosomes from the population
+// using the selection method. The weights of methods that select in proportion
+// to weight are computed once and stored in the table, so the returned function
+// must not be used once the population's weights change. The returned function
+// is bound to the table, so it selects from the population of the table's
+// most recent selector.
+func (m SelectionMethod) selector(population Population, context SelectionContext, table *weightTable) func() *Chromosome {
+	table.context = context
+	if m.weights == nil {
+		table.function = m.Function
+		table.population = population
+	} else {
+		table.function = nil
+		table.population = nil
+		table.reset(population, m.weights(population, context))
+	}
+
+	if table.selector == nil {
+		table.selector = table.selectChromosome
+	}
+	return table.selector
+}
+
+// enter enters size distinct chromosomes, chosen uniformly from a population
+// of n chromosomes, in to the tournament using Floyd's algorithm.
+func (t *tournament) enter(n int, size int, rng *rand.Rand) {
+	if len(t.entered) < n {
+		t.entered = make([]bool, n)
+	}
+
+	for j := n - size; j < n; j++ {
+		i := rng.Intn(j + 1)
+		if t.entered[i] {
+			i = j
+		}
+		t.entered[i] = true
+		t.indexes = append(t.indexes, i)
+	}
+}
+
+// clear removes every chromosome from the tournament.
+func (t *tournament) clear() {
+	for _, i := range t.indexes {
+		t.entered[i] = false
+	}
+	t.indexes = t.indexes[:0]
 }
 
 // MARK: Private functions
 
+// rankWeights returns the rank of each chromosome in the population by the
+// context's comparator, or by fitness if it doesn't have one.
+func rankWeights(population Population, context SelectionContext) []float64 {
+	indexes := make([]int, len(population))
+	for i := range indexes {
+		indexes[i] = i
+	}
+
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return fitter(population[indexes[j]], population[indexes[i]], context.Comparator)
+	})
+
+	weights := make([]float64, len(population))
+	for rank, i := range indexes {
+		weights[i] = float64(rank) + 1.0
+	}
+	return weights
+}
+
+// rouletteWeights returns the normalized weight of each chromosome in the
+// population as described by `RouletteFunction`.
+func rouletteWeights(population Population, context SelectionContext) []float64 {
+	weights := make([]float64, len(population))
+	min := math.Inf(1)
+	infinite := false
+	for i, c := range population {
+		weights[i] = c.weight
+		if math.IsNaN(c.weight) || math.IsInf(c.weight, -1) {
+			continue
+		}
+		min = math.Min(min, c.weight)
+		infinite = infinite || math.IsInf(c.weight, 1)
+	}
+
+	for i, w := range weights {
+		switch {
+		case math.IsNaN(w) || math.IsInf(w, -1):
+			weights[i] = 0.0
+		case infinite:
+			if math.IsInf(w, 1) {
+				weights[i] = 1.0
+			} else {
+				weights[i] = 0.0
+			}
+		case min < 0.0:
+			weights[i] = w - min
+		}
+	}
+	return weights
+}
+
+// selectWeighted selects a chromosome from the population with a probability
+// proportional to its non-negative weight. If every weight is zero, then a
+// chromosome is selected uniformly. Random numbers are drawn from the given
+// source. Returns nil if the population is empty.
+func selectWeighted(population Population, weights []float64, rng *rand.Rand) *Chromosome {
+	table := weightTable{}
+	table.reset(population, weights)
+	return table.sample(rng)
+}
+
+// caseError returns the chromosome's error on case `i`. Missing and NaN
+// errors are infinite.
+func caseError(c *Chromosome, i int) float64 {
+	if i >= len(c.CaseErrors) || math.IsNaN(c.CaseErrors[i]) {
+		return math.Inf(1)
+	}
+	return c.CaseErrors[i]
+}
+
+// tournamentFunctionWithSize returns a tournament selection function that
+// selects the fittest of `size` randomly chosen chromosomes.
+func tournamentFunctionWithSize(size int) SelectionMethodFunction {
+	return func(population Population, context SelectionContext) *Chromosome {
+		rng := context.Source()
+		best := population[rng.Intn(len(population))]
+		for i := 1; i < size; i++ {
+			c := population[rng.Intn(len(population))]
+			if context.Comparator != nil {
+				if context.Comparator.Compare(c, best) > 0 {
+					best = c
+				}
+			} else if c.weight > best.weight {
+				best = c
+			}
+		}
+		return best
+	}
+}
+
 // selectionFunctionForType returns the selection function for the given type.
 func selectionFunctionForType(t SelectionMethodType) SelectionMethodFunction {
 	switch t {
@@ -119,7 +427,39 @@ func selectionFunctionForType(t SelectionMethodType) SelectionMethodFunction {
 		return RouletteFunction
 	case SelectionMethodTypeTournament:
 		return TournamentFunction
+	case SelectionMethodTypeLexicase:
+		return LexicaseFunction
 	default:
 		return nil
 	}
 }
+
+// selectionWeightsFunctionForType returns the selection weights function for
+// the given type, or nil if the type doesn't select in proportion to weight.
+func selectionWeightsFunctionForType(t SelectionMethodType) selectionWeightsFunction {
+	switch t {
+	case SelectionMethodTypeRank:
+		return rankWeights
+	case SelectionMethodTypeRoulette:
+		return rouletteWeights
+	default:
+		return nil
+	}
+}
+
+// selectionMethodTypeForName returns the selection method type with the given
+// case-insensitive name.
+func selectionMethodTypeForName(name string) (SelectionMethodType, bool) {
+	switch strings.ToLower(name) {
+	case "rank":
+		return SelectionMethodTypeRank, true
+	case "roulette":
+		return SelectionMethodTypeRoulette, true
+	case "tournament":
+		return SelectionMethodTypeTournament, true
+	case "lexicase":
+		return SelectionMethodTypeLexicase, true
+	default:
+		return SelectionMethodTypeCustom, false
+	}
+}
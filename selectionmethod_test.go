@@ -0,0 +1,135 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestSelectionMethodTypeValues(t *testing.T) {
+	// The numeric values of types are persisted, so they must never change.
+	types := []SelectionMethodType{
+		SelectionMethodTypeRank,
+		SelectionMethodTypeRoulette,
+		SelectionMethodTypeTournament,
+		SelectionMethodTypeCustom,
+		SelectionMethodTypeLexicase,
+	}
+	for i, ty := range types {
+		if int(ty) != i {
+			t.Errorf("%s has value %d, expected %d", ty, ty, i)
+		}
+	}
+}
+
+func TestWeightedSelectionDrawsFromContext(t *testing.T) {
+	population := benchmarkPopulation(10, 2)
+	s := NewRandomSource(1)
+	SetRandomSource(s)
+	defer SetRandomSource(nil)
+
+	for _, ty := range []SelectionMethodType{SelectionMethodTypeRank, SelectionMethodTypeRoulette} {
+		method := NewSelectionMethod(ty)
+		context := SelectionContext{Random: rand.New(NewRandomSource(2))}
+		method.Function(population, context)
+		method.selector(population, context, &weightTable{})()
+
+		if s.state != 1 {
+			t.Errorf("%s selection drew from the package's random source", ty)
+		}
+	}
+}
+
+func TestTournamentFunctionSingleChromosome(t *testing.T) {
+	c := &Chromosome{}
+	if selected := TournamentFunction(Population{c}, SelectionContext{}); selected != c {
+		t.Errorf("selected %v, expected the only chromosome", selected)
+	}
+}
+
+func TestTournamentFunctionProbabilities(t *testing.T) {
+	SetRandomSource(NewRandomSource(1))
+	defer SetRandomSource(nil)
+
+	population := Population{{weight: 0.0}, {weight: 1.0}, {weight: 2.0}}
+
+	// Tournaments of one chromosome select each chromosome with a probability
+	// of 1/3, and tournaments of two select the fitter of each pair.
+	expected := []float64{1.0 / 6.0, 1.0 / 3.0, 1.0 / 2.0}
+
+	const trials = 30000
+	counts := make([]int, len(population))
+	for trial := 0; trial < trials; trial++ {
+		counts[int(TournamentFunction(population, SelectionContext{}).weight)]++
+	}
+
+	for i, count := range counts {
+		if p := float64(count) / trials; math.Abs(p-expected[i]) > 0.01 {
+			t.Errorf("selected chromosome %d with probability %f, expected %f", i, p, expected[i])
+		}
+	}
+}
+
+func TestTournamentEnterUniform(t *testing.T) {
+	SetRandomSource(NewRandomSource(1))
+	defer SetRandomSource(nil)
+
+	const trials = 30000
+	counts := make(map[string]int)
+	tournament := &tournament{}
+	for trial := 0; trial < trials; trial++ {
+		tournament.enter(4, 2, Random())
+		indexes := append([]int(nil), tournament.indexes...)
+		sort.Ints(indexes)
+		if indexes[0] == indexes[1] {
+			t.Fatalf("entered chromosome %d twice", indexes[0])
+		}
+		counts[fmt.Sprint(indexes)]++
+		tournament.clear()
+	}
+
+	// There are six ways to choose two of four chromosomes.
+	if len(counts) != 6 {
+		t.Fatalf("held %d tournaments, expected 6", len(counts))
+	}
+	for indexes, count := range counts {
+		if count < trials/6*9/10 || count > trials/6*11/10 {
+			t.Errorf("tournament %s held %d times, expected about %d", indexes, count, trials/6)
+		}
+	}
+}
+
+func TestTournamentFunctionDoesNotAllocate(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocations aren't counted with the race detector enabled")
+	}
+
+	population := benchmarkPopulation(100, 10)
+	TournamentFunction(population, SelectionContext{})
+
+	if allocs := testing.AllocsPerRun(100, func() {
+		TournamentFunction(population, SelectionContext{})
+	}); allocs >= 1 {
+		t.Errorf("allocated %f times per selection", allocs)
+	}
+}
+
+func BenchmarkSelection(b *testing.B) {
+	for _, t := range []SelectionMethodType{SelectionMethodTypeRank, SelectionMethodTypeRoulette, SelectionMethodTypeTournament} {
+		for _, size := range benchmarkPopulationSizes {
+			for _, length := range benchmarkChromosomeLengths {
+				b.Run(fmt.Sprintf("%s/%dx%d", t, size, length), func(b *testing.B) {
+					method := NewSelectionMethod(t)
+					population := benchmarkPopulation(size, length)
+					b.ReportAllocs()
+					b.ResetTimer()
+					for i := 0; i < b.N; i++ {
+						method.Function(population, SelectionContext{})
+					}
+				})
+			}
+		}
+	}
+}
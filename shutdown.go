@@ -0,0 +1,48 @@
+package genetics
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// MARK: Public functions
+
+// StopOnSignal returns a channel that is closed when the process receives one
+// of the given signals, such as `os.Interrupt`, and a function that stops
+// waiting for the signals. Assign the channel to an evolver's `Stop` channel so
+// that interrupting a long evolution finishes the current generation and
+// returns the best chromosome so far, and call the function once the evolution
+// returns so that the signals aren't waited for the life of the process. If no
+// signals are given, then `os.Interrupt` is waited for.
+//
+// Only the first signal is handled. Unless other channels are notified of the
+// signals, their default behaviour is then restored so that a second signal
+// terminates the process. Cancelling doesn't close the channel.
+func StopOnSignal(signals ...os.Signal) (<-chan struct{}, func()) {
+	if len(signals) == 0 {
+		signals = []os.Signal{os.Interrupt}
+	}
+
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, signals...)
+
+	stop := make(chan struct{})
+	cancelled := make(chan struct{})
+	go func() {
+		defer signal.Stop(received)
+		select {
+		case <-received:
+			close(stop)
+		case <-cancelled:
+		}
+	}()
+
+	once := sync.Once{}
+	return stop, func() {
+		once.Do(func() {
+			signal.Stop(received)
+			close(cancelled)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+//go:build !windows
+// +build !windows
+
+package genetics
+
+import (
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestStopOnSignal(t *testing.T) {
+	stop, cancel := StopOnSignal(syscall.SIGUSR1)
+	defer cancel()
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-stop:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the stop channel wasn't closed")
+	}
+}
+
+func TestStopOnSignalDefaultsToInterrupt(t *testing.T) {
+	stop, cancel := StopOnSignal()
+	defer cancel()
+
+	// The runtime uses SIGURG to preempt goroutines, so it must not stop an
+	// evolution.
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGURG); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-stop:
+		t.Fatal("the stop channel was closed by SIGURG")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestStopOnSignalCancel(t *testing.T) {
+	goroutines := runtime.NumGoroutine()
+	stop, cancel := StopOnSignal(syscall.SIGUSR2)
+	cancel()
+	cancel()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > goroutines && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > goroutines {
+		t.Errorf("%d goroutines are running after cancelling, expected %d", n, goroutines)
+	}
+
+	select {
+	case <-stop:
+		t.Error("cancelling closed the stop channel")
+	default:
+	}
+}
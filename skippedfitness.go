@@ -0,0 +1,79 @@
+package genetics
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// SkippedFitnessPolicy represents how the fitness of chromosomes that are
+// skipped, rather than evaluated, is assigned. Chromosomes are skipped when an
+// evolver's evaluation budget is spent or a generation's evaluation exceeds
+// its timeout. Skipped chromosomes are flagged as estimated, and are evaluated
+// if they survive to the next generation.
+type SkippedFitnessPolicy uint
+
+// Skipped fitness policies.
+const (
+	// Skipped chromosomes are given the worst evaluated fitness in the
+	// population as a penalty.
+	SkippedFitnessPolicyWorst SkippedFitnessPolicy = 0
+
+	// Skipped chromosomes carry forward the fitness of their fittest parent.
+	// Chromosomes without parents are given the worst fitness. Because inherited
+	// fitness is optimistic, skipped chromosomes may outrank evaluated ones, so
+	// check `Chromosome.Estimated` before relying on the best chromosome.
+	SkippedFitnessPolicyInherit SkippedFitnessPolicy = 1
+)
+
+// MARK: String methods
+
+func (p SkippedFitnessPolicy) String() string {
+	switch p {
+	case SkippedFitnessPolicyWorst:
+		return "worst"
+	case SkippedFitnessPolicyInherit:
+		return "inherit"
+	default:
+		return "unknown"
+	}
+}
+
+// MARK: Public functions
+
+// ParseSkippedFitnessPolicy returns the skipped fitness policy with the given
+// name. Valid names are "worst" and "inherit". An empty name is the worst
+// policy.
+func ParseSkippedFitnessPolicy(name string) (SkippedFitnessPolicy, error) {
+	switch strings.ToLower(name) {
+	case "", "worst":
+		return SkippedFitnessPolicyWorst, nil
+	case "inherit":
+		return SkippedFitnessPolicyInherit, nil
+	default:
+		return SkippedFitnessPolicyWorst, fmt.Errorf("unknown skipped fitness policy %q", name)
+	}
+}
+
+// MARK: Private methods
+
+// assign assigns the fitness of the skipped chromosomes. Skipped chromosomes'
+// fitness must be NaN so that it's ignored when finding the population's worst
+// fitness.
+func (p SkippedFitnessPolicy) assign(population Population, skipped []*Chromosome) {
+	if len(skipped) == 0 {
+		return
+	}
+
+	worst := worstValidFitness(population)
+	for _, c := range skipped {
+		c.Fitness = worst
+		if p == SkippedFitnessPolicyInherit && c.bred.pending && c.bred.parentFitness > -math.MaxFloat64 {
+			c.Fitness = c.bred.parentFitness
+		}
+
+		c.weight = c.Fitness
+		c.evaluated = false
+		c.estimated = true
+	}
+}
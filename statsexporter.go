@@ -0,0 +1,123 @@
+package genetics
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// StatsFormat represents a format that generation statistics are exported in.
+type StatsFormat uint
+
+// Formats of exported statistics.
+const (
+	StatsFormatCSV       StatsFormat = 0
+	StatsFormatJSONLines StatsFormat = 1
+)
+
+// StatsExporter types write generation statistics to a writer.
+type StatsExporter struct {
+	Format StatsFormat
+
+	writer      io.Writer
+	csvWriter   *csv.Writer
+	wroteHeader bool
+}
+
+// MARK: Constructors
+
+// NewStatsExporter creates and returns a new statistics exporter that writes to
+// the given writer in the given format.
+func NewStatsExporter(w io.Writer, format StatsFormat) *StatsExporter {
+	return &StatsExporter{
+		Format:    format,
+		writer:    w,
+		csvWriter: csv.NewWriter(w),
+	}
+}
+
+// MARK: Public methods
+
+// Export writes the statistics of a single generation. When exporting CSV, a
+// header row is written before the first generation's statistics. Elapsed and
+// evaluation times are written in seconds. The fitness components of the best
+// chromosome are only exported as JSON lines.
+func (x *StatsExporter) Export(stats GenerationStats) error {
+	switch x.Format {
+	case StatsFormatJSONLines:
+		return x.exportJSONLine(stats)
+	default:
+		return x.exportCSVRow(stats)
+	}
+}
+
+// ExportAll writes the statistics of each generation of an evolution.
+func (x *StatsExporter) ExportAll(stats EvolutionStats) error {
+	for _, s := range stats {
+		if err := x.Export(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MARK: Private methods
+
+// exportCSVRow writes the statistics as a CSV row.
+func (x *StatsExporter) exportCSVRow(stats GenerationStats) error {
+	if !x.wroteHeader {
+		if err := x.csvWriter.Write([]string{"generation", "best", "mean", "worst", "diversity", "elapsed", "invalid_fitnesses", "evaluations", "evaluation_time", "slowest_evaluation"}); err != nil {
+			return err
+		}
+		x.wroteHeader = true
+	}
+
+	err := x.csvWriter.Write([]string{
+		strconv.Itoa(stats.Generation),
+		strconv.FormatFloat(stats.Best, 'g', -1, 64),
+		strconv.FormatFloat(stats.Mean, 'g', -1, 64),
+		strconv.FormatFloat(stats.Worst, 'g', -1, 64),
+		strconv.FormatFloat(stats.Diversity, 'g', -1, 64),
+		strconv.FormatFloat(stats.Elapsed.Seconds(), 'g', -1, 64),
+		strconv.Itoa(stats.InvalidFitnesses),
+		strconv.Itoa(stats.Evaluations),
+		strconv.FormatFloat(stats.EvaluationTime.Seconds(), 'g', -1, 64),
+		strconv.FormatFloat(stats.SlowestEvaluation.Seconds(), 'g', -1, 64),
+	})
+	if err != nil {
+		return err
+	}
+
+	x.csvWriter.Flush()
+	return x.csvWriter.Error()
+}
+
+// exportJSONLine writes the statistics as a single line of JSON.
+func (x *StatsExporter) exportJSONLine(stats GenerationStats) error {
+	return json.NewEncoder(x.writer).Encode(struct {
+		Generation int                `json:"generation"`
+		Best       float64            `json:"best"`
+		Components map[string]float64 `json:"best_components,omitempty"`
+		Mean       float64            `json:"mean"`
+		Worst      float64            `json:"worst"`
+		Diversity  float64            `json:"diversity"`
+		Elapsed    float64            `json:"elapsed"`
+		Invalid    int                `json:"invalid_fitnesses"`
+		Evaluation int                `json:"evaluations"`
+		Evaluating float64            `json:"evaluation_time"`
+		Slowest    float64            `json:"slowest_evaluation"`
+	}{
+		Generation: stats.Generation,
+		Best:       stats.Best,
+		Components: stats.BestComponents,
+		Mean:       stats.Mean,
+		Worst:      stats.Worst,
+		Diversity:  stats.Diversity,
+		Elapsed:    stats.Elapsed.Seconds(),
+		Invalid:    stats.InvalidFitnesses,
+		Evaluation: stats.Evaluations,
+		Evaluating: stats.EvaluationTime.Seconds(),
+		Slowest:    stats.SlowestEvaluation.Seconds(),
+	})
+}
@@ -0,0 +1,92 @@
+package genetics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// exporterStats returns the statistics of two generations used to test the
+// stats exporter.
+func exporterStats() EvolutionStats {
+	return EvolutionStats{
+		{
+			Generation:        0,
+			Best:              2.5,
+			Mean:              1.0,
+			Worst:             -0.5,
+			Diversity:         0.25,
+			Elapsed:           1500 * time.Millisecond,
+			Evaluations:       10,
+			EvaluationTime:    time.Second,
+			SlowestEvaluation: 250 * time.Millisecond,
+		},
+		{
+			Generation:       1,
+			Best:             3.0,
+			BestComponents:   map[string]float64{"cost": -1.0},
+			Mean:             2.0,
+			Worst:            1.0,
+			Elapsed:          3 * time.Second,
+			InvalidFitnesses: 2,
+			Evaluations:      20,
+		},
+	}
+}
+
+func TestStatsExporter(t *testing.T) {
+	tests := []struct {
+		format   StatsFormat
+		expected []string
+	}{
+		{
+			StatsFormatCSV,
+			[]string{
+				"generation,best,mean,worst,diversity,elapsed,invalid_fitnesses,evaluations,evaluation_time,slowest_evaluation",
+				"0,2.5,1,-0.5,0.25,1.5,0,10,1,0.25",
+				"1,3,2,1,0,3,2,20,0,0",
+			},
+		},
+		{
+			StatsFormatJSONLines,
+			[]string{
+				`{"generation":0,"best":2.5,"mean":1,"worst":-0.5,"diversity":0.25,"elapsed":1.5,"invalid_fitnesses":0,"evaluations":10,"evaluation_time":1,"slowest_evaluation":0.25}`,
+				`{"generation":1,"best":3,"best_components":{"cost":-1},"mean":2,"worst":1,"diversity":0,"elapsed":3,"invalid_fitnesses":2,"evaluations":20,"evaluation_time":0,"slowest_evaluation":0}`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		var buffer bytes.Buffer
+		if err := NewStatsExporter(&buffer, test.format).ExportAll(exporterStats()); err != nil {
+			t.Errorf("format %d: error exporting statistics: %s", test.format, err)
+			continue
+		}
+
+		lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+		if len(lines) != len(test.expected) {
+			t.Errorf("format %d: exported %d lines, expected %d", test.format, len(lines), len(test.expected))
+			continue
+		}
+		for i, line := range lines {
+			if line != test.expected[i] {
+				t.Errorf("format %d: line %d is %q, expected %q", test.format, i, line, test.expected[i])
+			}
+		}
+	}
+}
+
+func TestStatsExporterWritesCSVHeaderOnce(t *testing.T) {
+	var buffer bytes.Buffer
+	exporter := NewStatsExporter(&buffer, StatsFormatCSV)
+	for _, stats := range exporterStats() {
+		if err := exporter.Export(stats); err != nil {
+			t.Fatalf("error exporting statistics: %s", err)
+		}
+	}
+
+	if headers := strings.Count(buffer.String(), "generation,"); headers != 1 {
+		t.Errorf("wrote %d header rows, expected 1", headers)
+	}
+}
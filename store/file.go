@@ -0,0 +1,96 @@
+package store
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	genetics "github.com/colinc86/go-genetics"
+)
+
+// File types store checkpoints as files in a directory. Each checkpoint is
+// written to a temporary file that then replaces the checkpoint's file, so an
+// interrupted save never leaves a partially written checkpoint.
+type File struct {
+	// The directory that checkpoints are stored in. It's created when the first
+	// checkpoint is saved.
+	Directory string
+}
+
+// MARK: Constructors
+
+// NewFile creates and returns a new store of checkpoints in the directory.
+func NewFile(directory string) *File {
+	return &File{Directory: directory}
+}
+
+// MARK: Public methods
+
+// Save saves the checkpoint to the file `name.pb` in the store's directory.
+func (s File) Save(name string, checkpoint *genetics.Checkpoint) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+
+	data, err := encode(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(s.Directory, 0755); err != nil {
+		return err
+	}
+
+	temporary, err := ioutil.TempFile(s.Directory, "."+name+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temporary.Name())
+
+	if _, err = temporary.Write(data); err != nil {
+		temporary.Close()
+		return err
+	}
+	if err = temporary.Sync(); err != nil {
+		temporary.Close()
+		return err
+	}
+	if err = temporary.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temporary.Name(), s.path(name))
+}
+
+// Load loads the checkpoint from the file `name.pb` in the store's directory.
+func (s File) Load(name string) (*genetics.Checkpoint, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(s.path(name))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return decode(data)
+}
+
+// Delete deletes the file `name.pb` from the store's directory.
+func (s File) Delete(name string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+
+	if err := os.Remove(s.path(name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// MARK: Private methods
+
+// path returns the path of the named checkpoint's file.
+func (s File) path(name string) string {
+	return filepath.Join(s.Directory, name+".pb")
+}
@@ -0,0 +1,236 @@
+package store
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	genetics "github.com/colinc86/go-genetics"
+)
+
+// S3 types store checkpoints as objects in a bucket of an S3-compatible object
+// storage service, such as Amazon S3 or MinIO. Requests are signed with AWS
+// signature version 4 and address buckets by path.
+type S3 struct {
+	// The URL of the service, such as "https://s3.us-east-1.amazonaws.com".
+	Endpoint string
+
+	// The region of the bucket, such as "us-east-1".
+	Region string
+
+	// The bucket that checkpoints are stored in.
+	Bucket string
+
+	// An optional prefix of the keys of the checkpoints' objects, such as
+	// "checkpoints/".
+	Prefix string
+
+	// The credentials used to sign requests. The session token is only
+	// required for temporary credentials.
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+
+	// The client used to send requests. If nil, then `http.DefaultClient` is
+	// used.
+	Client *http.Client
+}
+
+// MARK: Constructors
+
+// NewS3 creates and returns a new store of checkpoints in the bucket of the
+// S3-compatible service at the endpoint.
+func NewS3(endpoint string, region string, bucket string, accessKeyID string, secretAccessKey string) *S3 {
+	return &S3{
+		Endpoint:        endpoint,
+		Region:          region,
+		Bucket:          bucket,
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+	}
+}
+
+// MARK: Public methods
+
+// Save saves the checkpoint to the object with the key `Prefix + name + ".pb"`.
+func (s S3) Save(name string, checkpoint *genetics.Checkpoint) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+
+	data, err := encode(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	response, err := s.do(http.MethodPut, name, data)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	return responseError(response, http.StatusOK)
+}
+
+// Load loads the checkpoint from the object with the key
+// `Prefix + name + ".pb"`.
+func (s S3) Load(name string) (*genetics.Checkpoint, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+
+	response, err := s.do(http.MethodGet, name, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if err = responseError(response, http.StatusOK); err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	return decode(data)
+}
+
+// Delete deletes the object with the key `Prefix + name + ".pb"`.
+func (s S3) Delete(name string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+
+	response, err := s.do(http.MethodDelete, name, nil)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return responseError(response, http.StatusOK, http.StatusNoContent)
+}
+
+// MARK: Private methods
+
+// do sends a signed request for the named checkpoint's object.
+func (s S3) do(method string, name string, body []byte) (*http.Response, error) {
+	path := "/" + s.Bucket + "/" + s.Prefix + name + ".pb"
+	request, err := http.NewRequest(method, strings.TrimSuffix(s.Endpoint, "/")+escapePath(path), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	if s.SessionToken != "" {
+		request.Header.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+	s.sign(request, body, time.Now())
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(request)
+}
+
+// sign signs the request with AWS signature version 4.
+func (s S3) sign(request *http.Request, body []byte, now time.Time) {
+	payloadHash := sha256Hex(body)
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+
+	request.Header.Set("X-Amz-Date", amzDate)
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": request.URL.Host}
+	for key, values := range request.Header {
+		headers[strings.ToLower(key)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		request.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	request.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", s.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// MARK: Private functions
+
+// responseError returns an error describing the response if its status code
+// isn't one of the expected codes.
+func responseError(response *http.Response, expected ...int) error {
+	for _, code := range expected {
+		if response.StatusCode == code {
+			return nil
+		}
+	}
+
+	message, _ := ioutil.ReadAll(response.Body)
+	return fmt.Errorf("store: unexpected response %q: %s", response.Status, bytes.TrimSpace(message))
+}
+
+// escapePath returns the path with every byte other than unreserved
+// characters and slashes percent-encoded, as required by S3.
+func escapePath(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-._~/", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// sha256Hex returns the hexadecimal SHA-256 hash of the data.
+func sha256Hex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of the message with the key.
+func hmacSHA256(key []byte, message string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(message))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,104 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+
+	genetics "github.com/colinc86/go-genetics"
+)
+
+// SQL types store checkpoints in a table of a SQL database. The table must
+// have a unique text column `name` and a binary column `data`, for example:
+//
+//	CREATE TABLE checkpoints (name VARCHAR(255) PRIMARY KEY, data BLOB NOT NULL)
+//
+// Any database/sql driver may be used.
+type SQL struct {
+	// The database that checkpoints are stored in.
+	DB *sql.DB
+
+	// The name of the table that checkpoints are stored in.
+	Table string
+
+	// An optional function that returns the driver's placeholder for the n-th
+	// parameter of a statement, starting from one. For example, PostgreSQL
+	// drivers use "$1". If nil, then "?" is used.
+	Placeholder func(n int) string
+}
+
+// MARK: Constructors
+
+// NewSQL creates and returns a new store of checkpoints in the table of the
+// database.
+func NewSQL(db *sql.DB, table string) *SQL {
+	return &SQL{
+		DB:    db,
+		Table: table,
+	}
+}
+
+// MARK: Public methods
+
+// Save saves the checkpoint to the table's row with the given name.
+func (s SQL) Save(name string, checkpoint *genetics.Checkpoint) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+
+	data, err := encode(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE name = %s", s.Table, s.placeholder(1)), name); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err = tx.Exec(fmt.Sprintf("INSERT INTO %s (name, data) VALUES (%s, %s)", s.Table, s.placeholder(1), s.placeholder(2)), name, data); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// Load loads the checkpoint from the table's row with the given name.
+func (s SQL) Load(name string) (*genetics.Checkpoint, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	err := s.DB.QueryRow(fmt.Sprintf("SELECT data FROM %s WHERE name = %s", s.Table, s.placeholder(1)), name).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return decode(data)
+}
+
+// Delete deletes the table's row with the given name.
+func (s SQL) Delete(name string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+
+	_, err := s.DB.Exec(fmt.Sprintf("DELETE FROM %s WHERE name = %s", s.Table, s.placeholder(1)), name)
+	return err
+}
+
+// MARK: Private methods
+
+// placeholder returns the placeholder of the n-th parameter of a statement.
+func (s SQL) placeholder(n int) string {
+	if s.Placeholder == nil {
+		return "?"
+	}
+	return s.Placeholder(n)
+}
@@ -0,0 +1,61 @@
+// Package store persists the checkpoints of long-running evolutions.
+//
+// Checkpoints are saved to a `Store` by name. The package provides stores
+// backed by a directory, by an S3-compatible object storage service, and by a
+// SQL database. Every store encodes checkpoints as the `Checkpoint` protobuf
+// message of the genetics package, so they can be moved between stores and
+// decoded by other languages.
+package store
+
+import (
+	"errors"
+	"strings"
+
+	genetics "github.com/colinc86/go-genetics"
+)
+
+// ErrNotFound is returned when loading a checkpoint that doesn't exist.
+var ErrNotFound = errors.New("store: checkpoint not found")
+
+// ErrInvalidName is returned when a checkpoint's name is empty or contains a
+// path separator.
+var ErrInvalidName = errors.New("store: invalid checkpoint name")
+
+// Store types save and load checkpoints by name.
+type Store interface {
+	// Save saves the checkpoint, replacing any checkpoint with the same name.
+	Save(name string, checkpoint *genetics.Checkpoint) error
+
+	// Load loads the named checkpoint. Returns `ErrNotFound` if it doesn't
+	// exist.
+	Load(name string) (*genetics.Checkpoint, error)
+
+	// Delete deletes the named checkpoint. Deleting a checkpoint that doesn't
+	// exist isn't an error.
+	Delete(name string) error
+}
+
+// MARK: Private functions
+
+// validateName returns an error if the name can't be used as a checkpoint's
+// name.
+func validateName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return ErrInvalidName
+	}
+	return nil
+}
+
+// encode returns the protobuf encoding of the checkpoint.
+func encode(checkpoint *genetics.Checkpoint) ([]byte, error) {
+	return checkpoint.MarshalProto()
+}
+
+// decode returns the checkpoint decoded from its protobuf encoding.
+func decode(data []byte) (*genetics.Checkpoint, error) {
+	checkpoint := &genetics.Checkpoint{}
+	if err := checkpoint.UnmarshalProto(data); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
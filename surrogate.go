@@ -0,0 +1,398 @@
+package genetics
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// The regularization added to the kernel of radial basis function surrogate
+// models to keep it positive definite.
+const surrogateRegularization = 1e-6
+
+// SurrogateModel types approximate a fitness function from evaluated samples.
+type SurrogateModel interface {
+	// Fit fits the model to the genes and fitnesses of evaluated chromosomes.
+	Fit(genes [][]float64, fitnesses []float64)
+
+	// Predict returns the predicted fitness of the genes.
+	Predict(genes []float64) float64
+}
+
+// UncertainSurrogateModel types are surrogate models that also estimate the
+// uncertainty of their predictions.
+type UncertainSurrogateModel interface {
+	SurrogateModel
+
+	// PredictWithUncertainty returns the predicted fitness of the genes and the
+	// standard deviation of the prediction.
+	PredictWithUncertainty(genes []float64) (float64, float64)
+}
+
+// SurrogateAcquisitionType represents how a surrogate ranks the chromosomes
+// that it screens.
+type SurrogateAcquisitionType uint
+
+// Types of surrogate acquisition.
+const (
+	// Chromosomes are ranked by their predicted fitness.
+	SurrogateAcquisitionTypeMean SurrogateAcquisitionType = 0
+
+	// Chromosomes are ranked by their expected improvement over the best
+	// sampled fitness. Uncertainty is only considered if the model implements
+	// `UncertainSurrogateModel`.
+	SurrogateAcquisitionTypeExpectedImprovement SurrogateAcquisitionType = 1
+)
+
+// Surrogate objects pre-screen bred chromosomes with a surrogate model so that
+// only the most promising are evaluated by an evolver's fitness function. The
+// remaining chromosomes are assigned their predicted fitness. Chromosomes with
+// predicted fitness are screened again each generation that they survive, so
+// promising elites are eventually evaluated.
+type Surrogate struct {
+	// The model fitted to the evaluated chromosomes.
+	Model SurrogateModel
+
+	// The fraction of screened chromosomes, with the highest predicted fitness,
+	// that are evaluated by the fitness function.
+	EvaluationRate float64
+
+	// The number of evaluated samples required before screening begins.
+	MinimumSamples int
+
+	// The maximum number of samples that the model is fitted to. The most recent
+	// samples are kept.
+	MaximumSamples int
+
+	// How the chromosomes being screened are ranked.
+	Acquisition SurrogateAcquisitionType
+
+	// The improvement over the best sampled fitness that expected improvement is
+	// measured from. Larger values favour uncertain predictions over those
+	// predicted to be fit.
+	Exploration float64
+
+	genes       [][]float64
+	fitnesses   []float64
+	predictions int
+}
+
+// KNNSurrogateModel types predict fitness by inverse distance weighting of the
+// fitness of the nearest samples.
+type KNNSurrogateModel struct {
+	// The number of nearest samples used for each prediction.
+	K int
+
+	genes     [][]float64
+	fitnesses []float64
+}
+
+// RBFSurrogateModel types predict fitness by interpolating samples with
+// gaussian radial basis functions.
+type RBFSurrogateModel struct {
+	// The width of each radial basis function.
+	Width float64
+
+	genes    [][]float64
+	weights  []float64
+	mean     float64
+	variance float64
+	cholesky *mat.Cholesky
+}
+
+// MARK: Constructors
+
+// NewSurrogate creates and returns a new surrogate that evaluates the given
+// fraction of screened chromosomes.
+func NewSurrogate(model SurrogateModel, evaluationRate float64) *Surrogate {
+	return &Surrogate{
+		Model:          model,
+		EvaluationRate: evaluationRate,
+		MinimumSamples: 20,
+		MaximumSamples: 500,
+	}
+}
+
+// NewKNNSurrogateModel creates and returns a new k-nearest neighbours
+// surrogate model.
+func NewKNNSurrogateModel(k int) *KNNSurrogateModel {
+	return &KNNSurrogateModel{K: k}
+}
+
+// NewRBFSurrogateModel creates and returns a new radial basis function
+// surrogate model.
+func NewRBFSurrogateModel(width float64) *RBFSurrogateModel {
+	return &RBFSurrogateModel{Width: width}
+}
+
+// MARK: Public methods
+
+// Samples returns the number of samples that the model is fitted to.
+func (s *Surrogate) Samples() int {
+	return len(s.fitnesses)
+}
+
+// Predictions returns the number of chromosomes that have been assigned a
+// predicted fitness instead of being evaluated.
+func (s *Surrogate) Predictions() int {
+	return s.predictions
+}
+
+// Fit fits the model to the samples.
+func (m *KNNSurrogateModel) Fit(genes [][]float64, fitnesses []float64) {
+	m.genes = genes
+	m.fitnesses = fitnesses
+}
+
+// Predict returns the inverse distance weighted fitness of the `K` samples
+// nearest to the genes.
+func (m *KNNSurrogateModel) Predict(genes []float64) float64 {
+	if len(m.genes) == 0 {
+		return 0.0
+	}
+
+	sum, total := 0.0, 0.0
+	for _, i := range m.nearest(genes) {
+		distance := floats.Distance(genes, m.genes[i], 2.0)
+		if distance == 0.0 {
+			return m.fitnesses[i]
+		}
+		sum += m.fitnesses[i] / distance
+		total += 1.0 / distance
+	}
+	return sum / total
+}
+
+// PredictWithUncertainty returns the inverse distance weighted fitness of the
+// `K` samples nearest to the genes and the weighted standard deviation of their
+// fitness.
+func (m *KNNSurrogateModel) PredictWithUncertainty(genes []float64) (float64, float64) {
+	mean := m.Predict(genes)
+	neighbours := m.nearest(genes)
+
+	sum, total := 0.0, 0.0
+	for _, i := range neighbours {
+		distance := floats.Distance(genes, m.genes[i], 2.0)
+		if distance == 0.0 {
+			return mean, 0.0
+		}
+		sum += (m.fitnesses[i] - mean) * (m.fitnesses[i] - mean) / distance
+		total += 1.0 / distance
+	}
+
+	if total == 0.0 {
+		return mean, 0.0
+	}
+	return mean, math.Sqrt(sum / total)
+}
+
+// Fit fits the model to the samples by solving for the weights of the radial
+// basis functions centred on each sample.
+func (m *RBFSurrogateModel) Fit(genes [][]float64, fitnesses []float64) {
+	m.genes = genes
+	m.weights = nil
+	m.mean = 0.0
+	m.variance = 0.0
+	m.cholesky = nil
+	if len(genes) == 0 {
+		return
+	}
+
+	m.mean = floats.Sum(fitnesses) / float64(len(fitnesses))
+	for _, f := range fitnesses {
+		m.variance += (f - m.mean) * (f - m.mean)
+	}
+	m.variance /= float64(len(fitnesses))
+	kernel := mat.NewSymDense(len(genes), nil)
+	for i := range genes {
+		for j := i; j < len(genes); j++ {
+			value := m.basis(floats.Distance(genes[i], genes[j], 2.0))
+			if i == j {
+				value += surrogateRegularization
+			}
+			kernel.SetSym(i, j, value)
+		}
+	}
+
+	residuals := make([]float64, len(fitnesses))
+	for i, f := range fitnesses {
+		residuals[i] = f - m.mean
+	}
+
+	var cholesky mat.Cholesky
+	if !cholesky.Factorize(kernel) {
+		return
+	}
+
+	weights := mat.NewVecDense(len(genes), nil)
+	if err := cholesky.SolveVecTo(weights, mat.NewVecDense(len(residuals), residuals)); err != nil {
+		return
+	}
+	m.weights = weights.RawVector().Data
+	m.cholesky = &cholesky
+}
+
+// Predict returns the interpolated fitness of the genes. If the model couldn't
+// be fitted, then the mean fitness of the samples is returned.
+func (m *RBFSurrogateModel) Predict(genes []float64) float64 {
+	prediction := m.mean
+	for i, w := range m.weights {
+		prediction += w * m.basis(floats.Distance(genes, m.genes[i], 2.0))
+	}
+	return prediction
+}
+
+// PredictWithUncertainty returns the interpolated fitness of the genes and the
+// standard deviation of the prediction, which is the variance of the samples'
+// fitness not explained by the nearby samples as in gaussian process
+// regression.
+func (m *RBFSurrogateModel) PredictWithUncertainty(genes []float64) (float64, float64) {
+	mean := m.Predict(genes)
+	if m.cholesky == nil {
+		return mean, math.Sqrt(m.variance)
+	}
+
+	basis := make([]float64, len(m.genes))
+	for i, g := range m.genes {
+		basis[i] = m.basis(floats.Distance(genes, g, 2.0))
+	}
+
+	k := mat.NewVecDense(len(basis), basis)
+	solved := mat.NewVecDense(len(basis), nil)
+	if err := m.cholesky.SolveVecTo(solved, k); err != nil {
+		return mean, math.Sqrt(m.variance)
+	}
+
+	explained := mat.Dot(k, solved)
+	return mean, math.Sqrt(m.variance * math.Max(0.0, 1.0-explained))
+}
+
+// MARK: Private methods
+
+// screen predicts the fitness of the population's chromosomes that haven't
+// been evaluated by the fitness function, and assigns the predicted fitness to
+// all but the most promising of them. Does nothing if the surrogate is nil or
+// doesn't have enough samples.
+func (s *Surrogate) screen(population Population) {
+	if s == nil || len(s.fitnesses) < s.MinimumSamples {
+		return
+	}
+
+	var candidates []*Chromosome
+	for _, c := range population {
+		if !c.evaluated || c.estimated {
+			candidates = append(candidates, c)
+		}
+	}
+
+	best := floats.Max(s.fitnesses)
+	predictions := make(map[*Chromosome]float64, len(candidates))
+	acquisitions := make(map[*Chromosome]float64, len(candidates))
+	for _, c := range candidates {
+		predictions[c], acquisitions[c] = s.acquire(c.Genes, best)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return acquisitions[candidates[i]] > acquisitions[candidates[j]]
+	})
+
+	evaluated := int(math.Ceil(s.EvaluationRate * float64(len(candidates))))
+	for i, c := range candidates {
+		if i < evaluated {
+			c.evaluated = false
+			c.estimated = false
+			continue
+		}
+
+		c.Fitness = predictions[c]
+		c.evaluated = true
+		c.estimated = true
+		s.predictions++
+	}
+}
+
+// acquire returns the predicted fitness of the genes and the value used to
+// rank them given the best sampled fitness.
+func (s *Surrogate) acquire(genes []float64, best float64) (float64, float64) {
+	if s.Acquisition != SurrogateAcquisitionTypeExpectedImprovement {
+		prediction := s.Model.Predict(genes)
+		return prediction, prediction
+	}
+
+	mean, deviation := s.Model.Predict(genes), 0.0
+	if model, ok := s.Model.(UncertainSurrogateModel); ok {
+		mean, deviation = model.PredictWithUncertainty(genes)
+	}
+	return mean, expectedImprovement(mean, deviation, best+s.Exploration)
+}
+
+// update adds the evaluated chromosomes to the samples and refits the model.
+// Does nothing if the surrogate is nil.
+func (s *Surrogate) update(evaluated []*Chromosome) {
+	if s == nil || len(evaluated) == 0 {
+		return
+	}
+
+	for _, c := range evaluated {
+		if isInvalidFitness(c.Fitness) {
+			continue
+		}
+
+		genes := make([]float64, len(c.Genes))
+		copy(genes, c.Genes)
+		s.genes = append(s.genes, genes)
+		s.fitnesses = append(s.fitnesses, c.Fitness)
+	}
+
+	if s.MaximumSamples > 0 && len(s.fitnesses) > s.MaximumSamples {
+		excess := len(s.fitnesses) - s.MaximumSamples
+		s.genes = append([][]float64(nil), s.genes[excess:]...)
+		s.fitnesses = append([]float64(nil), s.fitnesses[excess:]...)
+	}
+
+	s.Model.Fit(s.genes, s.fitnesses)
+}
+
+// nearest returns the indexes of the `K` samples nearest to the genes.
+func (m *KNNSurrogateModel) nearest(genes []float64) []int {
+	indexes := make([]int, len(m.genes))
+	distances := make([]float64, len(m.genes))
+	for i, g := range m.genes {
+		indexes[i] = i
+		distances[i] = floats.Distance(genes, g, 2.0)
+	}
+
+	sort.Slice(indexes, func(i, j int) bool {
+		return distances[indexes[i]] < distances[indexes[j]]
+	})
+
+	k := m.K
+	if k < 1 || k > len(indexes) {
+		k = len(indexes)
+	}
+	return indexes[:k]
+}
+
+// basis returns the value of the radial basis function at the given distance.
+func (m *RBFSurrogateModel) basis(distance float64) float64 {
+	r := distance / m.Width
+	return math.Exp(-r * r)
+}
+
+// MARK: Private functions
+
+// expectedImprovement returns the expected improvement over the target of a
+// normally distributed prediction.
+func expectedImprovement(mean float64, deviation float64, target float64) float64 {
+	improvement := mean - target
+	if deviation <= 0.0 {
+		return math.Max(0.0, improvement)
+	}
+
+	z := improvement / deviation
+	cdf := 0.5 * (1.0 + math.Erf(z/math.Sqrt2))
+	pdf := math.Exp(-0.5*z*z) / math.Sqrt(2.0*math.Pi)
+	return improvement*cdf + deviation*pdf
+}
@@ -0,0 +1,151 @@
+package genetics
+
+import "fmt"
+
+// WindowedFitnessFunction defines a fitness function that evaluates a
+// chromosome on the data points in the window [start, end) of a series, such
+// as the bars of a price chart.
+type WindowedFitnessFunction func(chromosome *Chromosome, start int, end int, state *EvolutionState) float64
+
+// WalkForwardOptimizer types perform walk-forward optimization of a series of
+// data points. A population is evolved on a training window of the series,
+// its best chromosome is validated on the subsequent out-of-sample window, and
+// both windows roll forward until the series is exhausted. Comparing in-sample
+// with out-of-sample fitness detects chromosomes that overfit their training
+// data.
+//
+// The population is carried from one training window to the next, so each
+// window's evolution is warm-started from the previous window's. The evolver's
+// seeds are only seeded in to the first window, and closing its stop channel
+// stops the optimization after the current window.
+type WalkForwardOptimizer struct {
+	// The evolver used to evolve each training window. Its fitness function is
+	// replaced by the windowed fitness function.
+	Evolver *Evolver
+
+	// The fitness function that evaluates chromosomes on a window of the series.
+	FitnessFunction WindowedFitnessFunction
+
+	// The number of data points in the series.
+	SeriesLength int
+
+	// The number of data points in each training and validation window.
+	TrainingLength   int
+	ValidationLength int
+
+	// The number of data points that the windows roll forward by. If zero, then
+	// the validation length is used so that validation windows don't overlap.
+	StepLength int
+
+	// The number of generations evolved on each training window.
+	Generations int
+}
+
+// WalkForwardWindow objects describe the optimization of a single training
+// window and its validation.
+type WalkForwardWindow struct {
+	// The bounds of the training and validation windows.
+	TrainingStart   int
+	TrainingEnd     int
+	ValidationStart int
+	ValidationEnd   int
+
+	// The best chromosome of the training window.
+	Best *Chromosome
+
+	// The best chromosome's fitness on the training and validation windows.
+	InSampleFitness    float64
+	OutOfSampleFitness float64
+}
+
+// WalkForwardResult objects describe a walk-forward optimization.
+type WalkForwardResult struct {
+	// Each of the optimization's windows.
+	Windows []WalkForwardWindow
+
+	// The mean in-sample and out-of-sample fitness of the windows.
+	InSampleFitness    float64
+	OutOfSampleFitness float64
+}
+
+// MARK: Constructors
+
+// NewWalkForwardOptimizer creates and returns a new walk-forward optimizer.
+func NewWalkForwardOptimizer(evolver *Evolver, fitnessFunction WindowedFitnessFunction, seriesLength int, trainingLength int, validationLength int, generations int) *WalkForwardOptimizer {
+	return &WalkForwardOptimizer{
+		Evolver:          evolver,
+		FitnessFunction:  fitnessFunction,
+		SeriesLength:     seriesLength,
+		TrainingLength:   trainingLength,
+		ValidationLength: validationLength,
+		Generations:      generations,
+	}
+}
+
+// MARK: Public methods
+
+// Optimize performs walk-forward optimization starting from the population
+// and returns the result of each window.
+func (o WalkForwardOptimizer) Optimize(population Population) (*WalkForwardResult, error) {
+	if o.TrainingLength <= 0 || o.ValidationLength <= 0 {
+		return nil, fmt.Errorf("the training and validation lengths must be positive")
+	}
+
+	if o.TrainingLength+o.ValidationLength > o.SeriesLength {
+		return nil, fmt.Errorf("the series of length %d is shorter than a training and validation window", o.SeriesLength)
+	}
+
+	step := o.StepLength
+	if step <= 0 {
+		step = o.ValidationLength
+	}
+
+	result := &WalkForwardResult{}
+	for start := 0; start+o.TrainingLength+o.ValidationLength <= o.SeriesLength && !o.Evolver.stopped(); start += step {
+		window := WalkForwardWindow{
+			TrainingStart:   start,
+			TrainingEnd:     start + o.TrainingLength,
+			ValidationStart: start + o.TrainingLength,
+			ValidationEnd:   start + o.TrainingLength + o.ValidationLength,
+		}
+
+		evolver := *o.Evolver
+		if len(result.Windows) > 0 {
+			evolver.Seeds = nil
+		}
+		evolver.FitnessFunction = func(chromosome *Chromosome, state *EvolutionState) float64 {
+			return o.FitnessFunction(chromosome, window.TrainingStart, window.TrainingEnd, state)
+		}
+
+		for _, c := range population {
+			c.evaluated = false
+		}
+
+		var best *Chromosome
+		var err error
+		var state *EvolutionState
+		population, best, err = evolver.Evolve(population, func(s *EvolutionState) bool {
+			state = s
+			return s.Generation < o.Generations
+		})
+		if err != nil {
+			return result, fmt.Errorf("window %d: %w", len(result.Windows), err)
+		}
+		if best == nil {
+			return result, fmt.Errorf("the population is empty")
+		}
+
+		window.Best = best.Clone()
+		window.InSampleFitness = best.Fitness
+		window.OutOfSampleFitness = o.FitnessFunction(window.Best, window.ValidationStart, window.ValidationEnd, state)
+		result.Windows = append(result.Windows, window)
+	}
+
+	for _, w := range result.Windows {
+		result.InSampleFitness += w.InSampleFitness
+		result.OutOfSampleFitness += w.OutOfSampleFitness
+	}
+	result.InSampleFitness /= float64(len(result.Windows))
+	result.OutOfSampleFitness /= float64(len(result.Windows))
+	return result, nil
+}
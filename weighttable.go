@@ -0,0 +1,124 @@
+package genetics
+
+import "math/rand"
+
+// weightTable objects contain the alias table of the selection weights of a
+// population so that chromosomes can be repeatedly selected in proportion to
+// their weights in constant time using Vose's alias method.
+type weightTable struct {
+	// The chromosomes of the population at the time the table was built.
+	chromosomes Population
+
+	// The probability that each chromosome is selected when its column of the
+	// table is chosen, otherwise its alias is selected.
+	probabilities []float64
+	aliases       []int
+
+	// Whether or not every weight is zero, in which case chromosomes are
+	// selected uniformly.
+	uniform bool
+
+	// Buffers of the indexes of the columns with less and more than the mean
+	// weight.
+	small []int
+	large []int
+
+	// The selection function and the population that it selects from, used in
+	// place of the table by selection methods without weights, and the context
+	// of the selections, whose source of random numbers the table draws from.
+	function   SelectionMethodFunction
+	population Population
+	context    SelectionContext
+
+	// The table's `selectChromosome` method, bound once so that it can be
+	// returned each generation without allocating.
+	selector func() *Chromosome
+}
+
+// MARK: Private methods
+
+// reset rebuilds the table from the population and weights, reusing the
+// table's buffers. Weights that aren't positive are treated as zero.
+func (t *weightTable) reset(population Population, weights []float64) {
+	n := len(weights)
+	t.chromosomes = append(t.chromosomes[:0], population...)
+	t.probabilities = append(t.probabilities[:0], make([]float64, n)...)
+	t.aliases = append(t.aliases[:0], make([]int, n)...)
+	t.small = t.small[:0]
+	t.large = t.large[:0]
+
+	sum := 0.0
+	positive := -1
+	for i, w := range weights {
+		if w > 0.0 {
+			sum += w
+			positive = i
+		}
+	}
+
+	t.uniform = sum <= 0.0
+	if t.uniform {
+		return
+	}
+
+	for i, w := range weights {
+		if w > 0.0 {
+			t.probabilities[i] = w * float64(n) / sum
+		}
+
+		if t.probabilities[i] < 1.0 {
+			t.small = append(t.small, i)
+		} else {
+			t.large = append(t.large, i)
+		}
+	}
+
+	for len(t.small) > 0 && len(t.large) > 0 {
+		s := t.small[len(t.small)-1]
+		l := t.large[len(t.large)-1]
+		t.small = t.small[:len(t.small)-1]
+
+		t.aliases[s] = l
+		t.probabilities[l] -= 1.0 - t.probabilities[s]
+		if t.probabilities[l] < 1.0 {
+			t.large = t.large[:len(t.large)-1]
+			t.small = append(t.small, l)
+		}
+	}
+
+	// Rounding may leave columns unpaired. Their probability is one unless
+	// their weight is zero, in which case their alias is always selected.
+	for _, i := range append(t.small, t.large...) {
+		t.probabilities[i] = 1.0
+		if !(weights[i] > 0.0) {
+			t.probabilities[i] = 0.0
+			t.aliases[i] = positive
+		}
+	}
+}
+
+// selectChromosome selects a chromosome with a probability proportional to its
+// weight, drawing random numbers from the table's context. If the table has a
+// selection function, then the chromosome is selected by the function.
+func (t *weightTable) selectChromosome() *Chromosome {
+	if t.function != nil {
+		return t.function(t.population, t.context)
+	}
+	return t.sample(t.context.Source())
+}
+
+// sample selects a chromosome with a probability proportional to its weight,
+// drawing random numbers from the given source. If every weight is zero, then
+// a chromosome is selected uniformly. Returns nil if the table is empty.
+func (t *weightTable) sample(rng *rand.Rand) *Chromosome {
+	n := len(t.probabilities)
+	if n == 0 {
+		return nil
+	}
+
+	i := rng.Intn(n)
+	if t.uniform || rng.Float64() < t.probabilities[i] {
+		return t.chromosomes[i]
+	}
+	return t.chromosomes[t.aliases[i]]
+}